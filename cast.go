@@ -0,0 +1,367 @@
+package qframe
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/tobgu/qframe/config/cast"
+	"github.com/tobgu/qframe/errors"
+	"github.com/tobgu/qframe/internal/bcolumn"
+	"github.com/tobgu/qframe/internal/bitmap"
+	"github.com/tobgu/qframe/internal/column"
+	"github.com/tobgu/qframe/internal/ecolumn"
+	"github.com/tobgu/qframe/internal/fcolumn"
+	"github.com/tobgu/qframe/internal/icolumn"
+	"github.com/tobgu/qframe/internal/index"
+	"github.com/tobgu/qframe/internal/scolumn"
+	"github.com/tobgu/qframe/internal/tcolumn"
+	"github.com/tobgu/qframe/types"
+)
+
+// Cast converts the column colName to dataType. Null values remain null.
+//
+// The following conversions are performed:
+//
+//	int -> float, bool (0 is false), string, enum, time (nanoseconds since the Unix epoch)
+//	float -> int (truncated), bool (0 is false), string, enum
+//	bool -> int (1 or 0), float (1 or 0), string, enum
+//	string, enum -> int, float, bool and time by parsing the strings, string and enum
+//	time -> int (nanoseconds since the Unix epoch), float (seconds since the Unix epoch), string, enum
+//
+// Strings that cannot be parsed, and values missing from cast.EnumValues, produce an
+// error unless cast.Lenient is set. Casting a column to its current type is a no-op,
+// except for enums where the values may be changed using cast.EnumValues.
+//
+// Time complexity O(n) where n = number of rows.
+func (qf QFrame) Cast(colName string, dataType types.DataType, configFns ...cast.ConfigFunc) QFrame {
+	if qf.Err != nil {
+		return qf
+	}
+
+	namedColumn, ok := qf.columnsByName[colName]
+	if !ok {
+		return qf.withErr(errors.New("Cast", unknownCol(colName)))
+	}
+
+	if namedColumn.DataType() == dataType && dataType != types.Enum {
+		return qf
+	}
+
+	c := &caster{col: namedColumn.Column, index: qf.index, size: namedColumn.Len(), conf: cast.NewConfig(configFns)}
+	var result column.Column
+	var err error
+	switch dataType {
+	case types.Int:
+		result, err = c.toInt()
+	case types.Float:
+		result, err = c.toFloat()
+	case types.Bool:
+		result, err = c.toBool()
+	case types.String:
+		result = scolumn.New(c.strings())
+	case types.Enum:
+		result, err = c.toEnum()
+	case types.Time:
+		result, err = c.toTime()
+	default:
+		err = errors.New("Cast", "unknown data type: %s", dataType)
+	}
+
+	if err != nil {
+		return qf.withErr(errors.Propagate("Cast", err))
+	}
+
+	return qf.setColumn(colName, result)
+}
+
+// caster converts the elements of col referenced by index. Converted values are
+// stored at the same positions in the new column as in col.
+type caster struct {
+	col   column.Column
+	index index.Int
+	size  int
+	conf  cast.Config
+	valid bitmap.Bitmap
+}
+
+func (c *caster) setNull(k int) {
+	if c.valid == nil {
+		c.valid = bitmap.New(c.size, true)
+	}
+	c.valid.SetNull(c.index[k])
+}
+
+// invalid reports a value that could not be cast, it results in an error unless lenient.
+func (c *caster) invalid(k int, value string, dataType types.DataType) error {
+	if c.conf.Lenient {
+		c.setNull(k)
+		return nil
+	}
+
+	return errors.New("cast", `cannot cast "%s" at row %d to %s`, value, k, dataType)
+}
+
+func (c *caster) unsupported(dataType types.DataType) error {
+	return errors.New("cast", "cannot cast %s to %s", c.col.DataType(), dataType)
+}
+
+// stringAt returns the string representation of the element at position k in the index, nil for null.
+func (c *caster) stringAt(k int, isNull func(int) bool) *string {
+	if isNull(k) {
+		return nil
+	}
+
+	s := c.col.StringAt(c.index[k], "")
+	return &s
+}
+
+func (c *caster) strings() []*string {
+	result := make([]*string, c.size)
+	switch col := c.col.(type) {
+	case scolumn.Column:
+		view := col.View(c.index)
+		for k := range c.index {
+			result[c.index[k]] = view.ItemAt(k)
+		}
+	case ecolumn.Column:
+		view := col.View(c.index)
+		for k := range c.index {
+			result[c.index[k]] = view.ItemAt(k)
+		}
+	case tcolumn.Column:
+		view := col.View(c.index)
+		for k := range c.index {
+			if !view.IsNull(k) {
+				s := view.ItemAt(k).Format(c.conf.TimeLayout)
+				result[c.index[k]] = &s
+			}
+		}
+	case icolumn.Column:
+		view := col.View(c.index)
+		for k := range c.index {
+			result[c.index[k]] = c.stringAt(k, view.IsNull)
+		}
+	case fcolumn.Column:
+		view := col.View(c.index)
+		for k := range c.index {
+			result[c.index[k]] = c.stringAt(k, view.IsNull)
+		}
+	case bcolumn.Column:
+		view := col.View(c.index)
+		for k := range c.index {
+			result[c.index[k]] = c.stringAt(k, view.IsNull)
+		}
+	}
+	return result
+}
+
+// parse applies fn to the string value of each non null element.
+func (c *caster) parse(dataType types.DataType, fn func(pos uint32, s string) error) error {
+	strs := c.strings()
+	for k, pos := range c.index {
+		s := strs[pos]
+		if s == nil {
+			c.setNull(k)
+			continue
+		}
+
+		if err := fn(pos, *s); err != nil {
+			if err := c.invalid(k, *s, dataType); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *caster) toInt() (column.Column, error) {
+	data := make([]int, c.size)
+	switch col := c.col.(type) {
+	case fcolumn.Column:
+		view := col.View(c.index)
+		for k, pos := range c.index {
+			if view.IsNull(k) {
+				c.setNull(k)
+			} else {
+				data[pos] = int(view.ItemAt(k))
+			}
+		}
+	case bcolumn.Column:
+		view := col.View(c.index)
+		for k, pos := range c.index {
+			if view.IsNull(k) {
+				c.setNull(k)
+			} else if view.ItemAt(k) {
+				data[pos] = 1
+			}
+		}
+	case tcolumn.Column:
+		view := col.View(c.index)
+		for k, pos := range c.index {
+			if view.IsNull(k) {
+				c.setNull(k)
+			} else {
+				data[pos] = int(view.ItemAt(k).UnixNano())
+			}
+		}
+	case scolumn.Column, ecolumn.Column:
+		err := c.parse(types.Int, func(pos uint32, s string) error {
+			x, err := strconv.Atoi(s)
+			data[pos] = x
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, c.unsupported(types.Int)
+	}
+
+	return icolumn.NewNullable(data, c.valid), nil
+}
+
+func (c *caster) toFloat() (column.Column, error) {
+	data := make([]float64, c.size)
+	for i := range data {
+		data[i] = math.NaN()
+	}
+
+	switch col := c.col.(type) {
+	case icolumn.Column:
+		view := col.View(c.index)
+		for k, pos := range c.index {
+			if !view.IsNull(k) {
+				data[pos] = float64(view.ItemAt(k))
+			}
+		}
+	case bcolumn.Column:
+		view := col.View(c.index)
+		for k, pos := range c.index {
+			if !view.IsNull(k) {
+				data[pos] = 0
+				if view.ItemAt(k) {
+					data[pos] = 1
+				}
+			}
+		}
+	case tcolumn.Column:
+		view := col.View(c.index)
+		for k, pos := range c.index {
+			if !view.IsNull(k) {
+				data[pos] = float64(view.ItemAt(k).UnixNano()) / float64(time.Second)
+			}
+		}
+	case scolumn.Column, ecolumn.Column:
+		err := c.parse(types.Float, func(pos uint32, s string) error {
+			x, err := strconv.ParseFloat(s, 64)
+			if err == nil {
+				data[pos] = x
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, c.unsupported(types.Float)
+	}
+
+	// Nulls are represented by NaN in float columns
+	return fcolumn.New(data), nil
+}
+
+func (c *caster) toBool() (column.Column, error) {
+	data := make([]bool, c.size)
+	switch col := c.col.(type) {
+	case icolumn.Column:
+		view := col.View(c.index)
+		for k, pos := range c.index {
+			if view.IsNull(k) {
+				c.setNull(k)
+			} else {
+				data[pos] = view.ItemAt(k) != 0
+			}
+		}
+	case fcolumn.Column:
+		view := col.View(c.index)
+		for k, pos := range c.index {
+			if view.IsNull(k) {
+				c.setNull(k)
+			} else {
+				data[pos] = view.ItemAt(k) != 0
+			}
+		}
+	case scolumn.Column, ecolumn.Column:
+		err := c.parse(types.Bool, func(pos uint32, s string) error {
+			x, err := strconv.ParseBool(s)
+			data[pos] = x
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, c.unsupported(types.Bool)
+	}
+
+	return bcolumn.NewNullable(data, c.valid), nil
+}
+
+func (c *caster) toEnum() (column.Column, error) {
+	strs := c.strings()
+	if c.conf.EnumValues == nil {
+		return ecolumn.New(strs, nil)
+	}
+
+	values := make(map[string]struct{}, len(c.conf.EnumValues))
+	for _, v := range c.conf.EnumValues {
+		values[v] = struct{}{}
+	}
+
+	for k, pos := range c.index {
+		if s := strs[pos]; s != nil {
+			if _, ok := values[*s]; !ok {
+				if err := c.invalid(k, *s, types.Enum); err != nil {
+					return nil, err
+				}
+				strs[pos] = nil
+			}
+		}
+	}
+
+	return ecolumn.New(strs, c.conf.EnumValues)
+}
+
+func (c *caster) toTime() (column.Column, error) {
+	loc := c.conf.TimeLocation
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	data := make([]int64, c.size)
+	switch col := c.col.(type) {
+	case icolumn.Column:
+		view := col.View(c.index)
+		for k, pos := range c.index {
+			if view.IsNull(k) {
+				c.setNull(k)
+			} else {
+				data[pos] = int64(view.ItemAt(k))
+			}
+		}
+	case scolumn.Column, ecolumn.Column:
+		err := c.parse(types.Time, func(pos uint32, s string) error {
+			t, err := time.ParseInLocation(c.conf.TimeLayout, s, loc)
+			data[pos] = t.UnixNano()
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, c.unsupported(types.Time)
+	}
+
+	return tcolumn.NewNanos(data, c.valid, loc), nil
+}
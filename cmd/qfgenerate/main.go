@@ -14,6 +14,7 @@ import (
 	igenerator "github.com/tobgu/qframe/internal/icolumn"
 	qfgenerator "github.com/tobgu/qframe/internal/qframe/generator"
 	sgenerator "github.com/tobgu/qframe/internal/scolumn"
+	tgenerator "github.com/tobgu/qframe/internal/tcolumn"
 )
 
 /*
@@ -40,6 +41,8 @@ func main() {
 		"efilter": egenerator.GenerateFilters,
 		"sdoc":    sgenerator.GenerateDoc,
 		"sfilter": sgenerator.GenerateFilters,
+		"tdoc":    tgenerator.GenerateDoc,
+		"tfilter": tgenerator.GenerateFilters,
 		"qframe":  qfgenerator.GenerateQFrame,
 	}
 
@@ -0,0 +1,195 @@
+package qframe
+
+import (
+	"math"
+	"time"
+
+	"github.com/tobgu/qframe/errors"
+	"github.com/tobgu/qframe/internal/bcolumn"
+	"github.com/tobgu/qframe/internal/column"
+	"github.com/tobgu/qframe/internal/ecolumn"
+	"github.com/tobgu/qframe/internal/fcolumn"
+	"github.com/tobgu/qframe/internal/icolumn"
+	"github.com/tobgu/qframe/internal/index"
+	"github.com/tobgu/qframe/internal/scolumn"
+	qfstrings "github.com/tobgu/qframe/internal/strings"
+	"github.com/tobgu/qframe/internal/tcolumn"
+	"github.com/tobgu/qframe/types"
+)
+
+// Concat creates a new QFrame by stacking the rows of frames on top of each other, in order.
+//
+// All frames must contain the same columns with the same types. Columns are ordered
+// by their first appearance in frames. Enum columns with different sets of values
+// are allowed, the values are merged in the result. See ConcatFillMissing for
+// how to handle frames with different sets of columns.
+//
+// The resulting QFrame does not share any data with the input frames.
+//
+// Time complexity O(m * n) where m = number of columns, n = total number of rows.
+func Concat(frames ...QFrame) QFrame {
+	return concatFrames(frames, false)
+}
+
+// ConcatFillMissing works like Concat with the exception that columns missing from
+// some of the frames are allowed. Rows from frames without the column are null.
+func ConcatFillMissing(frames ...QFrame) QFrame {
+	return concatFrames(frames, true)
+}
+
+func concatFrames(frames []QFrame, fillMissing bool) QFrame {
+	for _, f := range frames {
+		if f.Err != nil {
+			return QFrame{Err: errors.Propagate("Concat", f.Err)}
+		}
+	}
+
+	// Column names in order of first appearance
+	names := make([]string, 0)
+	nameSet := qfstrings.NewStringSet(nil)
+	totalLen := 0
+	for _, f := range frames {
+		for _, c := range f.columns {
+			if !nameSet.Contains(c.name) {
+				nameSet.Add(c.name)
+				names = append(names, c.name)
+			}
+		}
+		totalLen += f.Len()
+	}
+
+	if !fillMissing {
+		for i, f := range frames {
+			for _, name := range names {
+				if !f.Contains(name) {
+					return QFrame{Err: errors.New("Concat", `column "%s" missing in frame %d`, name, i)}
+				}
+			}
+		}
+	}
+
+	newColumns := make([]namedColumn, len(names))
+	newColumnsByName := make(map[string]namedColumn, len(names))
+	for i, name := range names {
+		col, err := concatColumn(name, frames)
+		if err != nil {
+			return QFrame{Err: errors.Propagate("Concat", err)}
+		}
+
+		newColumns[i] = namedColumn{Column: col, name: name, pos: i}
+		newColumnsByName[name] = newColumns[i]
+	}
+
+	return QFrame{columns: newColumns, columnsByName: newColumnsByName, index: index.NewAscending(uint32(totalLen))}
+}
+
+func concatColumn(name string, frames []QFrame) (column.Column, error) {
+	parts := make([]column.Column, len(frames))
+	ixs := make([]index.Int, len(frames))
+	dataType := types.None
+	missing := false
+	for i, f := range frames {
+		nc, ok := f.columnsByName[name]
+		if !ok {
+			missing = true
+			continue
+		}
+
+		if dataType == types.None {
+			dataType = nc.DataType()
+		} else if dataType != nc.DataType() {
+			return nil, errors.New("concatColumn", `type mismatch for column "%s": %s != %s`, name, dataType, nc.DataType())
+		}
+
+		parts[i], ixs[i] = nc.Column, f.index
+	}
+
+	if missing {
+		if err := fillMissingParts(dataType, frames, parts, ixs); err != nil {
+			return nil, err
+		}
+	}
+
+	switch dataType {
+	case types.Int:
+		cols := make([]icolumn.Column, len(parts))
+		for i, p := range parts {
+			cols[i] = p.(icolumn.Column)
+		}
+		return icolumn.Concat(cols, ixs), nil
+	case types.Float:
+		cols := make([]fcolumn.Column, len(parts))
+		for i, p := range parts {
+			cols[i] = p.(fcolumn.Column)
+		}
+		return fcolumn.Concat(cols, ixs), nil
+	case types.Bool:
+		cols := make([]bcolumn.Column, len(parts))
+		for i, p := range parts {
+			cols[i] = p.(bcolumn.Column)
+		}
+		return bcolumn.Concat(cols, ixs), nil
+	case types.String:
+		cols := make([]scolumn.Column, len(parts))
+		for i, p := range parts {
+			cols[i] = p.(scolumn.Column)
+		}
+		return scolumn.Concat(cols, ixs), nil
+	case types.Enum:
+		cols := make([]ecolumn.Column, len(parts))
+		for i, p := range parts {
+			cols[i] = p.(ecolumn.Column)
+		}
+		return ecolumn.Concat(cols, ixs)
+	case types.Time:
+		cols := make([]tcolumn.Column, len(parts))
+		for i, p := range parts {
+			cols[i] = p.(tcolumn.Column)
+		}
+		return tcolumn.Concat(cols, ixs), nil
+	default:
+		return nil, errors.New("concatColumn", `unknown data type "%s" for column "%s"`, dataType, name)
+	}
+}
+
+// fillMissingParts replaces missing parts with null columns.
+func fillMissingParts(dataType types.DataType, frames []QFrame, parts []column.Column, ixs []index.Int) error {
+	for i, p := range parts {
+		if p != nil {
+			continue
+		}
+
+		count := frames[i].Len()
+		switch dataType {
+		case types.Int:
+			parts[i] = icolumn.NewNull(count)
+		case types.Float:
+			parts[i] = fcolumn.NewConst(math.NaN(), count)
+		case types.Bool:
+			parts[i] = bcolumn.NewNull(count)
+		case types.String:
+			parts[i] = scolumn.NewConst(nil, count)
+		case types.Enum:
+			c, err := ecolumn.NewConst(nil, count, nil)
+			if err != nil {
+				return err
+			}
+			parts[i] = c
+		case types.Time:
+			parts[i] = tcolumn.NewNull(count, timeLocation(parts))
+		}
+		ixs[i] = index.NewAscending(uint32(count))
+	}
+
+	return nil
+}
+
+// timeLocation returns the location of the first time column among parts.
+func timeLocation(parts []column.Column) *time.Location {
+	for _, p := range parts {
+		if c, ok := p.(tcolumn.Column); ok {
+			return c.Location()
+		}
+	}
+	return nil
+}
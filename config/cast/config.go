@@ -0,0 +1,64 @@
+package cast
+
+import "time"
+
+// Config holds configuration for casting columns in QFrames to other types.
+// It should be considered a private implementation detail and should never be
+// referenced or used directly outside of the QFrame code. To manipulate it
+// use the functions returning ConfigFunc below.
+type Config struct {
+	Lenient      bool
+	EnumValues   []string
+	TimeLayout   string
+	TimeLocation *time.Location
+}
+
+// ConfigFunc is a function that operates on a Config object.
+type ConfigFunc func(c *Config)
+
+// NewConfig creates a new Config object.
+// This function should never be called from outside QFrame.
+func NewConfig(configFns []ConfigFunc) Config {
+	config := Config{TimeLayout: time.RFC3339Nano, TimeLocation: time.UTC}
+	for _, f := range configFns {
+		f(&config)
+	}
+
+	return config
+}
+
+// Lenient configures how values that cannot be cast to the new type are handled.
+// If set to false (default) the cast fails with an error. If set to true such values
+// are turned into null.
+func Lenient(lenient bool) ConfigFunc {
+	return func(c *Config) {
+		c.Lenient = lenient
+	}
+}
+
+// EnumValues lists the possible values and internal order of these values when casting
+// to an enum column. If not set the values are derived from the content of the column
+// and the ordering is undefined. See Lenient for how values not in the list are handled.
+func EnumValues(values []string) ConfigFunc {
+	return func(c *Config) {
+		c.EnumValues = make([]string, len(values))
+		copy(c.EnumValues, values)
+	}
+}
+
+// TimeLayout configures the layout used when casting between strings and times, see the
+// time package for a description of layouts. Default is ISO-8601 (time.RFC3339Nano).
+func TimeLayout(layout string) ConfigFunc {
+	return func(c *Config) {
+		c.TimeLayout = layout
+	}
+}
+
+// TimeLocation configures the location of the time column produced when casting to time.
+// It is also used when parsing strings that do not contain any time zone information.
+// Default is UTC.
+func TimeLocation(loc *time.Location) ConfigFunc {
+	return func(c *Config) {
+		c.TimeLocation = loc
+	}
+}
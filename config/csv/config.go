@@ -1,6 +1,8 @@
 package csv
 
 import (
+	"time"
+
 	qfio "github.com/tobgu/qframe/internal/io"
 	"github.com/tobgu/qframe/types"
 )
@@ -17,7 +19,7 @@ type ConfigFunc func(*Config)
 // NewConfig creates a new Config object.
 // This function should never be called from outside QFrame.
 func NewConfig(ff []ConfigFunc) Config {
-	conf := Config{Delimiter: ','}
+	conf := Config{Delimiter: ',', TimeLayout: time.RFC3339Nano}
 	for _, f := range ff {
 		f(&conf)
 	}
@@ -83,3 +85,227 @@ func EnumValues(values map[string][]string) ConfigFunc {
 		}
 	}
 }
+
+// Rename is used to rename columns while reading. This can for example be used to
+// normalise column names given in the header of a file.
+//
+// columns - map column name in the CSV header -> new column name.
+//
+// Note that other options referring to columns by name, such as Types and EnumValues,
+// use the names from the header.
+func Rename(columns map[string]string) ConfigFunc {
+	return func(c *Config) {
+		c.Rename = make(map[string]string, len(columns))
+		for k, v := range columns {
+			c.Rename[k] = v
+		}
+	}
+}
+
+// AutoEnum configures columns that would otherwise be string columns to be read as enum
+// columns if they contain at most maxCardinality distinct values. Columns listed in Types
+// are not affected. The ordering between the values is undefined, see EnumValues.
+//
+// maxCardinality - The maximum number of distinct values of an enum column. 0 (default) disables
+// automatic enum detection.
+func AutoEnum(maxCardinality int) ConfigFunc {
+	return func(c *Config) {
+		c.AutoEnum = maxCardinality
+	}
+}
+
+// TimeLayout configures the layout used to parse time columns, see the time package
+// for a description of layouts. Default is ISO-8601 (time.RFC3339Nano).
+//
+// layout - The layout to use.
+//
+// Note that the column must be listed as having a time type (using Types above) for this option to take effect.
+func TimeLayout(layout string) ConfigFunc {
+	return func(c *Config) {
+		c.TimeLayout = layout
+	}
+}
+
+// TimeLocation configures the location of time columns. It is used when parsing values that
+// do not contain any time zone information and when presenting the values. Default is UTC.
+//
+// loc - The location to use.
+func TimeLocation(loc *time.Location) ConfigFunc {
+	return func(c *Config) {
+		c.TimeLocation = loc
+	}
+}
+
+// SkipRows configures a number of lines to skip at the start of the input, before
+// the header. This can for example be used to skip a preamble preceding the data.
+//
+// n - The number of lines to skip. Default is 0.
+func SkipRows(n int) ConfigFunc {
+	return func(c *Config) {
+		c.SkipRows = n
+	}
+}
+
+// CommentChar configures a character that marks a line as a comment when it is the
+// first character of the line. Comment lines are ignored.
+//
+// comment - The comment character. Default is 0 which disables comments.
+func CommentChar(comment byte) ConfigFunc {
+	return func(c *Config) {
+		c.CommentChar = comment
+	}
+}
+
+// Headers configures the column names for input without a header line. When set all
+// lines of the input are read as data.
+//
+// headers - The column names, in the order of the columns in the input.
+func Headers(headers []string) ConfigFunc {
+	return func(c *Config) {
+		c.Headers = make([]string, len(headers))
+		copy(c.Headers, headers)
+	}
+}
+
+// Columns configures a subset of the columns to read. Other columns are skipped
+// without being parsed. Reading fails if a column is not present in the input.
+//
+// columns - Names of the columns to read, the resulting QFrame has the columns in this order.
+func Columns(columns ...string) ConfigFunc {
+	return func(c *Config) {
+		c.Columns = make([]string, len(columns))
+		copy(c.Columns, columns)
+	}
+}
+
+// MaxRows configures the maximum number of rows to read, any remaining input is ignored.
+//
+// n - The maximum number of rows. Default is 0 which reads all rows.
+func MaxRows(n int) ConfigFunc {
+	return func(c *Config) {
+		c.MaxRows = n
+	}
+}
+
+// NullValues configures values that should be read as null, for example "NA" or "\N".
+// The values apply to columns of all types, both when inferring the type of a column
+// and when parsing it. Empty cells are always null in int, float, bool and time columns,
+// see EmptyNull for string and enum columns.
+//
+// values - The values to read as null.
+func NullValues(values []string) ConfigFunc {
+	return func(c *Config) {
+		c.NullValues = make([]string, len(values))
+		copy(c.NullValues, values)
+	}
+}
+
+// ColumnNullValues configures values that should be read as null in individual columns.
+// For the columns given these values are used instead of those configured with NullValues.
+//
+// values - map column name -> values to read as null.
+func ColumnNullValues(values map[string][]string) ConfigFunc {
+	return func(c *Config) {
+		c.ColumnNullValues = make(map[string][]string, len(values))
+		for k, v := range values {
+			c.ColumnNullValues[k] = v
+		}
+	}
+}
+
+// BoolValues configures additional spellings of true and false, such as "yes" and "no".
+// They are used both when inferring the type of a column and when parsing bool columns.
+// Matching is case sensitive.
+//
+// trueValues - Values to read as true.
+//
+// falseValues - Values to read as false.
+func BoolValues(trueValues, falseValues []string) ConfigFunc {
+	return func(c *Config) {
+		c.TrueValues = make([]string, len(trueValues))
+		copy(c.TrueValues, trueValues)
+		c.FalseValues = make([]string, len(falseValues))
+		copy(c.FalseValues, falseValues)
+	}
+}
+
+// ToConfig holds configuration for writing QFrames as CSV.
+// It should be considered a private implementation detail and should never be
+// referenced or used directly outside of the QFrame code. To manipulate it
+// use the functions returning ToConfigFunc below.
+type ToConfig qfio.CSVWriteConfig
+
+// ToConfigFunc is a function that operates on a ToConfig object.
+type ToConfigFunc func(*ToConfig)
+
+// NewToConfig creates a new ToConfig object.
+// This function should never be called from outside QFrame.
+func NewToConfig(ff []ToConfigFunc) ToConfig {
+	conf := ToConfig{Delimiter: ',', Header: true, FloatFormat: 'f', FloatPrec: -1}
+	for _, f := range ff {
+		f(&conf)
+	}
+	return conf
+}
+
+// WriteDelimiter configures the delimiter between fields when writing CSV. Default is ','.
+//
+// delimiter - The delimiter to use.
+func WriteDelimiter(delimiter byte) ToConfigFunc {
+	return func(c *ToConfig) {
+		c.Delimiter = delimiter
+	}
+}
+
+// NullRep configures how null values are written. Default is the empty string.
+// The null representation is never quoted, not even when using QuoteAll.
+//
+// rep - The string to write for null values.
+func NullRep(rep string) ToConfigFunc {
+	return func(c *ToConfig) {
+		c.NullRep = rep
+	}
+}
+
+// Header configures if a header with the column names should be written (default) or not.
+//
+// header - If set to false no header is written.
+func Header(header bool) ToConfigFunc {
+	return func(c *ToConfig) {
+		c.Header = header
+	}
+}
+
+// QuoteAll configures if all fields should be quoted or only those that require
+// quoting (default), for example fields containing the delimiter.
+//
+// quoteAll - If set to true all fields, except null values, are quoted.
+func QuoteAll(quoteAll bool) ToConfigFunc {
+	return func(c *ToConfig) {
+		c.QuoteAll = quoteAll
+	}
+}
+
+// FloatFormat configures the formatting of float values, see strconv.FormatFloat for
+// a description of the arguments. Default is 'f' with precision -1, the smallest number
+// of digits necessary to represent the value exactly.
+//
+// format - One of 'b', 'e', 'E', 'f', 'g', 'G', 'x' and 'X'.
+//
+// precision - The number of digits, -1 for the smallest number necessary.
+func FloatFormat(format byte, precision int) ToConfigFunc {
+	return func(c *ToConfig) {
+		c.FloatFormat = format
+		c.FloatPrec = precision
+	}
+}
+
+// Gzip configures the output to be compressed using gzip.
+//
+// level - The compression level, see compress/gzip. Use gzip.DefaultCompression if unsure.
+func Gzip(level int) ToConfigFunc {
+	return func(c *ToConfig) {
+		c.Compression = qfio.GzipCompression
+		c.CompressionLevel = level
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"math"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/tobgu/qframe/errors"
 	"github.com/tobgu/qframe/function"
@@ -102,6 +103,30 @@ func NewDefaultCtx() *Context {
 					"+": function.ConcatS,
 				},
 			},
+			types.FunctionTypeTime: functionsByArgCount{
+				singleArgs: map[string]interface{}{
+					"year":         function.YearT,
+					"month":        function.MonthT,
+					"day":          function.DayT,
+					"hour":         function.HourT,
+					"minute":       function.MinuteT,
+					"second":       function.SecondT,
+					"weekday":      function.WeekdayT,
+					"yearday":      function.YearDayT,
+					"unix":         function.UnixT,
+					"str":          function.StrT,
+					"trunc_year":   function.TruncYearT,
+					"trunc_month":  function.TruncMonthT,
+					"trunc_day":    function.TruncDayT,
+					"trunc_hour":   function.TruncHourT,
+					"trunc_minute": function.TruncMinuteT,
+					"trunc_second": function.TruncSecondT,
+				},
+				doubleArgs: map[string]interface{}{
+					"min": function.MinT,
+					"max": function.MaxT,
+				},
+			},
 		},
 	}
 }
@@ -164,6 +189,12 @@ func (ctx *Context) SetFunc(name string, fn interface{}) error {
 	case func(*string) *string, func(*string) int, func(*string) float64, func(*string) bool:
 		ac, typ = ArgCountOne, types.FunctionTypeString
 
+	// Time
+	case func(time.Time, time.Time) time.Time:
+		ac, typ = ArgCountTwo, types.FunctionTypeTime
+	case func(time.Time) time.Time, func(time.Time) int, func(time.Time) float64, func(time.Time) bool, func(time.Time) *string:
+		ac, typ = ArgCountOne, types.FunctionTypeTime
+
 	default:
 		return errors.New("SetFunc", "invalid function type for function \"%s\": %v", name, reflect.TypeOf(fn))
 	}
@@ -0,0 +1,86 @@
+package join
+
+// Type describes which rows that should be kept when joining two QFrames.
+type Type byte
+
+const (
+	// Inner only keeps rows that have a match in both frames. This is the default.
+	Inner Type = iota
+
+	// Left keeps all rows in the left frame. Rows without a match in the right
+	// frame will have null values in the columns originating from the right frame.
+	Left
+
+	// Right keeps all rows in the right frame. Rows without a match in the left
+	// frame will have null values in the columns originating from the left frame.
+	Right
+
+	// Outer keeps all rows from both frames, filling in null values where there is no match.
+	Outer
+)
+
+// String returns a string representation of the join type.
+func (t Type) String() string {
+	switch t {
+	case Inner:
+		return "inner"
+	case Left:
+		return "left"
+	case Right:
+		return "right"
+	case Outer:
+		return "outer"
+	default:
+		return "unknown"
+	}
+}
+
+// Config holds configuration for join operations on QFrames.
+// It should be considered a private implementation detail and should never be
+// referenced or used directly outside of the QFrame code. To manipulate it
+// use the functions returning ConfigFunc below.
+type Config struct {
+	Columns     []string
+	How         Type
+	LeftSuffix  string
+	RightSuffix string
+}
+
+// ConfigFunc is a function that operates on a Config object.
+type ConfigFunc func(c *Config)
+
+// NewConfig creates a new Config object.
+// This function should never be called from outside QFrame.
+func NewConfig(configFns []ConfigFunc) Config {
+	config := Config{How: Inner, LeftSuffix: "_x", RightSuffix: "_y"}
+	for _, f := range configFns {
+		f(&config)
+	}
+
+	return config
+}
+
+// On sets the key columns to join on. The columns must be present in both frames.
+// This option is mandatory.
+func On(columns ...string) ConfigFunc {
+	return func(c *Config) {
+		c.Columns = columns
+	}
+}
+
+// How sets the type of join to perform. Default is Inner.
+func How(t Type) ConfigFunc {
+	return func(c *Config) {
+		c.How = t
+	}
+}
+
+// Suffixes sets the suffixes that are added to the names of non key columns
+// that exist in both frames. Default is "_x" for the left frame and "_y" for
+// the right frame.
+func Suffixes(left, right string) ConfigFunc {
+	return func(c *Config) {
+		c.LeftSuffix = left
+		c.RightSuffix = right
+	}
+}
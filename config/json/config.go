@@ -0,0 +1,34 @@
+package json
+
+import (
+	qfio "github.com/tobgu/qframe/internal/io"
+)
+
+// ToConfig holds configuration for writing QFrames as JSON.
+// It should be considered a private implementation detail and should never be
+// referenced or used directly outside of the QFrame code. To manipulate it
+// use the functions returning ToConfigFunc below.
+type ToConfig qfio.JSONWriteConfig
+
+// ToConfigFunc is a function that operates on a ToConfig object.
+type ToConfigFunc func(*ToConfig)
+
+// NewToConfig creates a new ToConfig object.
+// This function should never be called from outside QFrame.
+func NewToConfig(ff []ToConfigFunc) ToConfig {
+	conf := ToConfig{}
+	for _, f := range ff {
+		f(&conf)
+	}
+	return conf
+}
+
+// Gzip configures the output to be compressed using gzip.
+//
+// level - The compression level, see compress/gzip. Use gzip.DefaultCompression if unsure.
+func Gzip(level int) ToConfigFunc {
+	return func(c *ToConfig) {
+		c.Compression = qfio.GzipCompression
+		c.CompressionLevel = level
+	}
+}
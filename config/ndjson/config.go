@@ -0,0 +1,95 @@
+package ndjson
+
+import (
+	"time"
+
+	qfio "github.com/tobgu/qframe/internal/io"
+	"github.com/tobgu/qframe/types"
+)
+
+// Config holds configuration for reading newline delimited JSON into QFrames.
+// It should be considered a private implementation detail and should never be
+// referenced or used directly outside of the QFrame code. To manipulate it
+// use the functions returning ConfigFunc below.
+type Config qfio.NDJSONConfig
+
+// ConfigFunc is a function that operates on a Config object.
+type ConfigFunc func(*Config)
+
+// NewConfig creates a new Config object.
+// This function should never be called from outside QFrame.
+func NewConfig(ff []ConfigFunc) Config {
+	conf := Config{TimeLayout: time.RFC3339Nano, TimeLocation: time.UTC}
+	for _, f := range ff {
+		f(&conf)
+	}
+	return conf
+}
+
+// Types is used set types for certain columns.
+// If types are not given the type is inferred from the values of the column. Numbers
+// are read as ints unless any of them has a fraction or exponent in which case they
+// are read as floats. Columns containing only nulls are read as string columns. Objects
+// and arrays are read as strings holding their JSON text.
+//
+// Columns listed here that are not present in the input are created with only null values.
+// Time columns are parsed from strings, see TimeLayout. String and enum columns keep the
+// JSON text of values that are not strings.
+//
+// typs - map column name -> type name. For a list of type names see package qframe/types.
+func Types(typs map[string]string) ConfigFunc {
+	return func(c *Config) {
+		c.Types = make(map[string]types.DataType, len(typs))
+		for k, v := range typs {
+			c.Types[k] = types.DataType(v)
+		}
+	}
+}
+
+// EnumValues is used to list the possible values and internal order of these values for an enum column.
+//
+// values - map column name -> list of valid values.
+//
+// Note that the column must be listed as having an enum type (using Types above) for this option to take effect.
+func EnumValues(values map[string][]string) ConfigFunc {
+	return func(c *Config) {
+		c.EnumVals = make(map[string][]string)
+		for k, v := range values {
+			c.EnumVals[k] = v
+		}
+	}
+}
+
+// AutoEnum configures columns that would otherwise be string columns to be read as enum
+// columns if they contain at most maxCardinality distinct values. Columns listed in Types
+// are not affected. The ordering between the values is undefined, see EnumValues.
+//
+// maxCardinality - The maximum number of distinct values of an enum column. 0 (default) disables
+// automatic enum detection.
+func AutoEnum(maxCardinality int) ConfigFunc {
+	return func(c *Config) {
+		c.AutoEnum = maxCardinality
+	}
+}
+
+// TimeLayout configures the layout used to parse time columns, see the time package
+// for a description of layouts. Default is ISO-8601 (time.RFC3339Nano).
+//
+// layout - The layout to use.
+//
+// Note that the column must be listed as having a time type (using Types above) for this option to take effect.
+func TimeLayout(layout string) ConfigFunc {
+	return func(c *Config) {
+		c.TimeLayout = layout
+	}
+}
+
+// TimeLocation configures the location of time columns. It is used when parsing values that
+// do not contain any time zone information and when presenting the values. Default is UTC.
+//
+// loc - The location to use.
+func TimeLocation(loc *time.Location) ConfigFunc {
+	return func(c *Config) {
+		c.TimeLocation = loc
+	}
+}
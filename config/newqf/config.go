@@ -7,6 +7,7 @@ package newqf
 type Config struct {
 	ColumnOrder []string
 	EnumColumns map[string][]string
+	AutoEnum    int
 }
 
 // ConfigFunc is a function that operates on a Config object.
@@ -43,3 +44,15 @@ func Enums(columns map[string][]string) ConfigFunc {
 		}
 	}
 }
+
+// AutoEnum configures string columns that are not listed in Enums to be created as enum
+// columns if they contain at most maxCardinality distinct values. The ordering between
+// the values is undefined.
+//
+// maxCardinality - The maximum number of distinct values of an enum column. 0 (default) disables
+// automatic enum detection.
+func AutoEnum(maxCardinality int) ConfigFunc {
+	return func(c *Config) {
+		c.AutoEnum = maxCardinality
+	}
+}
@@ -0,0 +1,97 @@
+package parquet
+
+import (
+	qfparquet "github.com/tobgu/qframe/internal/io/parquet"
+)
+
+// Config holds configuration for reading Parquet files into QFrames.
+// It should be considered a private implementation detail and should never be
+// referenced or used directly outside of the QFrame code. To manipulate it
+// use the functions returning ConfigFunc below.
+type Config qfparquet.ReadConfig
+
+// ConfigFunc is a function that operates on a Config object.
+type ConfigFunc func(*Config)
+
+// NewConfig creates a new Config object.
+// This function should never be called from outside QFrame.
+func NewConfig(ff []ConfigFunc) Config {
+	conf := Config{}
+	for _, f := range ff {
+		f(&conf)
+	}
+	return conf
+}
+
+// Columns configures which columns to read and in which order. Only the selected
+// columns are decoded. All columns are read by default.
+//
+// columns - The names of the columns to read.
+func Columns(columns ...string) ConfigFunc {
+	return func(c *Config) {
+		c.Columns = columns
+	}
+}
+
+// RowGroups configures which row groups to read. Rows from the selected row groups
+// are concatenated in the given order. All row groups are read by default.
+//
+// rowGroups - The zero based indexes of the row groups to read.
+func RowGroups(rowGroups ...int) ConfigFunc {
+	return func(c *Config) {
+		c.RowGroups = rowGroups
+	}
+}
+
+// ToConfig holds configuration for writing QFrames as Parquet.
+// It should be considered a private implementation detail and should never be
+// referenced or used directly outside of the QFrame code. To manipulate it
+// use the functions returning ToConfigFunc below.
+type ToConfig qfparquet.WriteConfig
+
+// ToConfigFunc is a function that operates on a ToConfig object.
+type ToConfigFunc func(*ToConfig)
+
+// NewToConfig creates a new ToConfig object.
+// This function should never be called from outside QFrame.
+func NewToConfig(ff []ToConfigFunc) ToConfig {
+	conf := ToConfig{Codec: qfparquet.Snappy}
+	for _, f := range ff {
+		f(&conf)
+	}
+	return conf
+}
+
+// Snappy configures pages to be compressed using snappy (default).
+func Snappy() ToConfigFunc {
+	return func(c *ToConfig) {
+		c.Codec = qfparquet.Snappy
+	}
+}
+
+// Gzip configures pages to be compressed using gzip.
+//
+// level - The compression level, see compress/gzip. Use gzip.DefaultCompression if unsure.
+func Gzip(level int) ToConfigFunc {
+	return func(c *ToConfig) {
+		c.Codec = qfparquet.Gzip
+		c.CompressionLevel = level
+	}
+}
+
+// Uncompressed configures pages to be written without compression.
+func Uncompressed() ToConfigFunc {
+	return func(c *ToConfig) {
+		c.Codec = qfparquet.Uncompressed
+	}
+}
+
+// RowGroupSize configures the max number of rows in each row group.
+// All rows are written to a single row group by default.
+//
+// rows - Max number of rows per row group, zero means no limit.
+func RowGroupSize(rows int) ToConfigFunc {
+	return func(c *ToConfig) {
+		c.RowGroupSize = rows
+	}
+}
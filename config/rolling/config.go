@@ -0,0 +1,42 @@
+package rolling
+
+// Config holds configuration for rolling window operations on QFrames.
+// It should be considered a private implementation detail and should never be
+// referenced or used directly outside of the QFrame code. To manipulate it
+// use the functions returning ConfigFunc below.
+type Config struct {
+	MinPeriods int
+	Center     bool
+}
+
+// ConfigFunc is a function that operates on a Config object.
+type ConfigFunc func(c *Config)
+
+// NewConfig creates a new Config object.
+// This function should never be called from outside QFrame.
+func NewConfig(configFns []ConfigFunc) Config {
+	var config Config
+	for _, f := range configFns {
+		f(&config)
+	}
+
+	return config
+}
+
+// MinPeriods sets the minimum number of non null values that must be present in a window
+// for it to produce a value. Windows with fewer values produce null.
+// Default is the size of the window.
+func MinPeriods(n int) ConfigFunc {
+	return func(c *Config) {
+		c.MinPeriods = n
+	}
+}
+
+// Center configures if the window should be centered around each row (true) or
+// end at each row (false, default). For even window sizes the window extends
+// one row further back than forward.
+func Center(center bool) ConfigFunc {
+	return func(c *Config) {
+		c.Center = center
+	}
+}
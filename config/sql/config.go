@@ -125,3 +125,15 @@ func Precision(i int) ConfigFunc {
 		c.Precision = i
 	}
 }
+
+// Rename accepts a map of column names, as
+// returned by the query, to new column names.
+// Coerce uses the names returned by the query.
+func Rename(columns map[string]string) ConfigFunc {
+	return func(c *Config) {
+		c.Rename = make(map[string]string, len(columns))
+		for k, v := range columns {
+			c.Rename[k] = v
+		}
+	}
+}
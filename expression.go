@@ -3,6 +3,7 @@ package qframe
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/tobgu/qframe/config/eval"
 	"github.com/tobgu/qframe/errors"
@@ -117,7 +118,7 @@ func newConstExpr(x interface{}) (constExpr, bool) {
 	value := x
 	var isConst bool
 	switch x.(type) {
-	case int, float64, bool, string:
+	case int, float64, bool, string, time.Time:
 		isConst = true
 	default:
 		isConst = false
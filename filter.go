@@ -6,6 +6,7 @@ import (
 
 	"github.com/tobgu/qframe/errors"
 	"github.com/tobgu/qframe/filter"
+	"github.com/tobgu/qframe/internal/grouper"
 	"github.com/tobgu/qframe/internal/index"
 	"github.com/tobgu/qframe/internal/math/integer"
 )
@@ -37,6 +38,14 @@ type NotClause struct {
 	subClause FilterClause
 }
 
+// InFrameClause represents a semi join (or anti join if inverted) against another QFrame.
+type InFrameClause struct {
+	other   QFrame
+	columns []string
+	inverse bool
+	err     error
+}
+
 // NullClause is a convenience type to simplify clients when no filtering is to be done.
 type NullClause struct{}
 
@@ -283,3 +292,80 @@ func (c NullClause) filter(qf QFrame) QFrame {
 func (c NullClause) Err() error {
 	return nil
 }
+
+func newInFrameClause(other QFrame, columns []string, inverse bool) InFrameClause {
+	var err error
+	if other.Err != nil {
+		err = errors.Propagate("new in frame clause", other.Err)
+	} else if len(columns) == 0 {
+		err = errors.New("new in frame clause", "at least one column must be given")
+	}
+
+	return InFrameClause{other: other, columns: columns, inverse: inverse, err: err}
+}
+
+// InFrame returns a new InFrameClause that keeps the rows for which the values in columns
+// are also found in the same columns in other.
+//
+// The columns must exist in both frames. Enum and string columns can be matched against each
+// other, all other columns must be of the same type. Rows with null values in any of the columns
+// never match.
+func InFrame(other QFrame, columns ...string) InFrameClause {
+	return newInFrameClause(other, columns, false)
+}
+
+// NotInFrame returns a new InFrameClause that keeps the rows for which the values in columns
+// are not found in the same columns in other. It is the inverse of InFrame.
+func NotInFrame(other QFrame, columns ...string) InFrameClause {
+	return newInFrameClause(other, columns, true)
+}
+
+// String returns a textual description of the filter clause.
+func (c InFrameClause) String() string {
+	if c.Err() != nil {
+		return c.Err().Error()
+	}
+
+	name := "in_frame"
+	if c.inverse {
+		name = "not_in_frame"
+	}
+
+	quoted := make([]string, len(c.columns))
+	for i, col := range c.columns {
+		quoted[i] = fmt.Sprintf("%q", col)
+	}
+
+	return fmt.Sprintf(`["%s", [%s]]`, name, strings.Join(quoted, ", "))
+}
+
+func (c InFrameClause) filter(qf QFrame) QFrame {
+	if qf.Err != nil {
+		return qf
+	}
+
+	if c.Err() != nil {
+		return qf.withErr(c.Err())
+	}
+
+	if err := checkKeyColumns("InFrame", qf, c.other, c.columns); err != nil {
+		return qf.withErr(err)
+	}
+
+	probe, build := newJoinSide(qf, c.columns), newJoinSide(c.other, c.columns)
+	table := grouper.NewJoinTable(build.qf.index, build.keys)
+	newIx := make(index.Int, 0, qf.index.Len())
+	for _, i := range qf.index {
+		found := !probe.hasNullKey(i) && table.Lookup(i, probe.keys) != nil
+		if found != c.inverse {
+			newIx = append(newIx, i)
+		}
+	}
+
+	return qf.withIndex(newIx)
+}
+
+// Err returns any error that may have occurred during creation of the filter
+func (c InFrameClause) Err() error {
+	return c.err
+}
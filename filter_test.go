@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/tobgu/qframe"
+	"github.com/tobgu/qframe/config/newqf"
+	"github.com/tobgu/qframe/types"
 )
 
 func f(column string, comparator string, arg interface{}) qframe.Filter {
@@ -106,6 +108,26 @@ func TestFilter_Success(t *testing.T) {
 			not(f("COL1", "<", 6)),
 			[]int{},
 		},
+		{
+			"In frame",
+			qframe.InFrame(qframe.New(map[string]interface{}{"COL1": []int{4, 2, 7, 2}}), "COL1"),
+			[]int{2, 4},
+		},
+		{
+			"Not in frame",
+			qframe.NotInFrame(qframe.New(map[string]interface{}{"COL1": []int{4, 2, 7, 2}}), "COL1"),
+			[]int{1, 3, 5},
+		},
+		{
+			"In frame combined with or",
+			or(eq(1), qframe.InFrame(qframe.New(map[string]interface{}{"COL1": []int{5}}), "COL1")),
+			[]int{1, 5},
+		},
+		{
+			"Not in frame inverted",
+			not(qframe.NotInFrame(qframe.New(map[string]interface{}{"COL1": []int{3, 4}}), "COL1")),
+			[]int{3, 4},
+		},
 	}
 
 	for _, tc := range table {
@@ -162,6 +184,14 @@ func TestFilter_String(t *testing.T) {
 			or(f("COL1", ">", 3), f("COL2", ">", 3)),
 			`["or", [">", "COL1", 3], [">", "COL2", 3]]`,
 		},
+		{
+			qframe.InFrame(qframe.New(map[string]interface{}{"COL1": []int{1}}), "COL1", "COL2"),
+			`["in_frame", ["COL1", "COL2"]]`,
+		},
+		{
+			qframe.NotInFrame(qframe.New(map[string]interface{}{"COL1": []int{1}}), "COL1"),
+			`["not_in_frame", ["COL1"]]`,
+		},
 	}
 
 	for _, tc := range table {
@@ -173,3 +203,70 @@ func TestFilter_String(t *testing.T) {
 		})
 	}
 }
+
+func TestFilter_InFrameMultipleColumns(t *testing.T) {
+	a, b, c := "a", "b", "c"
+	input := qframe.New(map[string]interface{}{
+		"COL1": []*string{&a, &a, &b, &c, nil},
+		"COL2": []float64{1, 2, 1, 1, 1},
+	}, newqf.Enums(map[string][]string{"COL1": nil}))
+
+	other := qframe.New(map[string]interface{}{
+		"COL1": []*string{&a, &b, &b, nil},
+		"COL2": []float64{2, 1, 1, 1},
+		"COL3": []int{1, 2, 3, 4},
+	})
+
+	out := input.Filter(qframe.InFrame(other, "COL1", "COL2"))
+	expected := qframe.New(map[string]interface{}{
+		"COL1": []*string{&a, &b},
+		"COL2": []float64{2, 1},
+	}, newqf.Enums(map[string][]string{"COL1": nil}))
+	assertEquals(t, expected, out)
+
+	out = input.Filter(qframe.NotInFrame(other, "COL1", "COL2"))
+	expected = qframe.New(map[string]interface{}{
+		"COL1": []*string{&a, &c, nil},
+		"COL2": []float64{1, 1, 1},
+	}, newqf.Enums(map[string][]string{"COL1": nil}))
+	assertEquals(t, expected, out)
+}
+
+func TestFilter_InFrameErrors(t *testing.T) {
+	input := qframe.New(map[string]interface{}{"COL1": []int{1, 2}})
+	assertErr(t, input.Filter(qframe.InFrame(input)).Err, "at least one column")
+	assertErr(t, input.Filter(qframe.InFrame(input, "COL2")).Err, "unknown column")
+	assertErr(t, input.Filter(qframe.InFrame(qframe.New(map[string]interface{}{"COL1": []float64{1}}), "COL1")).Err, "incompatible types")
+	assertErr(t, input.Filter(qframe.InFrame(input.Select("COL3"), "COL1")).Err, "unknown column")
+}
+
+func TestFilter_NullableIntBool(t *testing.T) {
+	input := qframe.New(map[string]interface{}{
+		"COL1": []*int{intPtr(1), nil, intPtr(3), intPtr(4)},
+		"COL2": []*int{intPtr(2), intPtr(2), nil, intPtr(4)},
+		"COL3": []*bool{boolPtr(true), nil, boolPtr(false), boolPtr(true)},
+	})
+
+	table := []struct {
+		name     string
+		clause   qframe.FilterClause
+		expected []*int
+	}{
+		{"Null never greater", f("COL1", ">", 0), []*int{intPtr(1), intPtr(3), intPtr(4)}},
+		{"Null always not equal", f("COL1", "!=", 1), []*int{nil, intPtr(3), intPtr(4)}},
+		{"Null never in", f("COL1", "in", []int{0, 1}), []*int{intPtr(1)}},
+		{"Null in any column never less", f("COL1", "<", types.ColumnName("COL2")), []*int{intPtr(1)}},
+		{"Null in any column always not equal", f("COL1", "!=", types.ColumnName("COL2")), []*int{intPtr(1), nil, intPtr(3)}},
+		{"Null never passed to custom filter", qframe.Filter{Column: "COL1", Comparator: func(x int) bool { return x < 2 }}, []*int{intPtr(1)}},
+		{"Null bool never equal", f("COL3", "=", true), []*int{intPtr(1), intPtr(4)}},
+		{"Null or value", or(f("COL1", "isnull", nil), f("COL1", ">", 3)), []*int{nil, intPtr(4)}},
+	}
+
+	for _, tc := range table {
+		t.Run(fmt.Sprintf("Filter %s", tc.name), func(t *testing.T) {
+			out := input.Filter(tc.clause)
+			assertNotErr(t, out.Err)
+			assertEquals(t, qframe.New(map[string]interface{}{"COL1": tc.expected}), out.Select("COL1"))
+		})
+	}
+}
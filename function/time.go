@@ -0,0 +1,100 @@
+package function
+
+import "time"
+
+// YearT returns the year of t.
+func YearT(t time.Time) int {
+	return t.Year()
+}
+
+// MonthT returns the month of the year of t, 1 - 12.
+func MonthT(t time.Time) int {
+	return int(t.Month())
+}
+
+// DayT returns the day of the month of t.
+func DayT(t time.Time) int {
+	return t.Day()
+}
+
+// HourT returns the hour within the day of t, 0 - 23.
+func HourT(t time.Time) int {
+	return t.Hour()
+}
+
+// MinuteT returns the minute within the hour of t, 0 - 59.
+func MinuteT(t time.Time) int {
+	return t.Minute()
+}
+
+// SecondT returns the second within the minute of t, 0 - 59.
+func SecondT(t time.Time) int {
+	return t.Second()
+}
+
+// WeekdayT returns the day of the week of t, 0 (Sunday) - 6 (Saturday).
+func WeekdayT(t time.Time) int {
+	return int(t.Weekday())
+}
+
+// YearDayT returns the day of the year of t, 1 - 366.
+func YearDayT(t time.Time) int {
+	return t.YearDay()
+}
+
+// UnixT returns t as the number of seconds since the Unix epoch.
+func UnixT(t time.Time) int {
+	return int(t.Unix())
+}
+
+// StrT returns t formatted according to ISO-8601 (RFC 3339).
+func StrT(t time.Time) *string {
+	result := t.Format(time.RFC3339Nano)
+	return &result
+}
+
+// TruncYearT returns the start of the year of t.
+func TruncYearT(t time.Time) time.Time {
+	return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+}
+
+// TruncMonthT returns the start of the month of t.
+func TruncMonthT(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// TruncDayT returns the start of the day of t.
+func TruncDayT(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// TruncHourT returns the start of the hour of t.
+func TruncHourT(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+}
+
+// TruncMinuteT returns the start of the minute of t.
+func TruncMinuteT(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location())
+}
+
+// TruncSecondT returns the start of the second of t.
+func TruncSecondT(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, t.Location())
+}
+
+// MinT returns the earliest of x and y.
+func MinT(x, y time.Time) time.Time {
+	if y.Before(x) {
+		return y
+	}
+	return x
+}
+
+// MaxT returns the latest of x and y.
+func MaxT(x, y time.Time) time.Time {
+	if y.After(x) {
+		return y
+	}
+	return x
+}
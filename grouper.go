@@ -4,6 +4,7 @@ import (
 	"github.com/tobgu/qframe/errors"
 	"github.com/tobgu/qframe/internal/grouper"
 	"github.com/tobgu/qframe/internal/index"
+	qfstrings "github.com/tobgu/qframe/internal/strings"
 	"github.com/tobgu/qframe/types"
 )
 
@@ -33,6 +34,11 @@ type Aggregation struct {
 
 	// Column is the name of the column to apply the aggregation to.
 	Column string
+
+	// As is the name of the column in which the result of the aggregation is stored.
+	// This field is optional, if not set the result is stored in a column named Column.
+	// Set As to apply multiple aggregations to the same column.
+	As string
 }
 
 // Aggregate applies the given aggregations to all row groups in the Grouper.
@@ -60,26 +66,39 @@ func (g Grouper) Aggregate(aggs ...Aggregation) QFrame {
 		newColumns = append(newColumns, col)
 	}
 
-	var err error
 	for _, agg := range aggs {
 		col, ok := g.columnsByName[agg.Column]
 		if !ok {
 			return QFrame{Err: errors.New("Aggregate", unknownCol(agg.Column))}
 		}
 
-		_, ok = newColumnsByName[agg.Column]
-		if ok {
+		dstName := agg.Column
+		if agg.As != "" {
+			if err := qfstrings.CheckName(agg.As); err != nil {
+				return QFrame{Err: errors.Propagate("Aggregate", err)}
+			}
+			dstName = agg.As
+		}
+
+		if _, ok = newColumnsByName[dstName]; ok {
 			return QFrame{Err: errors.New(
 				"Aggregate",
-				"cannot aggregate on column that is part of group by or is already an aggregate: %s", agg.Column)}
+				"cannot aggregate on column that is part of group by or is already an aggregate: %s", dstName)}
+		}
+
+		data, err := col.Aggregate(g.indices, agg.Fn)
+		if err != nil {
+			return QFrame{Err: errors.Propagate("Aggregate", err)}
 		}
 
-		col.Column, err = col.Aggregate(g.indices, agg.Fn)
+		col.Column, err = sliceToColumn(data)
 		if err != nil {
 			return QFrame{Err: errors.Propagate("Aggregate", err)}
 		}
 
-		newColumnsByName[agg.Column] = col
+		col.name = dstName
+		col.pos = len(newColumns)
+		newColumnsByName[dstName] = col
 		newColumns = append(newColumns, col)
 	}
 
@@ -1,7 +1,14 @@
 package bcolumn
 
-var aggregations = map[string]func([]bool) bool{
-	"majority": majority,
+var aggregations = map[string]interface{}{
+	"all":            allTrue,
+	"any":            anyTrue,
+	"count":          count,
+	"count_non_null": count,
+	"first":          first,
+	"last":           last,
+	"majority":       majority,
+	"nunique":        nunique,
 }
 
 func majority(b []bool) bool {
@@ -16,3 +23,34 @@ func majority(b []bool) bool {
 
 	return tCount > fCount
 }
+
+func anyTrue(b []bool) bool {
+	for _, x := range b {
+		if x {
+			return true
+		}
+	}
+	return false
+}
+
+func allTrue(b []bool) bool {
+	for _, x := range b {
+		if !x {
+			return false
+		}
+	}
+	return true
+}
+
+func nunique(b []bool) int {
+	tCount, fCount := 0, 0
+	for _, x := range b {
+		if x {
+			tCount = 1
+		} else {
+			fCount = 1
+		}
+	}
+
+	return tCount + fCount
+}
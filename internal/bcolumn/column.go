@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/tobgu/qframe/errors"
+	"github.com/tobgu/qframe/internal/bitmap"
 	"github.com/tobgu/qframe/internal/column"
 	"github.com/tobgu/qframe/internal/hash"
 	"github.com/tobgu/qframe/internal/index"
@@ -12,6 +13,12 @@ import (
 )
 
 func (c Comparable) Compare(i, j uint32) column.CompareResult {
+	if c.valid != nil {
+		if result, ok := c.compareNull(i, j); ok {
+			return result
+		}
+	}
+
 	x, y := c.data[i], c.data[j]
 	if x == y {
 		return column.Equal
@@ -25,6 +32,17 @@ func (c Comparable) Compare(i, j uint32) column.CompareResult {
 }
 
 func (c Comparable) HashBytes(i uint32, buf *hash.Murm32) {
+	if c.valid.IsNull(i) {
+		if c.equalNullValue == column.NotEqual {
+			// Use a random value here to avoid hash collisions when
+			// we don't consider null to equal null.
+			buf.WriteRand32()
+		} else {
+			buf.WriteByte(2)
+		}
+		return
+	}
+
 	if c.data[i] {
 		buf.WriteByte(1)
 	}
@@ -35,17 +53,23 @@ func (c Column) DataType() types.DataType {
 	return types.Bool
 }
 
-func (c Column) StringAt(i uint32, _ string) string {
+func (c Column) StringAt(i uint32, naRep string) string {
+	if c.isNull(i) {
+		return naRep
+	}
 	return strconv.FormatBool(c.data[i])
 }
 
 func (c Column) AppendByteStringAt(buf []byte, i uint32) []byte {
+	if c.isNull(i) {
+		return append(buf, "null"...)
+	}
 	return strconv.AppendBool(buf, c.data[i])
 }
 
 func (c Column) ByteSize() int {
-	// Slice header + data
-	return 2*8 + len(c.data)
+	// Slice header + data + validity
+	return 2*8 + len(c.data) + c.valid.ByteSize()
 }
 
 func (c Column) Equals(index index.Int, other column.Column, otherIndex index.Int) bool {
@@ -55,7 +79,8 @@ func (c Column) Equals(index index.Int, other column.Column, otherIndex index.In
 	}
 
 	for ix, x := range index {
-		if c.data[x] != otherI.data[otherIndex[ix]] {
+		xNull, yNull := c.isNull(x), otherI.isNull(otherIndex[ix])
+		if xNull != yNull || (!xNull && c.data[x] != otherI.data[otherIndex[ix]]) {
 			return false
 		}
 	}
@@ -107,20 +132,72 @@ func (c Column) filterCustom2(index index.Int, fn func(bool, bool) bool, compara
 }
 
 func (c Column) Filter(index index.Int, comparator interface{}, comparatee interface{}, bIndex index.Bool) error {
-	var err error
-	switch t := comparator.(type) {
-	case string:
-		err = c.filterBuiltIn(index, t, comparatee, bIndex)
-	case func(bool) bool:
-		c.filterCustom1(index, t, bIndex)
-	case func(bool, bool) bool:
-		err = c.filterCustom2(index, t, comparatee, bIndex)
-	default:
-		err = errors.New("filter bool", "invalid filter type %v", reflect.TypeOf(comparator))
-	}
-	return err
+	return c.filterWithNulls(index, comparator, comparatee, bIndex, func() error {
+		var err error
+		switch t := comparator.(type) {
+		case string:
+			err = c.filterBuiltIn(index, t, comparatee, bIndex)
+		case func(bool) bool:
+			c.filterCustom1(index, t, bIndex)
+		case func(bool, bool) bool:
+			err = c.filterCustom2(index, t, comparatee, bIndex)
+		default:
+			err = errors.New("filter bool", "invalid filter type %v", reflect.TypeOf(comparator))
+		}
+		return err
+	})
 }
 
 func (c Column) FunctionType() types.FunctionType {
 	return types.FunctionTypeBool
 }
+
+// NewNullable creates a new column from d. Positions that are null in valid
+// are null in the column. A nil valid means that the column contains no nulls.
+func NewNullable(d []bool, valid bitmap.Bitmap) Column {
+	return Column{data: d, valid: valid}
+}
+
+// NewPointers creates a new column from d where nil pointers are null.
+func NewPointers(d []*bool) Column {
+	var valid bitmap.Bitmap
+	data := make([]bool, len(d))
+	for i, p := range d {
+		if p != nil {
+			data[i] = *p
+			continue
+		}
+
+		if valid == nil {
+			valid = bitmap.New(len(d), true)
+		}
+		valid.SetNull(uint32(i))
+	}
+
+	return Column{data: data, valid: valid}
+}
+
+// NewNull creates a new column containing count null values.
+func NewNull(count int) Column {
+	return Column{data: make([]bool, count), valid: bitmap.New(count, false)}
+}
+
+// NullableSubset works like Subset with the exception that positions in
+// index equal to nullPos result in null values.
+func (c Column) NullableSubset(index index.Int, nullPos uint32) column.Column {
+	data, valid := make([]bool, len(index)), bitmap.New(len(index), true)
+	for i, ix := range index {
+		if ix == nullPos || c.isNull(ix) {
+			valid.SetNull(uint32(i))
+		} else {
+			data[i] = c.data[ix]
+		}
+	}
+
+	return Column{data: data, valid: valid}
+}
+
+// IsNull returns true if the value at position i is null.
+func (v View) IsNull(i int) bool {
+	return v.valid.IsNull(v.index[i])
+}
@@ -6,8 +6,17 @@ func Doc() string {
 	return "\n Built in filters\n" +
 		"  !=\n" +
 		"  =\n" +
+		"  isnotnull\n" +
+		"  isnull\n" +
 
 		"\n Built in aggregations\n" +
+		"  all\n" +
+		"  any\n" +
+		"  count\n" +
+		"  count_non_null\n" +
+		"  first\n" +
+		"  last\n" +
 		"  majority\n" +
+		"  nunique\n" +
 		"\n"
 }
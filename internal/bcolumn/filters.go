@@ -5,6 +5,12 @@ import (
 	"github.com/tobgu/qframe/internal/index"
 )
 
+// Null checks, applied by Column.Filter using the validity of the values
+var nullFilters = map[string]struct{}{
+	filter.IsNull:    {},
+	filter.IsNotNull: {},
+}
+
 var filterFuncs = map[string]func(index.Int, []bool, bool, index.Bool){
 	filter.Eq:  eq,
 	filter.Neq: neq,
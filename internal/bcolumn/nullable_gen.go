@@ -0,0 +1,363 @@
+// This file was automatically generated by genny.
+// Any changes will be lost if this file is regenerated.
+// see https://github.com/mauricelam/genny
+
+package bcolumn
+
+import (
+	"math"
+
+	"github.com/tobgu/qframe/errors"
+
+	"github.com/tobgu/qframe/filter"
+
+	"github.com/tobgu/qframe/internal/bitmap"
+
+	"github.com/tobgu/qframe/internal/column"
+
+	"github.com/tobgu/qframe/internal/index"
+)
+
+// Code generated from template/nullable.go DO NOT EDIT
+
+// This file contains the parts of the columns that track null values using a validity
+// bitmap. Float columns use NaN to represent null and are not generated from this file.
+
+type Column struct {
+	data []bool
+
+	// valid tracks null values, nil if the column contains no nulls.
+	valid bitmap.Bitmap
+}
+
+// Concat returns a new column holding the elements at positions ixs[i] in cols[i] for all columns, in order.
+func Concat(cols []Column, ixs []index.Int) Column {
+	size := 0
+	for _, ix := range ixs {
+		size += len(ix)
+	}
+
+	var valid bitmap.Bitmap
+	for _, c := range cols {
+		if c.valid != nil {
+			valid = bitmap.New(size, true)
+			break
+		}
+	}
+
+	data := make([]bool, 0, size)
+	for i, c := range cols {
+		for _, j := range ixs[i] {
+			if c.valid.IsNull(j) {
+				valid.SetNull(uint32(len(data)))
+			}
+			data = append(data, c.data[j])
+		}
+	}
+
+	return Column{data: data, valid: valid}
+}
+
+func (c Column) isNull(i uint32) bool {
+	return c.valid.IsNull(i)
+}
+
+// newValid returns a bitmap with all of size positions valid if the
+// column contains nulls, nil otherwise.
+func (c Column) newValid(size int) bitmap.Bitmap {
+	if c.valid == nil {
+		return nil
+	}
+	return bitmap.New(size, true)
+}
+
+// nullableData wraps int and bool data in a column.NullableData if valid is non nil.
+func nullableData(data interface{}, valid bitmap.Bitmap) interface{} {
+	if valid == nil {
+		return data
+	}
+	return column.NullableData{Data: data, Valid: valid}
+}
+
+// Apply single argument function. The result may be a column
+// of a different type than the current column. fn is not applied
+// to null values, they produce null in the result.
+func (c Column) Apply1(fn interface{}, ix index.Int) (interface{}, error) {
+	switch t := fn.(type) {
+	case func(bool) int:
+		result, valid := make([]int, len(c.data)), c.newValid(len(c.data))
+		for _, i := range ix {
+			if c.isNull(i) {
+				valid.SetNull(i)
+			} else {
+				result[i] = t(c.data[i])
+			}
+		}
+		return nullableData(result, valid), nil
+	case func(bool) float64:
+		result := make([]float64, len(c.data))
+		for _, i := range ix {
+			if c.isNull(i) {
+				result[i] = math.NaN()
+			} else {
+				result[i] = t(c.data[i])
+			}
+		}
+		return result, nil
+	case func(bool) bool:
+		result, valid := make([]bool, len(c.data)), c.newValid(len(c.data))
+		for _, i := range ix {
+			if c.isNull(i) {
+				valid.SetNull(i)
+			} else {
+				result[i] = t(c.data[i])
+			}
+		}
+		return nullableData(result, valid), nil
+	case func(bool) *string:
+		result := make([]*string, len(c.data))
+		for _, i := range ix {
+			if !c.isNull(i) {
+				result[i] = t(c.data[i])
+			}
+		}
+		return result, nil
+	default:
+		return nil, errors.New(c.fnName("Apply1"), "cannot apply type %#v to column", fn)
+	}
+}
+
+// Apply double argument function to two columns. Both columns must have the
+// same type. The resulting column will have the same type as this column.
+// The result is null where any of the columns is null.
+func (c Column) Apply2(fn interface{}, s2 column.Column, ix index.Int) (column.Column, error) {
+	ss2, ok := s2.(Column)
+	if !ok {
+		return Column{}, errors.New(c.fnName("Apply2"), "invalid column type: %s", s2.DataType())
+	}
+
+	t, ok := fn.(func(bool, bool) bool)
+	if !ok {
+		return Column{}, errors.New("Apply2", "invalid function type: %#v", fn)
+	}
+
+	var valid bitmap.Bitmap
+	if c.valid != nil || ss2.valid != nil {
+		valid = bitmap.New(len(c.data), true)
+	}
+
+	result := make([]bool, len(c.data))
+	for _, i := range ix {
+		if c.isNull(i) || ss2.isNull(i) {
+			valid.SetNull(i)
+		} else {
+			result[i] = t(c.data[i], ss2.data[i])
+		}
+	}
+
+	return Column{data: result, valid: valid}, nil
+}
+
+func (c Column) subset(index index.Int) Column {
+	data, valid := make([]bool, len(index)), c.newValid(len(index))
+	for i, ix := range index {
+		data[i] = c.data[ix]
+		if c.isNull(ix) {
+			valid.SetNull(uint32(i))
+		}
+	}
+
+	return Column{data: data, valid: valid}
+}
+
+// filterWithNulls handles the isnull and isnotnull filters and otherwise applies
+// filterFn, making sure that null values never match the filter. The exception is
+// neq for which null values always match, as for the other column types.
+func (c Column) filterWithNulls(index index.Int, comparator interface{}, comparatee interface{}, bIndex index.Bool, filterFn func() error) error {
+	if comparator == filter.IsNull || comparator == filter.IsNotNull {
+		if comparatee != nil {
+			return errors.New(c.fnName("Filter"), "%s does not take an argument", comparator)
+		}
+
+		for i, x := range bIndex {
+			if !x {
+				bIndex[i] = c.isNull(index[i]) == (comparator == filter.IsNull)
+			}
+		}
+		return nil
+	}
+
+	other, _ := comparatee.(Column)
+	if c.valid == nil && other.valid == nil {
+		return filterFn()
+	}
+
+	before := make([]bool, len(bIndex))
+	copy(before, bIndex)
+	if err := filterFn(); err != nil {
+		return err
+	}
+
+	for i, x := range before {
+		if !x && (c.isNull(index[i]) || other.isNull(index[i])) {
+			bIndex[i] = comparator == filter.Neq
+		}
+	}
+	return nil
+}
+
+func (c Column) Comparable(reverse, equalNull bool) column.Comparable {
+	result := Comparable{data: c.data, valid: c.valid, ltValue: column.LessThan, gtValue: column.GreaterThan, equalNullValue: column.NotEqual}
+	if reverse {
+		result.ltValue, result.gtValue = result.gtValue, result.ltValue
+	}
+
+	if equalNull {
+		result.equalNullValue = column.Equal
+	}
+
+	return result
+}
+
+// Aggregate applies fn to the elements of each index in indices. The result
+// may be a column of a different type than the current column.
+//
+// Null values are never passed to fn. Groups without any non null values
+// produce null, except for the built in functions count, count_non_null and
+// nunique. The built in function count counts all values, including null, and
+// first and last return null if the first or last value is null.
+func (c Column) Aggregate(indices []index.Int, fn interface{}) (interface{}, error) {
+	nullable := c.valid != nil
+	if name, ok := fn.(string); ok {
+		switch {
+		case name == "count":
+			result := make([]int, len(indices))
+			for i, ix := range indices {
+				result[i] = len(ix)
+			}
+			return result, nil
+		case nullable && (name == "first" || name == "last"):
+			positions := make(index.Int, len(indices))
+			for i, ix := range indices {
+				if name == "first" {
+					positions[i] = ix[0]
+				} else {
+					positions[i] = ix[len(ix)-1]
+				}
+			}
+			return c.subset(positions), nil
+		}
+
+		nullable = nullable && name != "count_non_null" && name != "nunique"
+		fn, ok = aggregations[name]
+		if !ok {
+			return nil, errors.New(c.fnName("Aggregate"), "aggregation function %s is not defined for column", name)
+		}
+	}
+
+	var valid bitmap.Bitmap
+	if nullable {
+		valid = bitmap.New(len(indices), true)
+	}
+
+	var buf []bool
+	switch t := fn.(type) {
+	case func([]bool) int:
+		result := make([]int, len(indices))
+		for i, ix := range indices {
+			if values := c.subsetWithBuf(ix, &buf).data; nullable && len(values) == 0 {
+				valid.SetNull(uint32(i))
+			} else {
+				result[i] = t(values)
+			}
+		}
+		return nullableData(result, valid), nil
+	case func([]bool) float64:
+		result := make([]float64, len(indices))
+		for i, ix := range indices {
+			if values := c.subsetWithBuf(ix, &buf).data; nullable && len(values) == 0 {
+				result[i] = math.NaN()
+			} else {
+				result[i] = t(values)
+			}
+		}
+		return result, nil
+	case func([]bool) bool:
+		result := make([]bool, len(indices))
+		for i, ix := range indices {
+			if values := c.subsetWithBuf(ix, &buf).data; nullable && len(values) == 0 {
+				valid.SetNull(uint32(i))
+			} else {
+				result[i] = t(values)
+			}
+		}
+		return nullableData(result, valid), nil
+	case func([]bool) *string:
+		result := make([]*string, len(indices))
+		for i, ix := range indices {
+			if values := c.subsetWithBuf(ix, &buf).data; !nullable || len(values) > 0 {
+				result[i] = t(values)
+			}
+		}
+		return result, nil
+	default:
+		return nil, errors.New(c.fnName("Aggregate"), "invalid aggregation function type: %v", t)
+	}
+}
+
+// subsetWithBuf returns the non null values at the positions in index, reusing buf if possible.
+func (c Column) subsetWithBuf(index index.Int, buf *[]bool) Column {
+	if cap(*buf) < len(index) {
+		*buf = make([]bool, 0, len(index))
+	}
+
+	data := (*buf)[:0]
+	for _, ix := range index {
+		if !c.isNull(ix) {
+			data = append(data, c.data[ix])
+		}
+	}
+
+	return Column{data: data}
+}
+
+func (c Column) View(ix index.Int) View {
+	return View{data: c.data, valid: c.valid, index: ix}
+}
+
+// Raw returns the data and the validity bitmap backing the column.
+// Neither may be modified.
+func (c Column) Raw() ([]bool, bitmap.Bitmap) {
+	return c.data, c.valid
+}
+
+type Comparable struct {
+	data           []bool
+	valid          bitmap.Bitmap
+	ltValue        column.CompareResult
+	gtValue        column.CompareResult
+	equalNullValue column.CompareResult
+}
+
+// compareNull compares the values at i and j if at least one of them is null,
+// null is considered less than all other values. ok is false if none of the values are null.
+func (c Comparable) compareNull(i, j uint32) (result column.CompareResult, ok bool) {
+	iNull, jNull := c.valid.IsNull(i), c.valid.IsNull(j)
+	switch {
+	case iNull && jNull:
+		return c.equalNullValue, true
+	case iNull:
+		return c.ltValue, true
+	case jNull:
+		return c.gtValue, true
+	}
+	return column.Equal, false
+}
+
+// View is a view into a column that allows access to individual elements by index.
+// ItemAt returns the zero value for null values.
+type View struct {
+	data  []bool
+	valid bitmap.Bitmap
+	index index.Int
+}
@@ -0,0 +1,41 @@
+package bitmap
+
+// Bitmap keeps track of which positions in a column hold a valid, non null, value.
+// A set bit means that the value at that position is valid. A nil Bitmap is used
+// by columns that contain no null values.
+type Bitmap []uint64
+
+// New returns a bitmap with room for size positions, all of them valid if valid
+// is true and all of them null otherwise.
+func New(size int, valid bool) Bitmap {
+	b := make(Bitmap, (size+63)/64)
+	if valid {
+		for i := range b {
+			b[i] = ^uint64(0)
+		}
+	}
+	return b
+}
+
+// IsNull returns true if the value at position i is null.
+func (b Bitmap) IsNull(i uint32) bool {
+	return b != nil && b[i>>6]&(1<<(i&63)) == 0
+}
+
+// SetNull marks the value at position i as null.
+func (b Bitmap) SetNull(i uint32) {
+	b[i>>6] &^= 1 << (i & 63)
+}
+
+// SetValid marks the value at position i as valid.
+func (b Bitmap) SetValid(i uint32) {
+	b[i>>6] |= 1 << (i & 63)
+}
+
+// ByteSize returns the size of the bitmap in bytes, including the slice header.
+func (b Bitmap) ByteSize() int {
+	if b == nil {
+		return 0
+	}
+	return 3*8 + 8*len(b)
+}
@@ -0,0 +1,31 @@
+package bitmap
+
+import "testing"
+
+func TestBitmap(t *testing.T) {
+	b := New(130, true)
+	for _, i := range []uint32{0, 63, 64, 129} {
+		if b.IsNull(i) {
+			t.Errorf("expected %d to be valid", i)
+		}
+
+		b.SetNull(i)
+		if !b.IsNull(i) {
+			t.Errorf("expected %d to be null", i)
+		}
+
+		b.SetValid(i)
+		if b.IsNull(i) {
+			t.Errorf("expected %d to be valid after reset", i)
+		}
+	}
+
+	if !New(10, false).IsNull(9) {
+		t.Errorf("expected null")
+	}
+
+	var nilBitmap Bitmap
+	if nilBitmap.IsNull(5) {
+		t.Errorf("expected nil bitmap to have no nulls")
+	}
+}
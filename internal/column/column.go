@@ -3,6 +3,7 @@ package column
 import (
 	"fmt"
 
+	"github.com/tobgu/qframe/internal/bitmap"
 	"github.com/tobgu/qframe/internal/hash"
 	"github.com/tobgu/qframe/internal/index"
 	"github.com/tobgu/qframe/types"
@@ -14,7 +15,7 @@ type Column interface {
 	Subset(index index.Int) Column
 	Equals(index index.Int, other Column, otherIndex index.Int) bool
 	Comparable(reverse, equalNull bool) Comparable
-	Aggregate(indices []index.Int, fn interface{}) (Column, error)
+	Aggregate(indices []index.Int, fn interface{}) (interface{}, error)
 	StringAt(i uint32, naRep string) string
 	AppendByteStringAt(buf []byte, i uint32) []byte
 	ByteSize() int
@@ -46,3 +47,10 @@ type Comparable interface {
 	// due to unknown target.
 	HashBytes(i uint32, buf *hash.Murm32)
 }
+
+// NullableData holds the int or bool data produced by Apply1 and Aggregate
+// on columns containing null values. Valid tracks which positions are null.
+type NullableData struct {
+	Data  interface{}
+	Valid bitmap.Bitmap
+}
@@ -0,0 +1,78 @@
+package ecolumn
+
+// Unless stated otherwise the aggregations below ignore null values.
+// Null is returned if there are no non null values to aggregate.
+
+var aggregations = map[string]interface{}{
+	"count":          count,
+	"count_non_null": countNonNull,
+	"first":          first,
+	"last":           last,
+	"max":            max,
+	"min":            min,
+	"nunique":        nunique,
+}
+
+func count(values []enumVal) int {
+	return len(values)
+}
+
+func countNonNull(values []enumVal) int {
+	result := 0
+	for _, v := range values {
+		if !v.isNull() {
+			result++
+		}
+	}
+	return result
+}
+
+// first returns the first value, null or not.
+func first(values []enumVal) enumVal {
+	if len(values) == 0 {
+		return nullValue
+	}
+	return values[0]
+}
+
+// last returns the last value, null or not.
+func last(values []enumVal) enumVal {
+	if len(values) == 0 {
+		return nullValue
+	}
+	return values[len(values)-1]
+}
+
+// min returns the smallest value according to the order of the enum values.
+func min(values []enumVal) enumVal {
+	result := enumVal(nullValue)
+	for _, v := range values {
+		if !v.isNull() && (result.isNull() || v < result) {
+			result = v
+		}
+	}
+	return result
+}
+
+// max returns the largest value according to the order of the enum values.
+func max(values []enumVal) enumVal {
+	result := enumVal(nullValue)
+	for _, v := range values {
+		if !v.isNull() && (result.isNull() || v > result) {
+			result = v
+		}
+	}
+	return result
+}
+
+func nunique(values []enumVal) int {
+	var set bitset
+	result := 0
+	for _, v := range values {
+		if !v.isNull() && !set.isSet(v) {
+			set.set(v)
+			result++
+		}
+	}
+	return result
+}
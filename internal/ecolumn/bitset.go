@@ -2,17 +2,34 @@ package ecolumn
 
 import "fmt"
 
-// Helper type for multi value filtering
-type bitset [4]uint64
+// Helper type for multi value filtering, one bit per enum value
+type bitset []uint64
+
+func newBitset(size int) *bitset {
+	s := make(bitset, (size+63)/64)
+	return &s
+}
 
 func (s *bitset) set(val enumVal) {
-	s[val>>6] |= 1 << (val & 0x3F)
+	word := int(val >> 6)
+	if word >= len(*s) {
+		*s = append(*s, make(bitset, word-len(*s)+1)...)
+	}
+	(*s)[word] |= 1 << (val & 0x3F)
 }
 
 func (s *bitset) isSet(val enumVal) bool {
-	return s[val>>6]&(1<<(val&0x3F)) > 0
+	word := int(val >> 6)
+	return word < len(*s) && (*s)[word]&(1<<(val&0x3F)) > 0
 }
 
 func (s *bitset) String() string {
-	return fmt.Sprintf("%X %X %X %X", s[3], s[2], s[1], s[0])
+	result := ""
+	for i := len(*s) - 1; i >= 0; i-- {
+		if result != "" {
+			result += " "
+		}
+		result += fmt.Sprintf("%X", (*s)[i])
+	}
+	return result
 }
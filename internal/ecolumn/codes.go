@@ -0,0 +1,196 @@
+package ecolumn
+
+import (
+	"math"
+
+	"github.com/tobgu/qframe/internal/index"
+)
+
+// Enum values of a column are stored using the narrowest of uint8, uint16 and uint32 that can
+// represent all values of the column. The largest value of each width is reserved for null.
+const (
+	maxCardinality8  = math.MaxUint8
+	maxCardinality16 = math.MaxUint16
+)
+
+// codes holds the enum values of the elements in a column.
+// Only the slice matching width is in use.
+type codes struct {
+	width byte
+	u8    []uint8
+	u16   []uint16
+	u32   []uint32
+}
+
+func widthFor(cardinality int) byte {
+	switch {
+	case cardinality <= maxCardinality8:
+		return 1
+	case cardinality <= maxCardinality16:
+		return 2
+	default:
+		return 4
+	}
+}
+
+func newCodes(cardinality, capacity int) codes {
+	c := codes{width: widthFor(cardinality)}
+	switch c.width {
+	case 1:
+		c.u8 = make([]uint8, 0, capacity)
+	case 2:
+		c.u16 = make([]uint16, 0, capacity)
+	default:
+		c.u32 = make([]uint32, 0, capacity)
+	}
+	return c
+}
+
+func newCodesFromVals(cardinality int, vals []enumVal) codes {
+	c := newCodes(cardinality, len(vals))
+	for _, v := range vals {
+		c.append(v)
+	}
+	return c
+}
+
+func (c codes) len() int {
+	switch c.width {
+	case 1:
+		return len(c.u8)
+	case 2:
+		return len(c.u16)
+	default:
+		return len(c.u32)
+	}
+}
+
+func (c codes) byteSize() int {
+	return int(c.width) * c.len()
+}
+
+// at returns the enum value at position i.
+func (c codes) at(i uint32) enumVal {
+	switch c.width {
+	case 1:
+		if v := c.u8[i]; v != math.MaxUint8 {
+			return enumVal(v)
+		}
+	case 2:
+		if v := c.u16[i]; v != math.MaxUint16 {
+			return enumVal(v)
+		}
+	default:
+		return enumVal(c.u32[i])
+	}
+	return nullValue
+}
+
+// append adds v to the codes, widening them if v does not fit the current width.
+func (c *codes) append(v enumVal) {
+	if !v.isNull() && widthFor(int(v)+1) > c.width {
+		c.widen(widthFor(int(v) + 1))
+	}
+
+	switch c.width {
+	case 1:
+		if v.isNull() {
+			c.u8 = append(c.u8, math.MaxUint8)
+		} else {
+			c.u8 = append(c.u8, uint8(v))
+		}
+	case 2:
+		if v.isNull() {
+			c.u16 = append(c.u16, math.MaxUint16)
+		} else {
+			c.u16 = append(c.u16, uint16(v))
+		}
+	default:
+		c.u32 = append(c.u32, uint32(v))
+	}
+}
+
+func (c *codes) widen(width byte) {
+	result := codes{width: width}
+	if width == 2 {
+		result.u16 = make([]uint16, 0, cap(c.u8))
+	} else {
+		result.u32 = make([]uint32, 0, c.len())
+	}
+
+	for i := 0; i < c.len(); i++ {
+		result.append(c.at(uint32(i)))
+	}
+	*c = result
+}
+
+// sameWidth returns a and b widened to the largest width of the two.
+func sameWidth(a, b codes) (codes, codes) {
+	if a.width < b.width {
+		a.widen(b.width)
+	} else if b.width < a.width {
+		b.widen(a.width)
+	}
+	return a, b
+}
+
+func (c codes) subset(index index.Int) codes {
+	result := codes{width: c.width}
+	switch c.width {
+	case 1:
+		result.u8 = make([]uint8, len(index))
+		for i, ix := range index {
+			result.u8[i] = c.u8[ix]
+		}
+	case 2:
+		result.u16 = make([]uint16, len(index))
+		for i, ix := range index {
+			result.u16[i] = c.u16[ix]
+		}
+	default:
+		result.u32 = make([]uint32, len(index))
+		for i, ix := range index {
+			result.u32[i] = c.u32[ix]
+		}
+	}
+	return result
+}
+
+// raw returns the slice in use, a []uint8, []uint16 or []uint32.
+func (c codes) raw() interface{} {
+	switch c.width {
+	case 1:
+		return c.u8
+	case 2:
+		return c.u16
+	default:
+		return c.u32
+	}
+}
+
+// newCodesFromRaw creates codes from a slice as returned by raw. Ok is false if raw is
+// of an unsupported type or contains values larger than or equal to cardinality.
+func newCodesFromRaw(raw interface{}, cardinality int) (codes, bool) {
+	var c codes
+	switch r := raw.(type) {
+	case []uint8:
+		c = codes{width: 1, u8: r}
+	case []uint16:
+		c = codes{width: 2, u16: r}
+	case []uint32:
+		c = codes{width: 4, u32: r}
+	default:
+		return c, false
+	}
+
+	if widthFor(cardinality) > c.width {
+		return c, false
+	}
+
+	for i := 0; i < c.len(); i++ {
+		if v := c.at(uint32(i)); !v.isNull() && int(v) >= cardinality {
+			return c, false
+		}
+	}
+	return c, true
+}
@@ -2,6 +2,7 @@ package ecolumn
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"strings"
 
@@ -14,10 +15,10 @@ import (
 	"github.com/tobgu/qframe/types"
 )
 
-type enumVal uint8
+type enumVal uint32
 
-const maxCardinality = 255
-const nullValue = maxCardinality
+const maxCardinality = math.MaxUint32
+const nullValue = enumVal(maxCardinality)
 
 func (v enumVal) isNull() bool {
 	return v == nullValue
@@ -34,7 +35,7 @@ func (v enumVal) compVal() int {
 }
 
 type Column struct {
-	data   []enumVal
+	data   codes
 	values []string
 }
 
@@ -64,6 +65,39 @@ func New(data []*string, values []string) (Column, error) {
 	return f.ToColumn(), nil
 }
 
+// NewAuto creates a new column from data if data contains at most maxCardinality
+// distinct non null values. The second return value reports if the column was created.
+func NewAuto(data []*string, maxCardinality int) (Column, bool) {
+	if maxCardinality <= 0 {
+		return Column{}, false
+	}
+
+	f, _ := NewFactory(nil, len(data))
+	for _, d := range data {
+		if d == nil {
+			f.AppendNil()
+			continue
+		}
+
+		if err := f.AppendString(*d); err != nil || f.Cardinality() > maxCardinality {
+			return Column{}, false
+		}
+	}
+
+	return f.ToColumn(), true
+}
+
+// NewCodes creates a new column from the enum codes and values of a column, as
+// returned by Codes and Values.
+func NewCodes(codes interface{}, values []string) (Column, error) {
+	c, ok := newCodesFromRaw(codes, len(values))
+	if !ok {
+		return Column{}, errors.New("NewCodes", "invalid enum codes for %d values", len(values))
+	}
+
+	return Column{data: c, values: values}, nil
+}
+
 func NewConst(val *string, count int, values []string) (Column, error) {
 	f, err := NewFactory(values, count)
 	if err != nil {
@@ -83,8 +117,8 @@ func NewConst(val *string, count int, values []string) (Column, error) {
 }
 
 func NewFactory(values []string, sizeHint int) (*Factory, error) {
-	if len(values) > maxCardinality {
-		return nil, errors.New("New enum", "too many unique values, max cardinality is %d", maxCardinality)
+	if uint64(len(values)) > maxCardinality {
+		return nil, errors.New("New enum", "too many unique values, max cardinality is %d", uint64(maxCardinality))
 	}
 
 	if values == nil {
@@ -97,17 +131,31 @@ func NewFactory(values []string, sizeHint int) (*Factory, error) {
 	}
 
 	return &Factory{column: Column{
-		data: make([]enumVal, 0, sizeHint), values: values},
+		data: newCodes(len(values), sizeHint), values: values},
 		valToEnum: valToEnum,
 		strict:    len(values) > 0}, nil
 }
 
+// NewExtendableFactory works like NewFactory with the exception that values not
+// present in values are accepted and added after those.
+func NewExtendableFactory(values []string, sizeHint int) (*Factory, error) {
+	valuesCopy := make([]string, len(values))
+	copy(valuesCopy, values)
+	f, err := NewFactory(valuesCopy, sizeHint)
+	if err != nil {
+		return nil, err
+	}
+
+	f.strict = false
+	return f, nil
+}
+
 func (f *Factory) AppendNil() {
 	f.AppendEnum(nullValue)
 }
 
 func (f *Factory) AppendEnum(val enumVal) {
-	f.column.data = append(f.column.data, val)
+	f.column.data.append(val)
 }
 
 func (f *Factory) AppendByteString(str []byte) error {
@@ -122,7 +170,7 @@ func (f *Factory) AppendByteString(str []byte) error {
 
 func (f *Factory) AppendString(str string) error {
 	if e, ok := f.valToEnum[str]; ok {
-		f.column.data = append(f.column.data, e)
+		f.AppendEnum(e)
 		return nil
 	}
 
@@ -149,8 +197,8 @@ func (f *Factory) enumVal(s *string) (enumVal, error) {
 		return 0, errors.New("enum val", `unknown enum value "%s" using strict enum`, *s)
 	}
 
-	if len(f.column.values) >= maxCardinality {
-		return 0, errors.New("enum val", `enum max cardinality (%d) exceeded`, maxCardinality)
+	if uint64(len(f.column.values)) >= maxCardinality {
+		return 0, errors.New("enum val", `enum max cardinality (%d) exceeded`, uint64(maxCardinality))
 	}
 
 	return f.newEnumVal(*s), nil
@@ -161,21 +209,67 @@ func (f *Factory) appendString(str string) error {
 		return errors.New("append enum val", `unknown enum value "%s" using strict enum`, str)
 	}
 
-	if len(f.column.values) >= maxCardinality {
-		return errors.New("append enum val", `enum max cardinality (%d) exceeded`, maxCardinality)
+	if uint64(len(f.column.values)) >= maxCardinality {
+		return errors.New("append enum val", `enum max cardinality (%d) exceeded`, uint64(maxCardinality))
 	}
 
-	ev := f.newEnumVal(str)
-	f.column.data = append(f.column.data, ev)
+	f.AppendEnum(f.newEnumVal(str))
 	return nil
 }
 
+// Cardinality returns the number of distinct values currently known by the factory.
+func (f *Factory) Cardinality() int {
+	return len(f.column.values)
+}
+
 func (f *Factory) ToColumn() Column {
 	// Using the factory after this method has been called and the column exposed
 	// is not recommended.
 	return f.column
 }
 
+// Concat returns a new column holding the elements at positions ixs[i] in cols[i] for all columns, in order.
+//
+// If the values of the columns differ they are merged. The values of the first column keep
+// their order, values only present in later columns are added after those in order of appearance.
+func Concat(cols []Column, ixs []index.Int) (Column, error) {
+	size := 0
+	for _, ix := range ixs {
+		size += len(ix)
+	}
+
+	values := make([]string, 0)
+	valToEnum := make(map[string]enumVal)
+	data := newCodes(0, size)
+	for i, c := range cols {
+		translation := make([]enumVal, len(c.values))
+		for j, v := range c.values {
+			e, ok := valToEnum[v]
+			if !ok {
+				if uint64(len(values)) >= maxCardinality {
+					return Column{}, errors.New("concat enum", `enum max cardinality (%d) exceeded`, uint64(maxCardinality))
+				}
+
+				e = enumVal(len(values))
+				values = append(values, v)
+				valToEnum[v] = e
+			}
+			translation[j] = e
+		}
+
+		for _, j := range ixs[i] {
+			v := c.data.at(j)
+			if v.isNull() {
+				data.append(nullValue)
+			} else {
+				data.append(translation[v])
+			}
+		}
+	}
+
+	return Column{data: data, values: values}, nil
+}
+
 var enumApplyFuncs = map[string]func(index.Int, Column) interface{}{
 	"ToUpper": toUpper,
 }
@@ -194,12 +288,24 @@ func toUpper(_ index.Int, s Column) interface{} {
 	return Column{data: s.data, values: newValues}
 }
 
+// Values returns the values of the column in enum order.
+func (c Column) Values() []string {
+	return c.values
+}
+
+// Codes returns the enum codes of the column, a []uint8, []uint16 or []uint32 depending
+// on the cardinality of the column. The largest value of the type is used for null.
+// The codes may not be modified.
+func (c Column) Codes() interface{} {
+	return c.data.raw()
+}
+
 func (c Column) Len() int {
-	return len(c.data)
+	return c.data.len()
 }
 
 func (c Column) StringAt(i uint32, naRep string) string {
-	v := c.data[i]
+	v := c.data.at(i)
 	if v.isNull() {
 		return naRep
 	}
@@ -208,7 +314,7 @@ func (c Column) StringAt(i uint32, naRep string) string {
 }
 
 func (c Column) AppendByteStringAt(buf []byte, i uint32) []byte {
-	enum := c.data[i]
+	enum := c.data.at(i)
 	if enum.isNull() {
 		return append(buf, "null"...)
 	}
@@ -221,7 +327,7 @@ func (c Column) ByteSize() int {
 	for _, s := range c.values {
 		totalSize += len(s)
 	}
-	totalSize += len(c.data)
+	totalSize += c.data.byteSize()
 	return totalSize
 }
 
@@ -232,8 +338,8 @@ func (c Column) Equals(index index.Int, other column.Column, otherIndex index.In
 	}
 
 	for ix, x := range index {
-		enumVal := c.data[x]
-		oEnumVal := otherE.data[otherIndex[ix]]
+		enumVal := c.data.at(x)
+		oEnumVal := otherE.data.at(otherIndex[ix])
 		if enumVal.isNull() || oEnumVal.isNull() {
 			if enumVal == oEnumVal {
 				continue
@@ -250,7 +356,7 @@ func (c Column) Equals(index index.Int, other column.Column, otherIndex index.In
 }
 
 func (c Comparable) Compare(i, j uint32) column.CompareResult {
-	x, y := c.column.data[i], c.column.data[j]
+	x, y := c.column.data.at(i), c.column.data.at(j)
 	if x.isNull() || y.isNull() {
 		if !x.isNull() {
 			return c.gtValue
@@ -275,11 +381,18 @@ func (c Comparable) Compare(i, j uint32) column.CompareResult {
 }
 
 func (c Comparable) HashBytes(i uint32, buf *hash.Murm32) {
-	buf.WriteByte(byte(c.column.data[i]))
+	// Small values are hashed as a single byte, independent of the width of the codes.
+	v := c.column.data.at(i)
+	buf.WriteByte(byte(v))
+	if v > math.MaxUint8 {
+		buf.WriteByte(byte(v >> 8))
+		buf.WriteByte(byte(v >> 16))
+		buf.WriteByte(byte(v >> 24))
+	}
 }
 
 func equalTypes(s1, s2 Column) bool {
-	if len(s1.values) != len(s2.values) || len(s1.data) != len(s2.data) {
+	if len(s1.values) != len(s2.values) || s1.Len() != s2.Len() {
 		return false
 	}
 
@@ -293,10 +406,26 @@ func equalTypes(s1, s2 Column) bool {
 }
 
 func (c Column) filterWithBitset(index index.Int, bset *bitset, bIndex index.Bool) {
-	for i, x := range bIndex {
-		if !x {
-			enum := c.data[index[i]]
-			bIndex[i] = bset.isSet(enum)
+	switch c.data.width {
+	case 1:
+		for i, x := range bIndex {
+			if !x {
+				enum := c.data.u8[index[i]]
+				bIndex[i] = enum != math.MaxUint8 && bset.isSet(enumVal(enum))
+			}
+		}
+	case 2:
+		for i, x := range bIndex {
+			if !x {
+				enum := c.data.u16[index[i]]
+				bIndex[i] = enum != math.MaxUint16 && bset.isSet(enumVal(enum))
+			}
+		}
+	default:
+		for i, x := range bIndex {
+			if !x {
+				bIndex[i] = bset.isSet(enumVal(c.data.u32[index[i]]))
+			}
 		}
 	}
 }
@@ -345,7 +474,8 @@ func (c Column) filterBuiltIn(index index.Int, comparator string, comparatee int
 			return errors.New("filter enum", "unknown comparison operator for column - column comparison, %v", comparator)
 		}
 
-		compFunc(index, c.data, comp.data, bIndex)
+		col, col2 := sameWidth(c.data, comp.data)
+		compFunc(index, col, col2, bIndex)
 		return nil
 	case nil:
 		compFunc, ok := filterFuncs0[comparator]
@@ -398,22 +528,32 @@ func (c Column) Filter(index index.Int, comparator interface{}, comparatee inter
 }
 
 func (c Column) subset(index index.Int) Column {
-	data := make([]enumVal, 0, len(index))
-	for _, ix := range index {
-		data = append(data, c.data[ix])
-	}
-
-	return Column{data: data, values: c.values}
+	return Column{data: c.data.subset(index), values: c.values}
 }
 
 func (c Column) Subset(index index.Int) column.Column {
 	return c.subset(index)
 }
 
+// NullableSubset works like Subset with the exception that positions in
+// index equal to nullPos result in null values.
+func (c Column) NullableSubset(index index.Int, nullPos uint32) column.Column {
+	data := newCodes(len(c.values), len(index))
+	for _, ix := range index {
+		if ix == nullPos {
+			data.append(nullValue)
+		} else {
+			data.append(c.data.at(ix))
+		}
+	}
+
+	return Column{data: data, values: c.values}
+}
+
 func (c Column) stringSlice(index index.Int) []*string {
 	result := make([]*string, 0, len(index))
 	for _, ix := range index {
-		v := c.data[ix]
+		v := c.data.at(ix)
 		if v.isNull() {
 			result = append(result, nil)
 		} else {
@@ -437,9 +577,9 @@ func (c Column) Comparable(reverse, equalNull bool) column.Comparable {
 }
 
 func (c Column) String() string {
-	strs := make([]string, len(c.data))
-	for i, v := range c.data {
-		if v.isNull() {
+	strs := make([]string, c.Len())
+	for i := range strs {
+		if v := c.data.at(uint32(i)); v.isNull() {
 			// For now
 			strs[i] = "null"
 		} else {
@@ -450,28 +590,59 @@ func (c Column) String() string {
 	return fmt.Sprintf("%v", strs)
 }
 
-func (c Column) Aggregate(indices []index.Int, fn interface{}) (column.Column, error) {
-	// NB! The result of aggregating over an enum column is a string column
-	switch t := fn.(type) {
-	case string:
-		// There are currently no build in aggregations for enums
+// Aggregate applies fn to each index in indices. Built in aggregations operate directly
+// on the enum values. first, last, min and max produce enum columns with the same
+// values as the current column. Aggregations using custom functions operate on strings.
+func (c Column) Aggregate(indices []index.Int, fn interface{}) (interface{}, error) {
+	name, ok := fn.(string)
+	if !ok {
+		// NB! The result of aggregating over an enum column using a function producing strings is a string column
+		return scolumn.AggregateStrings(indices, fn, c.stringSlice)
+	}
+
+	builtIn, ok := aggregations[name]
+	if !ok {
 		return nil, errors.New("enum aggregate", "aggregation function %v is not defined for enum column", fn)
-	case func([]*string) *string:
-		data := make([]*string, 0, len(indices))
+	}
+
+	var buf []enumVal
+	switch t := builtIn.(type) {
+	case func([]enumVal) enumVal:
+		data := newCodes(len(c.values), len(indices))
 		for _, ix := range indices {
-			data = append(data, t(c.stringSlice(ix)))
+			data.append(t(c.subsetWithBuf(ix, &buf)))
 		}
-		return scolumn.New(data), nil
+		return Column{data: data, values: c.values}, nil
+	case func([]enumVal) int:
+		data := make([]int, 0, len(indices))
+		for _, ix := range indices {
+			data = append(data, t(c.subsetWithBuf(ix, &buf)))
+		}
+		return data, nil
 	default:
-		return nil, errors.New("enum aggregate", "invalid aggregation function type: %v", t)
+		return nil, errors.New("enum aggregate", "invalid built in aggregation function type: %v", t)
 	}
 }
 
+func (c Column) subsetWithBuf(index index.Int, buf *[]enumVal) []enumVal {
+	if cap(*buf) < len(index) {
+		*buf = make([]enumVal, 0, len(index))
+	}
+
+	data := (*buf)[:0]
+	for _, ix := range index {
+		data = append(data, c.data.at(ix))
+	}
+
+	return data
+}
+
 func (c Column) stringPtrAt(i uint32) *string {
-	if c.data[i].isNull() {
+	v := c.data.at(i)
+	if v.isNull() {
 		return nil
 	}
-	return &c.values[c.data[i]]
+	return &c.values[v]
 }
 
 func (c Column) Apply1(fn interface{}, ix index.Int) (interface{}, error) {
@@ -484,25 +655,25 @@ func (c Column) Apply1(fn interface{}, ix index.Int) (interface{}, error) {
 	*/
 	switch t := fn.(type) {
 	case func(*string) int:
-		result := make([]int, len(c.data))
+		result := make([]int, c.Len())
 		for _, i := range ix {
 			result[i] = t(c.stringPtrAt(i))
 		}
 		return result, nil
 	case func(*string) float64:
-		result := make([]float64, len(c.data))
+		result := make([]float64, c.Len())
 		for _, i := range ix {
 			result[i] = t(c.stringPtrAt(i))
 		}
 		return result, nil
 	case func(*string) bool:
-		result := make([]bool, len(c.data))
+		result := make([]bool, c.Len())
 		for _, i := range ix {
 			result[i] = t(c.stringPtrAt(i))
 		}
 		return result, nil
 	case func(*string) *string:
-		result := make([]*string, len(c.data))
+		result := make([]*string, c.Len())
 		for _, i := range ix {
 			result[i] = t(c.stringPtrAt(i))
 		}
@@ -525,7 +696,7 @@ func (c Column) Apply2(fn interface{}, s2 column.Column, ix index.Int) (column.C
 
 	switch t := fn.(type) {
 	case func(*string, *string) *string:
-		result := make([]*string, len(c.data))
+		result := make([]*string, c.Len())
 		for _, i := range ix {
 			result[i] = t(c.stringPtrAt(i), s2S.stringPtrAt(i))
 		}
@@ -1,18 +1,20 @@
 package ecolumn
 
 import (
+	"math"
+
 	"github.com/tobgu/qframe/errors"
 	"github.com/tobgu/qframe/filter"
 	"github.com/tobgu/qframe/internal/index"
 	qfstrings "github.com/tobgu/qframe/internal/strings"
 )
 
-var filterFuncs0 = map[string]func(index.Int, []enumVal, index.Bool){
+var filterFuncs0 = map[string]func(index.Int, codes, index.Bool){
 	filter.IsNull:    isNull,
 	filter.IsNotNull: isNotNull,
 }
 
-var filterFuncs1 = map[string]func(index.Int, []enumVal, enumVal, index.Bool){
+var filterFuncs1 = map[string]func(index.Int, codes, enumVal, index.Bool){
 	filter.Gt:  gt,
 	filter.Gte: gte,
 	filter.Lt:  lt,
@@ -21,7 +23,7 @@ var filterFuncs1 = map[string]func(index.Int, []enumVal, enumVal, index.Bool){
 	filter.Neq: neq,
 }
 
-var filterFuncs2 = map[string]func(index.Int, []enumVal, []enumVal, index.Bool){
+var filterFuncs2 = map[string]func(index.Int, codes, codes, index.Bool){
 	filter.Gt:  gt2,
 	filter.Gte: gte2,
 	filter.Lt:  lt2,
@@ -53,7 +55,7 @@ func filterLike(comp string, values []string, caseSensitive bool) (*bitset, erro
 		return nil, errors.Propagate("enum like", err)
 	}
 
-	bset := &bitset{}
+	bset := newBitset(len(values))
 	for i, v := range values {
 		if matcher.Matches(v) {
 			bset.set(enumVal(i))
@@ -64,7 +66,7 @@ func filterLike(comp string, values []string, caseSensitive bool) (*bitset, erro
 }
 
 func in(comp qfstrings.StringSet, values []string) *bitset {
-	bset := &bitset{}
+	bset := newBitset(len(values))
 	for i, v := range values {
 		if comp.Contains(v) {
 			bset.set(enumVal(i))
@@ -74,38 +76,86 @@ func in(comp qfstrings.StringSet, values []string) *bitset {
 	return bset
 }
 
-func neq(index index.Int, column []enumVal, comparatee enumVal, bIndex index.Bool) {
-	for i, x := range bIndex {
-		if !x {
-			enum := column[index[i]]
-			bIndex[i] = enum.isNull() || enum.compVal() != comparatee.compVal()
+func neq(index index.Int, column codes, comparatee enumVal, bIndex index.Bool) {
+	comp := comparatee.compVal()
+	switch column.width {
+	case 1:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u8[index[i]]
+				bIndex[i] = enum == math.MaxUint8 || int(enum) != comp
+			}
+		}
+	case 2:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u16[index[i]]
+				bIndex[i] = enum == math.MaxUint16 || int(enum) != comp
+			}
+		}
+	default:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u32[index[i]]
+				bIndex[i] = enum == math.MaxUint32 || int(enum) != comp
+			}
 		}
 	}
 }
 
-func neq2(index index.Int, col, col2 []enumVal, bIndex index.Bool) {
-	for i, x := range bIndex {
-		if !x {
-			enum, enum2 := col[index[i]], col2[index[i]]
-			bIndex[i] = enum.isNull() || enum2.isNull() || enum.compVal() != enum2.compVal()
+func neq2(index index.Int, col, col2 codes, bIndex index.Bool) {
+	switch col.width {
+	case 1:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u8[index[i]], col2.u8[index[i]]
+				bIndex[i] = enum == math.MaxUint8 || enum2 == math.MaxUint8 || enum != enum2
+			}
+		}
+	case 2:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u16[index[i]], col2.u16[index[i]]
+				bIndex[i] = enum == math.MaxUint16 || enum2 == math.MaxUint16 || enum != enum2
+			}
+		}
+	default:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u32[index[i]], col2.u32[index[i]]
+				bIndex[i] = enum == math.MaxUint32 || enum2 == math.MaxUint32 || enum != enum2
+			}
 		}
 	}
 }
 
-func isNull(index index.Int, col []enumVal, bIndex index.Bool) {
-	for i, x := range bIndex {
-		if !x {
-			enum := col[index[i]]
-			bIndex[i] = enum.isNull()
-		}
-	}
+func isNull(index index.Int, col codes, bIndex index.Bool) {
+	filterNull(index, col, bIndex, true)
+}
+
+func isNotNull(index index.Int, col codes, bIndex index.Bool) {
+	filterNull(index, col, bIndex, false)
 }
 
-func isNotNull(index index.Int, col []enumVal, bIndex index.Bool) {
-	for i, x := range bIndex {
-		if !x {
-			enum := col[index[i]]
-			bIndex[i] = !enum.isNull()
+func filterNull(index index.Int, col codes, bIndex index.Bool, null bool) {
+	switch col.width {
+	case 1:
+		for i, x := range bIndex {
+			if !x {
+				bIndex[i] = (col.u8[index[i]] == math.MaxUint8) == null
+			}
+		}
+	case 2:
+		for i, x := range bIndex {
+			if !x {
+				bIndex[i] = (col.u16[index[i]] == math.MaxUint16) == null
+			}
+		}
+	default:
+		for i, x := range bIndex {
+			if !x {
+				bIndex[i] = (col.u32[index[i]] == math.MaxUint32) == null
+			}
 		}
 	}
 }
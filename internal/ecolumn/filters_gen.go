@@ -2,96 +2,272 @@ package ecolumn
 
 import (
 	"github.com/tobgu/qframe/internal/index"
+	"math"
 )
 
 // Code generated from template/... DO NOT EDIT
 
-func lt(index index.Int, column []enumVal, comparatee enumVal, bIndex index.Bool) {
-	for i, x := range bIndex {
-		if !x {
-			enum := column[index[i]]
-			bIndex[i] = !enum.isNull() && enum.compVal() < comparatee.compVal()
+func lt(index index.Int, column codes, comparatee enumVal, bIndex index.Bool) {
+	comp := comparatee.compVal()
+	switch column.width {
+	case 1:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u8[index[i]]
+				bIndex[i] = enum != math.MaxUint8 && int(enum) < comp
+			}
+		}
+	case 2:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u16[index[i]]
+				bIndex[i] = enum != math.MaxUint16 && int(enum) < comp
+			}
+		}
+	default:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u32[index[i]]
+				bIndex[i] = enum != math.MaxUint32 && int(enum) < comp
+			}
 		}
 	}
 }
 
-func lte(index index.Int, column []enumVal, comparatee enumVal, bIndex index.Bool) {
-	for i, x := range bIndex {
-		if !x {
-			enum := column[index[i]]
-			bIndex[i] = !enum.isNull() && enum.compVal() <= comparatee.compVal()
+func lte(index index.Int, column codes, comparatee enumVal, bIndex index.Bool) {
+	comp := comparatee.compVal()
+	switch column.width {
+	case 1:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u8[index[i]]
+				bIndex[i] = enum != math.MaxUint8 && int(enum) <= comp
+			}
+		}
+	case 2:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u16[index[i]]
+				bIndex[i] = enum != math.MaxUint16 && int(enum) <= comp
+			}
+		}
+	default:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u32[index[i]]
+				bIndex[i] = enum != math.MaxUint32 && int(enum) <= comp
+			}
 		}
 	}
 }
 
-func gt(index index.Int, column []enumVal, comparatee enumVal, bIndex index.Bool) {
-	for i, x := range bIndex {
-		if !x {
-			enum := column[index[i]]
-			bIndex[i] = !enum.isNull() && enum.compVal() > comparatee.compVal()
+func gt(index index.Int, column codes, comparatee enumVal, bIndex index.Bool) {
+	comp := comparatee.compVal()
+	switch column.width {
+	case 1:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u8[index[i]]
+				bIndex[i] = enum != math.MaxUint8 && int(enum) > comp
+			}
+		}
+	case 2:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u16[index[i]]
+				bIndex[i] = enum != math.MaxUint16 && int(enum) > comp
+			}
+		}
+	default:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u32[index[i]]
+				bIndex[i] = enum != math.MaxUint32 && int(enum) > comp
+			}
 		}
 	}
 }
 
-func gte(index index.Int, column []enumVal, comparatee enumVal, bIndex index.Bool) {
-	for i, x := range bIndex {
-		if !x {
-			enum := column[index[i]]
-			bIndex[i] = !enum.isNull() && enum.compVal() >= comparatee.compVal()
+func gte(index index.Int, column codes, comparatee enumVal, bIndex index.Bool) {
+	comp := comparatee.compVal()
+	switch column.width {
+	case 1:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u8[index[i]]
+				bIndex[i] = enum != math.MaxUint8 && int(enum) >= comp
+			}
+		}
+	case 2:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u16[index[i]]
+				bIndex[i] = enum != math.MaxUint16 && int(enum) >= comp
+			}
+		}
+	default:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u32[index[i]]
+				bIndex[i] = enum != math.MaxUint32 && int(enum) >= comp
+			}
 		}
 	}
 }
 
-func eq(index index.Int, column []enumVal, comparatee enumVal, bIndex index.Bool) {
-	for i, x := range bIndex {
-		if !x {
-			enum := column[index[i]]
-			bIndex[i] = !enum.isNull() && enum.compVal() == comparatee.compVal()
+func eq(index index.Int, column codes, comparatee enumVal, bIndex index.Bool) {
+	comp := comparatee.compVal()
+	switch column.width {
+	case 1:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u8[index[i]]
+				bIndex[i] = enum != math.MaxUint8 && int(enum) == comp
+			}
+		}
+	case 2:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u16[index[i]]
+				bIndex[i] = enum != math.MaxUint16 && int(enum) == comp
+			}
+		}
+	default:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u32[index[i]]
+				bIndex[i] = enum != math.MaxUint32 && int(enum) == comp
+			}
 		}
 	}
 }
 
-func lt2(index index.Int, col, col2 []enumVal, bIndex index.Bool) {
-	for i, x := range bIndex {
-		if !x {
-			enum, enum2 := col[index[i]], col2[index[i]]
-			bIndex[i] = !enum.isNull() && !enum2.isNull() && enum.compVal() < enum2.compVal()
+func lt2(index index.Int, col, col2 codes, bIndex index.Bool) {
+	switch col.width {
+	case 1:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u8[index[i]], col2.u8[index[i]]
+				bIndex[i] = enum != math.MaxUint8 && enum2 != math.MaxUint8 && enum < enum2
+			}
+		}
+	case 2:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u16[index[i]], col2.u16[index[i]]
+				bIndex[i] = enum != math.MaxUint16 && enum2 != math.MaxUint16 && enum < enum2
+			}
+		}
+	default:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u32[index[i]], col2.u32[index[i]]
+				bIndex[i] = enum != math.MaxUint32 && enum2 != math.MaxUint32 && enum < enum2
+			}
 		}
 	}
 }
 
-func lte2(index index.Int, col, col2 []enumVal, bIndex index.Bool) {
-	for i, x := range bIndex {
-		if !x {
-			enum, enum2 := col[index[i]], col2[index[i]]
-			bIndex[i] = !enum.isNull() && !enum2.isNull() && enum.compVal() <= enum2.compVal()
+func lte2(index index.Int, col, col2 codes, bIndex index.Bool) {
+	switch col.width {
+	case 1:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u8[index[i]], col2.u8[index[i]]
+				bIndex[i] = enum != math.MaxUint8 && enum2 != math.MaxUint8 && enum <= enum2
+			}
+		}
+	case 2:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u16[index[i]], col2.u16[index[i]]
+				bIndex[i] = enum != math.MaxUint16 && enum2 != math.MaxUint16 && enum <= enum2
+			}
+		}
+	default:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u32[index[i]], col2.u32[index[i]]
+				bIndex[i] = enum != math.MaxUint32 && enum2 != math.MaxUint32 && enum <= enum2
+			}
 		}
 	}
 }
 
-func gt2(index index.Int, col, col2 []enumVal, bIndex index.Bool) {
-	for i, x := range bIndex {
-		if !x {
-			enum, enum2 := col[index[i]], col2[index[i]]
-			bIndex[i] = !enum.isNull() && !enum2.isNull() && enum.compVal() > enum2.compVal()
+func gt2(index index.Int, col, col2 codes, bIndex index.Bool) {
+	switch col.width {
+	case 1:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u8[index[i]], col2.u8[index[i]]
+				bIndex[i] = enum != math.MaxUint8 && enum2 != math.MaxUint8 && enum > enum2
+			}
+		}
+	case 2:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u16[index[i]], col2.u16[index[i]]
+				bIndex[i] = enum != math.MaxUint16 && enum2 != math.MaxUint16 && enum > enum2
+			}
+		}
+	default:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u32[index[i]], col2.u32[index[i]]
+				bIndex[i] = enum != math.MaxUint32 && enum2 != math.MaxUint32 && enum > enum2
+			}
 		}
 	}
 }
 
-func gte2(index index.Int, col, col2 []enumVal, bIndex index.Bool) {
-	for i, x := range bIndex {
-		if !x {
-			enum, enum2 := col[index[i]], col2[index[i]]
-			bIndex[i] = !enum.isNull() && !enum2.isNull() && enum.compVal() >= enum2.compVal()
+func gte2(index index.Int, col, col2 codes, bIndex index.Bool) {
+	switch col.width {
+	case 1:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u8[index[i]], col2.u8[index[i]]
+				bIndex[i] = enum != math.MaxUint8 && enum2 != math.MaxUint8 && enum >= enum2
+			}
+		}
+	case 2:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u16[index[i]], col2.u16[index[i]]
+				bIndex[i] = enum != math.MaxUint16 && enum2 != math.MaxUint16 && enum >= enum2
+			}
+		}
+	default:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u32[index[i]], col2.u32[index[i]]
+				bIndex[i] = enum != math.MaxUint32 && enum2 != math.MaxUint32 && enum >= enum2
+			}
 		}
 	}
 }
 
-func eq2(index index.Int, col, col2 []enumVal, bIndex index.Bool) {
-	for i, x := range bIndex {
-		if !x {
-			enum, enum2 := col[index[i]], col2[index[i]]
-			bIndex[i] = !enum.isNull() && !enum2.isNull() && enum.compVal() == enum2.compVal()
+func eq2(index index.Int, col, col2 codes, bIndex index.Bool) {
+	switch col.width {
+	case 1:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u8[index[i]], col2.u8[index[i]]
+				bIndex[i] = enum != math.MaxUint8 && enum2 != math.MaxUint8 && enum == enum2
+			}
+		}
+	case 2:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u16[index[i]], col2.u16[index[i]]
+				bIndex[i] = enum != math.MaxUint16 && enum2 != math.MaxUint16 && enum == enum2
+			}
+		}
+	default:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u32[index[i]], col2.u32[index[i]]
+				bIndex[i] = enum != math.MaxUint32 && enum2 != math.MaxUint32 && enum == enum2
+			}
 		}
 	}
 }
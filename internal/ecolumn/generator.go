@@ -11,23 +11,61 @@ import (
 //go:generate qfgenerate -source=efilter -dst-file=filters_gen.go
 //go:generate qfgenerate -source=edoc -dst-file=doc_gen.go
 
+// The comparisons switch on the width of the codes once and then loop over the codes
+// of that width, null is represented by the largest value of each width.
 const basicColConstComparison = `
-func {{.name}}(index index.Int, column []enumVal, comparatee enumVal, bIndex index.Bool) {
-	for i, x := range bIndex {
-		if !x {
-			enum := column[index[i]]
-			bIndex[i] = !enum.isNull() && enum.compVal() {{.operator}} comparatee.compVal()
+func {{.name}}(index index.Int, column codes, comparatee enumVal, bIndex index.Bool) {
+	comp := comparatee.compVal()
+	switch column.width {
+	case 1:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u8[index[i]]
+				bIndex[i] = enum != math.MaxUint8 && int(enum) {{.operator}} comp
+			}
+		}
+	case 2:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u16[index[i]]
+				bIndex[i] = enum != math.MaxUint16 && int(enum) {{.operator}} comp
+			}
+		}
+	default:
+		for i, x := range bIndex {
+			if !x {
+				enum := column.u32[index[i]]
+				bIndex[i] = enum != math.MaxUint32 && int(enum) {{.operator}} comp
+			}
 		}
 	}
 }
 `
 
+// Both columns must have the same width, see sameWidth.
 const basicColColComparison = `
-func {{.name}}(index index.Int, col, col2 []enumVal, bIndex index.Bool) {
-	for i, x := range bIndex {
-		if !x {
-			enum, enum2 := col[index[i]], col2[index[i]]
-			bIndex[i] = !enum.isNull() && !enum2.isNull() && enum.compVal() {{.operator}} enum2.compVal()
+func {{.name}}(index index.Int, col, col2 codes, bIndex index.Bool) {
+	switch col.width {
+	case 1:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u8[index[i]], col2.u8[index[i]]
+				bIndex[i] = enum != math.MaxUint8 && enum2 != math.MaxUint8 && enum {{.operator}} enum2
+			}
+		}
+	case 2:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u16[index[i]], col2.u16[index[i]]
+				bIndex[i] = enum != math.MaxUint16 && enum2 != math.MaxUint16 && enum {{.operator}} enum2
+			}
+		}
+	default:
+		for i, x := range bIndex {
+			if !x {
+				enum, enum2 := col.u32[index[i]], col2.u32[index[i]]
+				bIndex[i] = enum != math.MaxUint32 && enum2 != math.MaxUint32 && enum {{.operator}} enum2
+			}
 		}
 	}
 }
@@ -51,7 +89,7 @@ func colColComparison(name, operator string) template.Spec {
 func GenerateFilters() (*bytes.Buffer, error) {
 	// If adding more filters here make sure to also add a reference to them
 	// in the corresponding filter map so that they can be looked up.
-	return template.GenerateFilters("ecolumn", []template.Spec{
+	return template.Generate("ecolumn", []template.Spec{
 		colConstComparison("lt", filter.Lt),
 		colConstComparison("lte", filter.Lte),
 		colConstComparison("gt", filter.Gt),
@@ -62,12 +100,12 @@ func GenerateFilters() (*bytes.Buffer, error) {
 		colColComparison("gt2", filter.Gt),
 		colColComparison("gte2", filter.Gte),
 		colColComparison("eq2", "=="), // Go eq ("==") differs from qframe eq ("=")
-	})
+	}, []string{"math", "github.com/tobgu/qframe/internal/index"})
 }
 
 func GenerateDoc() (*bytes.Buffer, error) {
 	return template.GenerateDocs(
 		"ecolumn",
 		maps.StringKeys(filterFuncs0, filterFuncs1, filterFuncs2, multiFilterFuncs, multiInputFilterFuncs),
-		maps.StringKeys())
+		maps.StringKeys(aggregations))
 }
@@ -1,6 +1,11 @@
 package fastcsv
 
-import "io"
+import (
+	"bytes"
+	"io"
+)
+
+const maxConsecutiveEmptyReads = 100
 
 type bufferedReader struct {
 	r      io.Reader
@@ -15,10 +20,16 @@ func (b *bufferedReader) more() error {
 		b.data = temp
 	}
 
-	// read the new bytes onto the end of the buffer
-	n, err := b.r.Read(b.data[len(b.data):cap(b.data)])
-	b.data = b.data[:len(b.data)+n]
-	return err
+	// read the new bytes onto the end of the buffer. Readers may return no data
+	// without an error, keep reading until there is data or an error, as bufio does.
+	for i := 0; i < maxConsecutiveEmptyReads; i++ {
+		n, err := b.r.Read(b.data[len(b.data):cap(b.data)])
+		b.data = b.data[:len(b.data)+n]
+		if n > 0 || err != nil {
+			return err
+		}
+	}
+	return io.ErrNoProgress
 }
 
 func (b *bufferedReader) reset() {
@@ -151,10 +162,72 @@ func (fs *fields) next() bool {
 type Reader struct {
 	fields       fields
 	fieldsBuffer [][]byte
+	comment      byte
+}
+
+// SetComment configures lines starting with comment to be skipped. 0 (default) disables comments.
+func (r *Reader) SetComment(comment byte) {
+	r.comment = comment
+}
+
+// SkipLines skips the next n lines without parsing them. Returns io.EOF if the
+// input ends before all lines have been skipped.
+func (r *Reader) SkipLines(n int) error {
+	for i := 0; i < n; i++ {
+		if err := r.skipLine(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Reader) skipLine() error {
+	if r.fields.err != nil {
+		return r.fields.err
+	}
+
+	r.fields.reset()
+	b := &r.fields.buffer
+	for {
+		if i := bytes.IndexByte(b.data[b.cursor:], '\n'); i >= 0 {
+			b.cursor += i + 1
+			return nil
+		}
+
+		b.cursor = len(b.data)
+		if err := b.more(); err != nil {
+			r.fields.err = err
+			return err
+		}
+	}
+}
+
+// isComment reports if the next line starts with the comment character.
+func (r *Reader) isComment() bool {
+	if r.comment == 0 || r.fields.err != nil {
+		return false
+	}
+
+	r.fields.reset()
+	b := &r.fields.buffer
+	if b.cursor >= len(b.data) {
+		if err := b.more(); err != nil && b.cursor >= len(b.data) {
+			r.fields.err = err
+			return false
+		}
+	}
+
+	return b.data[b.cursor] == r.comment
 }
 
 // Scans in the next row
 func (r *Reader) Next() bool {
+	for r.isComment() {
+		if r.skipLine() != nil {
+			return false
+		}
+	}
+
 	if r.fields.err != nil {
 		return false
 	}
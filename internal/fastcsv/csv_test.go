@@ -209,3 +209,102 @@ func BenchmarkRead(b *testing.B) {
 		}
 	})
 }
+
+func TestReadCommentsAndSkipLines(t *testing.T) {
+	testCases := []struct {
+		Title      string
+		Input      string
+		Skip       int
+		Comment    byte
+		Wanted     [][]string
+		BufferCap  int
+		EmptyReads bool
+	}{{
+		Title:  "SkipLines",
+		Input:  "preamble\n\"quoted, preamble\"\nabc,def\n",
+		Skip:   2,
+		Wanted: [][]string{{"abc", "def"}},
+	}, {
+		Title:     "SkipLinesSmallBuffer",
+		Input:     "a long preamble line\nabc,def\nghi,jkl",
+		Skip:      1,
+		Wanted:    [][]string{{"abc", "def"}, {"ghi", "jkl"}},
+		BufferCap: 2,
+	}, {
+		Title:   "Comments",
+		Input:   "#comment\nabc,def\n# another comment\nghi,jkl\n#last",
+		Comment: '#',
+		Wanted:  [][]string{{"abc", "def"}, {"ghi", "jkl"}},
+	}, {
+		Title:     "CommentsSmallBuffer",
+		Input:     "abc,def\n#comment, with delimiter\nghi,#jkl\n",
+		Comment:   '#',
+		Wanted:    [][]string{{"abc", "def"}, {"ghi", "#jkl"}},
+		BufferCap: 1,
+	}, {
+		Title:      "CommentsEmptyReads",
+		Input:      "#comment\nabc,def\n#comment\nghi,jkl\n",
+		Comment:    '#',
+		Wanted:     [][]string{{"abc", "def"}, {"ghi", "jkl"}},
+		BufferCap:  1,
+		EmptyReads: true,
+	}}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Title, func(t *testing.T) {
+			var input io.Reader = strings.NewReader(testCase.Input)
+			if testCase.EmptyReads {
+				input = &emptyReadsReader{r: input}
+			}
+
+			r := Reader{
+				fields: fields{
+					buffer: bufferedReader{
+						r:    input,
+						data: make([]byte, 0, testCase.BufferCap),
+					},
+					delimiter: ',',
+				},
+				fieldsBuffer: make([][]byte, 0, 16),
+			}
+			r.SetComment(testCase.Comment)
+			if err := r.SkipLines(testCase.Skip); err != nil {
+				t.Fatalf("Unexpected error skipping lines: %v", err)
+			}
+
+			for i, wantedLine := range testCase.Wanted {
+				fields, err := r.Read()
+				if err != nil {
+					t.Fatalf("Unexpected error on line %d: %v", i+1, err)
+				}
+				if err := compareLine(fields, wantedLine...); err != nil {
+					t.Fatalf("Mismatch on line %d: %v", i+1, err)
+				}
+			}
+			if _, err := r.Read(); err != io.EOF {
+				t.Fatal("Wanted io.EOF; got:", err)
+			}
+		})
+	}
+}
+
+// emptyReadsReader returns no data, and no error, every other call to Read.
+type emptyReadsReader struct {
+	r     io.Reader
+	empty bool
+}
+
+func (r *emptyReadsReader) Read(p []byte) (int, error) {
+	r.empty = !r.empty
+	if r.empty {
+		return 0, nil
+	}
+	return r.r.Read(p)
+}
+
+func TestSkipLinesPastEnd(t *testing.T) {
+	r := NewReader(strings.NewReader("abc\n"), ',')
+	if err := r.SkipLines(2); err != io.EOF {
+		t.Fatal("Wanted io.EOF; got:", err)
+	}
+}
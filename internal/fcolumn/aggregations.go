@@ -1,7 +1,26 @@
 package fcolumn
 
-var aggregations = map[string]func([]float64) float64{
-	"sum": sum,
+import (
+	"math"
+	"sort"
+)
+
+// Unless stated otherwise the aggregations below ignore null (NaN) values.
+// NaN is returned if there are no non null values to aggregate.
+
+var aggregations = map[string]interface{}{
+	"count":          count,
+	"count_non_null": countNonNull,
+	"first":          first,
+	"last":           last,
+	"max":            max,
+	"mean":           mean,
+	"median":         median,
+	"min":            min,
+	"nunique":        nunique,
+	"std":            std,
+	"sum":            sum,
+	"var":            variance,
 }
 
 func sum(values []float64) float64 {
@@ -11,3 +30,106 @@ func sum(values []float64) float64 {
 	}
 	return result
 }
+
+func countNonNull(values []float64) int {
+	result := 0
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			result++
+		}
+	}
+	return result
+}
+
+func min(values []float64) float64 {
+	result := math.NaN()
+	for _, v := range values {
+		if v < result || math.IsNaN(result) {
+			result = v
+		}
+	}
+	return result
+}
+
+func max(values []float64) float64 {
+	result := math.NaN()
+	for _, v := range values {
+		if v > result || math.IsNaN(result) {
+			result = v
+		}
+	}
+	return result
+}
+
+func mean(values []float64) float64 {
+	result, count := 0.0, 0
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			result += v
+			count++
+		}
+	}
+
+	if count == 0 {
+		return math.NaN()
+	}
+	return result / float64(count)
+}
+
+// nonNull returns values with all NaN values removed. The content of values is modified.
+func nonNull(values []float64) []float64 {
+	result := values[:0]
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func median(values []float64) float64 {
+	// Modifying values in place is fine since it is a copy of the column data
+	values = nonNull(values)
+	if len(values) == 0 {
+		return math.NaN()
+	}
+
+	sort.Float64s(values)
+	middle := len(values) / 2
+	if len(values)%2 == 1 {
+		return values[middle]
+	}
+	return (values[middle-1] + values[middle]) / 2
+}
+
+// variance calculates the sample variance (N-1 in the denominator).
+func variance(values []float64) float64 {
+	m := mean(values)
+	result, count := 0.0, 0
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			d := v - m
+			result += d * d
+			count++
+		}
+	}
+
+	if count < 2 {
+		return math.NaN()
+	}
+	return result / float64(count-1)
+}
+
+func std(values []float64) float64 {
+	return math.Sqrt(variance(values))
+}
+
+func nunique(values []float64) int {
+	set := make(map[float64]struct{}, len(values))
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			set[v] = struct{}{}
+		}
+	}
+	return len(set)
+}
@@ -13,6 +13,192 @@ import (
 	"github.com/tobgu/qframe/types"
 )
 
+// Float columns use NaN to represent null values. The parts of the column that
+// differ from the nullable int and bool columns are found below.
+
+type Column struct {
+	data []float64
+}
+
+// Concat returns a new column holding the elements at positions ixs[i] in cols[i] for all columns, in order.
+func Concat(cols []Column, ixs []index.Int) Column {
+	size := 0
+	for _, ix := range ixs {
+		size += len(ix)
+	}
+
+	data := make([]float64, 0, size)
+	for i, c := range cols {
+		for _, j := range ixs[i] {
+			data = append(data, c.data[j])
+		}
+	}
+
+	return Column{data: data}
+}
+
+// Apply single argument function. The result may be a column
+// of a different type than the current column.
+func (c Column) Apply1(fn interface{}, ix index.Int) (interface{}, error) {
+	switch t := fn.(type) {
+	case func(float64) int:
+		result := make([]int, len(c.data))
+		for _, i := range ix {
+			result[i] = t(c.data[i])
+		}
+		return result, nil
+	case func(float64) float64:
+		result := make([]float64, len(c.data))
+		for _, i := range ix {
+			result[i] = t(c.data[i])
+		}
+		return result, nil
+	case func(float64) bool:
+		result := make([]bool, len(c.data))
+		for _, i := range ix {
+			result[i] = t(c.data[i])
+		}
+		return result, nil
+	case func(float64) *string:
+		result := make([]*string, len(c.data))
+		for _, i := range ix {
+			result[i] = t(c.data[i])
+		}
+		return result, nil
+	default:
+		return nil, errors.New(c.fnName("Apply1"), "cannot apply type %#v to column", fn)
+	}
+}
+
+// Apply double argument function to two columns. Both columns must have the
+// same type. The resulting column will have the same type as this column.
+func (c Column) Apply2(fn interface{}, s2 column.Column, ix index.Int) (column.Column, error) {
+	ss2, ok := s2.(Column)
+	if !ok {
+		return Column{}, errors.New(c.fnName("Apply2"), "invalid column type: %s", s2.DataType())
+	}
+
+	t, ok := fn.(func(float64, float64) float64)
+	if !ok {
+		return Column{}, errors.New("Apply2", "invalid function type: %#v", fn)
+	}
+
+	result := make([]float64, len(c.data))
+	for _, i := range ix {
+		result[i] = t(c.data[i], ss2.data[i])
+	}
+
+	return New(result), nil
+}
+
+func (c Column) subset(index index.Int) Column {
+	data := make([]float64, len(index))
+	for i, ix := range index {
+		data[i] = c.data[ix]
+	}
+
+	return Column{data: data}
+}
+
+func (c Column) Comparable(reverse, equalNull bool) column.Comparable {
+	result := Comparable{data: c.data, ltValue: column.LessThan, gtValue: column.GreaterThan, equalNullValue: column.NotEqual}
+	if reverse {
+		result.ltValue, result.gtValue = result.gtValue, result.ltValue
+	}
+
+	if equalNull {
+		result.equalNullValue = column.Equal
+	}
+
+	return result
+}
+
+// Aggregate applies fn to the elements of each index in indices. The result
+// may be a column of a different type than the current column. The built in
+// function count counts all values, including null.
+func (c Column) Aggregate(indices []index.Int, fn interface{}) (interface{}, error) {
+	if name, ok := fn.(string); ok {
+		if name == "count" {
+			result := make([]int, len(indices))
+			for i, ix := range indices {
+				result[i] = len(ix)
+			}
+			return result, nil
+		}
+
+		fn, ok = aggregations[name]
+		if !ok {
+			return nil, errors.New(c.fnName("Aggregate"), "aggregation function %s is not defined for column", name)
+		}
+	}
+
+	var buf []float64
+	switch t := fn.(type) {
+	case func([]float64) int:
+		result := make([]int, len(indices))
+		for i, ix := range indices {
+			result[i] = t(c.subsetWithBuf(ix, &buf).data)
+		}
+		return result, nil
+	case func([]float64) float64:
+		result := make([]float64, len(indices))
+		for i, ix := range indices {
+			result[i] = t(c.subsetWithBuf(ix, &buf).data)
+		}
+		return result, nil
+	case func([]float64) bool:
+		result := make([]bool, len(indices))
+		for i, ix := range indices {
+			result[i] = t(c.subsetWithBuf(ix, &buf).data)
+		}
+		return result, nil
+	case func([]float64) *string:
+		result := make([]*string, len(indices))
+		for i, ix := range indices {
+			result[i] = t(c.subsetWithBuf(ix, &buf).data)
+		}
+		return result, nil
+	default:
+		return nil, errors.New(c.fnName("Aggregate"), "invalid aggregation function type: %v", t)
+	}
+}
+
+// subsetWithBuf returns the values at the positions in index, reusing buf if possible.
+func (c Column) subsetWithBuf(index index.Int, buf *[]float64) Column {
+	if cap(*buf) < len(index) {
+		*buf = make([]float64, 0, len(index))
+	}
+
+	data := (*buf)[:0]
+	for _, ix := range index {
+		data = append(data, c.data[ix])
+	}
+
+	return Column{data: data}
+}
+
+func (c Column) View(ix index.Int) View {
+	return View{data: c.data, index: ix}
+}
+
+// Raw returns the data backing the column. It may not be modified.
+func (c Column) Raw() []float64 {
+	return c.data
+}
+
+type Comparable struct {
+	data           []float64
+	ltValue        column.CompareResult
+	gtValue        column.CompareResult
+	equalNullValue column.CompareResult
+}
+
+// View is a view into a column that allows access to individual elements by index.
+type View struct {
+	data  []float64
+	index index.Int
+}
+
 func (c Column) DataType() types.DataType {
 	return types.Float
 }
@@ -167,3 +353,23 @@ func (c Column) Filter(index index.Int, comparator interface{}, comparatee inter
 func (c Column) FunctionType() types.FunctionType {
 	return types.FunctionTypeFloat
 }
+
+// NullableSubset works like Subset with the exception that positions in
+// index equal to nullPos result in null (NaN) values.
+func (c Column) NullableSubset(index index.Int, nullPos uint32) column.Column {
+	data := make([]float64, len(index))
+	for i, ix := range index {
+		if ix == nullPos {
+			data[i] = math.NaN()
+		} else {
+			data[i] = c.data[ix]
+		}
+	}
+
+	return Column{data: data}
+}
+
+// IsNull returns true if the value at position i is null (NaN).
+func (v View) IsNull(i int) bool {
+	return math.IsNaN(v.ItemAt(i))
+}
@@ -14,6 +14,17 @@ func Doc() string {
 		"  isnull\n" +
 
 		"\n Built in aggregations\n" +
+		"  count\n" +
+		"  count_non_null\n" +
+		"  first\n" +
+		"  last\n" +
+		"  max\n" +
+		"  mean\n" +
+		"  median\n" +
+		"  min\n" +
+		"  nunique\n" +
+		"  std\n" +
 		"  sum\n" +
+		"  var\n" +
 		"\n"
 }
@@ -59,12 +59,16 @@ func (t *table) grow() {
 }
 
 func (t *table) hash(i uint32) uint32 {
-	t.hashBuf.Reset()
-	for _, c := range t.comparables {
-		c.HashBytes(i, t.hashBuf)
+	return hashRow(t.hashBuf, t.comparables, i)
+}
+
+func hashRow(buf *hash.Murm32, comparables []column.Comparable, i uint32) uint32 {
+	buf.Reset()
+	for _, c := range comparables {
+		c.HashBytes(i, buf)
 	}
 
-	return t.hashBuf.Hash()
+	return buf.Hash()
 }
 
 const maxLoadFactor = 0.5
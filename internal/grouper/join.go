@@ -0,0 +1,211 @@
+package grouper
+
+import (
+	"bytes"
+	"math"
+
+	"github.com/tobgu/qframe/internal/column"
+	"github.com/tobgu/qframe/internal/ecolumn"
+	"github.com/tobgu/qframe/internal/hash"
+	"github.com/tobgu/qframe/internal/index"
+	"github.com/tobgu/qframe/internal/scolumn"
+	qfstrings "github.com/tobgu/qframe/internal/strings"
+)
+
+/*
+The join table reuses the group by hash table to group all rows of one frame (the build side)
+by their key columns. Rows from another frame (the probe side) are then looked up in
+the table by hashing their key columns the same way.
+
+For lookups to work the hashes of equal keys must be equal across the two frames. This holds
+for all column types that hash their actual values. Enums normally hash their codes which
+differ between enums with different values. Enum and string keys are therefore hashed and
+compared using the bytes of their values, read in place from the enum values and the string data.
+*/
+
+// bytesKey gives access to the bytes of the values in a string or enum column.
+type bytesKey interface {
+	bytesAt(i uint32) ([]byte, bool)
+}
+
+type stringKey struct {
+	pointers []qfstrings.Pointer
+	data     []byte
+}
+
+func (k stringKey) bytesAt(i uint32) ([]byte, bool) {
+	p := k.pointers[i]
+	if p.IsNull() {
+		return nil, false
+	}
+	return k.data[p.Offset() : p.Offset()+p.Len()], true
+}
+
+// One enum key type per code width to avoid switching on the width for every row.
+type enum8Key struct {
+	codes  []uint8
+	values [][]byte
+}
+
+func (k enum8Key) bytesAt(i uint32) ([]byte, bool) {
+	if v := k.codes[i]; v != math.MaxUint8 {
+		return k.values[v], true
+	}
+	return nil, false
+}
+
+type enum16Key struct {
+	codes  []uint16
+	values [][]byte
+}
+
+func (k enum16Key) bytesAt(i uint32) ([]byte, bool) {
+	if v := k.codes[i]; v != math.MaxUint16 {
+		return k.values[v], true
+	}
+	return nil, false
+}
+
+type enum32Key struct {
+	codes  []uint32
+	values [][]byte
+}
+
+func (k enum32Key) bytesAt(i uint32) ([]byte, bool) {
+	if v := k.codes[i]; v != math.MaxUint32 {
+		return k.values[v], true
+	}
+	return nil, false
+}
+
+func newEnumKey(col ecolumn.Column) bytesKey {
+	values := make([][]byte, len(col.Values()))
+	for i, v := range col.Values() {
+		values[i] = []byte(v)
+	}
+
+	switch codes := col.Codes().(type) {
+	case []uint8:
+		return enum8Key{codes: codes, values: values}
+	case []uint16:
+		return enum16Key{codes: codes, values: values}
+	default:
+		return enum32Key{codes: codes.([]uint32), values: values}
+	}
+}
+
+// Key is a key column of one of the frames taking part in a join. Comparisons
+// between rows of the same frame are done using the comparable of the column.
+type Key struct {
+	column.Comparable
+	col   column.Column
+	bytes bytesKey
+}
+
+// NewKey creates a new join key from col.
+func NewKey(col column.Column) Key {
+	k := Key{Comparable: col.Comparable(false, false), col: col}
+	switch c := col.(type) {
+	case ecolumn.Column:
+		k.bytes = newEnumKey(c)
+	case scolumn.Column:
+		pointers, data := c.Raw()
+		k.bytes = stringKey{pointers: pointers, data: data}
+	}
+	return k
+}
+
+// HashBytes hashes the value at position i in a way that is consistent across frames.
+func (k Key) HashBytes(i uint32, buf *hash.Murm32) {
+	if k.bytes == nil {
+		k.Comparable.HashBytes(i, buf)
+		return
+	}
+
+	if b, ok := k.bytes.bytesAt(i); ok {
+		buf.Write(b)
+	} else {
+		// Null never equals null in joins, see the string comparable
+		buf.WriteRand32()
+	}
+}
+
+// IsNull returns true if the value at position i is null.
+func (k Key) IsNull(i uint32) bool {
+	// Null is the only value that does not equal itself
+	return k.Compare(i, i) == column.NotEqual
+}
+
+func keysAsComparables(keys []Key) []column.Comparable {
+	result := make([]column.Comparable, len(keys))
+	for i, k := range keys {
+		result[i] = k
+	}
+	return result
+}
+
+// JoinTable is a hash table over the rows of one frame that can be probed with rows from another frame.
+type JoinTable struct {
+	entries            []tableEntry
+	keys               []Key
+	hashBuf            *hash.Murm32
+	probeBuf, buildBuf index.Int
+}
+
+// NewJoinTable groups the rows in ix by the values in keys.
+func NewJoinTable(ix index.Int, keys []Key) JoinTable {
+	entries, _ := groupIndex(ix, keysAsComparables(keys), true)
+	return JoinTable{entries: entries, keys: keys, hashBuf: new(hash.Murm32), probeBuf: index.Int{0}, buildBuf: index.Int{0}}
+}
+
+// equals returns true if row i in probe equals row j in the table.
+func (t JoinTable) equals(probe []Key, i, j uint32) bool {
+	for n, k := range probe {
+		build := t.keys[n]
+		if k.bytes != nil && build.bytes != nil {
+			x, xOk := k.bytes.bytesAt(i)
+			y, yOk := build.bytes.bytesAt(j)
+			if !xOk || !yOk || !bytes.Equal(x, y) {
+				return false
+			}
+			continue
+		}
+
+		t.probeBuf[0], t.buildBuf[0] = i, j
+		if !k.col.Equals(t.probeBuf, build.col, t.buildBuf) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Lookup returns the positions of all rows in the table with a key equal to that of row i in probe.
+// probe must hold the key columns of the probing frame in the same order as the keys of the table.
+// nil is returned if there is no match.
+func (t JoinTable) Lookup(i uint32, probe []Key) index.Int {
+	if len(t.entries) == 0 {
+		return nil
+	}
+
+	t.hashBuf.Reset()
+	for _, k := range probe {
+		k.HashBytes(i, t.hashBuf)
+	}
+
+	hashSum := t.hashBuf.Hash()
+	bitMask := uint64(len(t.entries) - 1)
+	for pos := uint64(hashSum) & bitMask; ; pos = (pos + 1) & bitMask {
+		e := &t.entries[pos]
+		if !e.occupied {
+			return nil
+		}
+
+		if e.hash == hashSum && t.equals(probe, i, e.firstPos) {
+			if e.ix == nil {
+				return index.Int{e.firstPos}
+			}
+			return e.ix
+		}
+	}
+}
@@ -1,5 +1,10 @@
 package icolumn
 
+import (
+	"math"
+	"sort"
+)
+
 func sum(values []int) int {
 	result := 0
 	for _, v := range values {
@@ -8,6 +13,94 @@ func sum(values []int) int {
 	return result
 }
 
-var aggregations = map[string]func([]int) int{
-	"sum": sum,
+func min(values []int) int {
+	if len(values) == 0 {
+		return 0
+	}
+
+	result := values[0]
+	for _, v := range values[1:] {
+		if v < result {
+			result = v
+		}
+	}
+	return result
+}
+
+func max(values []int) int {
+	if len(values) == 0 {
+		return 0
+	}
+
+	result := values[0]
+	for _, v := range values[1:] {
+		if v > result {
+			result = v
+		}
+	}
+	return result
+}
+
+func mean(values []int) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+
+	return float64(sum(values)) / float64(len(values))
+}
+
+func median(values []int) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+
+	// Sorting in place is fine since values is a copy of the column data
+	sort.Ints(values)
+	middle := len(values) / 2
+	if len(values)%2 == 1 {
+		return float64(values[middle])
+	}
+	return (float64(values[middle-1]) + float64(values[middle])) / 2
+}
+
+// variance calculates the sample variance (N-1 in the denominator).
+func variance(values []int) float64 {
+	if len(values) < 2 {
+		return math.NaN()
+	}
+
+	m := mean(values)
+	result := 0.0
+	for _, v := range values {
+		d := float64(v) - m
+		result += d * d
+	}
+	return result / float64(len(values)-1)
+}
+
+func std(values []int) float64 {
+	return math.Sqrt(variance(values))
+}
+
+func nunique(values []int) int {
+	set := make(map[int]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return len(set)
+}
+
+var aggregations = map[string]interface{}{
+	"count":          count,
+	"count_non_null": count,
+	"first":          first,
+	"last":           last,
+	"max":            max,
+	"mean":           mean,
+	"median":         median,
+	"min":            min,
+	"nunique":        nunique,
+	"std":            std,
+	"sum":            sum,
+	"var":            variance,
 }
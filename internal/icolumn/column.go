@@ -6,6 +6,7 @@ import (
 	"unsafe"
 
 	"github.com/tobgu/qframe/errors"
+	"github.com/tobgu/qframe/internal/bitmap"
 	"github.com/tobgu/qframe/internal/column"
 	"github.com/tobgu/qframe/internal/hash"
 	"github.com/tobgu/qframe/internal/index"
@@ -16,17 +17,23 @@ func (c Column) DataType() types.DataType {
 	return types.Int
 }
 
-func (c Column) StringAt(i uint32, _ string) string {
+func (c Column) StringAt(i uint32, naRep string) string {
+	if c.isNull(i) {
+		return naRep
+	}
 	return strconv.FormatInt(int64(c.data[i]), 10)
 }
 
 func (c Column) AppendByteStringAt(buf []byte, i uint32) []byte {
+	if c.isNull(i) {
+		return append(buf, "null"...)
+	}
 	return strconv.AppendInt(buf, int64(c.data[i]), 10)
 }
 
 func (c Column) ByteSize() int {
-	// Slice header + data
-	return 2*8 + 8*len(c.data)
+	// Slice header + data + validity
+	return 2*8 + 8*len(c.data) + c.valid.ByteSize()
 }
 
 func (c Column) Equals(index index.Int, other column.Column, otherIndex index.Int) bool {
@@ -36,7 +43,8 @@ func (c Column) Equals(index index.Int, other column.Column, otherIndex index.In
 	}
 
 	for ix, x := range index {
-		if c.data[x] != otherI.data[otherIndex[ix]] {
+		xNull, yNull := c.isNull(x), otherI.isNull(otherIndex[ix])
+		if xNull != yNull || (!xNull && c.data[x] != otherI.data[otherIndex[ix]]) {
 			return false
 		}
 	}
@@ -45,6 +53,12 @@ func (c Column) Equals(index index.Int, other column.Column, otherIndex index.In
 }
 
 func (c Comparable) Compare(i, j uint32) column.CompareResult {
+	if c.valid != nil {
+		if result, ok := c.compareNull(i, j); ok {
+			return result
+		}
+	}
+
 	x, y := c.data[i], c.data[j]
 	if x < y {
 		return c.ltValue
@@ -58,6 +72,17 @@ func (c Comparable) Compare(i, j uint32) column.CompareResult {
 }
 
 func (c Comparable) HashBytes(i uint32, buf *hash.Murm32) {
+	if c.valid.IsNull(i) {
+		if c.equalNullValue == column.NotEqual {
+			// Use a random value here to avoid hash collisions when
+			// we don't consider null to equal null.
+			buf.WriteRand32()
+		} else {
+			buf.WriteByte(0)
+		}
+		return
+	}
+
 	x := &c.data[i]
 	b := (*[8]byte)(unsafe.Pointer(x))[:]
 	buf.Write(b)
@@ -172,20 +197,72 @@ func (c Column) filterCustom2(index index.Int, fn func(int, int) bool, comparate
 }
 
 func (c Column) Filter(index index.Int, comparator interface{}, comparatee interface{}, bIndex index.Bool) error {
-	var err error
-	switch t := comparator.(type) {
-	case string:
-		err = c.filterBuiltIn(index, t, comparatee, bIndex)
-	case func(int) bool:
-		c.filterCustom1(index, t, bIndex)
-	case func(int, int) bool:
-		err = c.filterCustom2(index, t, comparatee, bIndex)
-	default:
-		err = errors.New("filter int", "invalid filter type %v", reflect.TypeOf(comparator))
-	}
-	return err
+	return c.filterWithNulls(index, comparator, comparatee, bIndex, func() error {
+		var err error
+		switch t := comparator.(type) {
+		case string:
+			err = c.filterBuiltIn(index, t, comparatee, bIndex)
+		case func(int) bool:
+			c.filterCustom1(index, t, bIndex)
+		case func(int, int) bool:
+			err = c.filterCustom2(index, t, comparatee, bIndex)
+		default:
+			err = errors.New("filter int", "invalid filter type %v", reflect.TypeOf(comparator))
+		}
+		return err
+	})
 }
 
 func (c Column) FunctionType() types.FunctionType {
 	return types.FunctionTypeInt
 }
+
+// NewNullable creates a new column from d. Positions that are null in valid
+// are null in the column. A nil valid means that the column contains no nulls.
+func NewNullable(d []int, valid bitmap.Bitmap) Column {
+	return Column{data: d, valid: valid}
+}
+
+// NewPointers creates a new column from d where nil pointers are null.
+func NewPointers(d []*int) Column {
+	var valid bitmap.Bitmap
+	data := make([]int, len(d))
+	for i, p := range d {
+		if p != nil {
+			data[i] = *p
+			continue
+		}
+
+		if valid == nil {
+			valid = bitmap.New(len(d), true)
+		}
+		valid.SetNull(uint32(i))
+	}
+
+	return Column{data: data, valid: valid}
+}
+
+// NewNull creates a new column containing count null values.
+func NewNull(count int) Column {
+	return Column{data: make([]int, count), valid: bitmap.New(count, false)}
+}
+
+// NullableSubset works like Subset with the exception that positions in
+// index equal to nullPos result in null values.
+func (c Column) NullableSubset(index index.Int, nullPos uint32) column.Column {
+	data, valid := make([]int, len(index)), bitmap.New(len(index), true)
+	for i, ix := range index {
+		if ix == nullPos || c.isNull(ix) {
+			valid.SetNull(uint32(i))
+		} else {
+			data[i] = c.data[ix]
+		}
+	}
+
+	return Column{data: data, valid: valid}
+}
+
+// IsNull returns true if the value at position i is null.
+func (v View) IsNull(i int) bool {
+	return v.valid.IsNull(v.index[i])
+}
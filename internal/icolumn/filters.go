@@ -5,6 +5,12 @@ import (
 	"github.com/tobgu/qframe/internal/index"
 )
 
+// Null checks, applied by Column.Filter using the validity of the values
+var nullFilters = map[string]struct{}{
+	filter.IsNull:    {},
+	filter.IsNotNull: {},
+}
+
 // Column - constant
 var filterFuncs = map[string]func(index.Int, []int, int, index.Bool){
 	filter.Gt:  gt,
@@ -48,6 +48,6 @@ func GenerateFilters() (*bytes.Buffer, error) {
 func GenerateDoc() (*bytes.Buffer, error) {
 	return template.GenerateDocs(
 		"icolumn",
-		maps.StringKeys(filterFuncs, filterFuncs2, multiInputFilterFuncs),
+		maps.StringKeys(nullFilters, filterFuncs, filterFuncs2, multiInputFilterFuncs),
 		maps.StringKeys(aggregations))
 }
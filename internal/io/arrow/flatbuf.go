@@ -0,0 +1,343 @@
+package arrow
+
+import (
+	"encoding/binary"
+)
+
+// The Arrow IPC metadata is encoded using flatbuffers. Only the small subset of
+// flatbuffers needed to read and write the Arrow messages is implemented here.
+// See https://google.github.io/flatbuffers/flatbuffers_internals.html for a
+// description of the format.
+
+var le = binary.LittleEndian
+
+// errInvalid is used in panics raised on malformed input, these are recovered
+// and turned into errors by the reader.
+type errInvalid string
+
+func checkBounds(buf []byte, pos, size int) {
+	if pos < 0 || size < 0 || pos+size > len(buf) || pos+size < pos {
+		panic(errInvalid("flatbuffer offset out of bounds"))
+	}
+}
+
+// fbTable is a table in a flatbuffer.
+type fbTable struct {
+	buf []byte
+	pos int
+}
+
+func fbRoot(buf []byte) fbTable {
+	checkBounds(buf, 0, 4)
+	return fbTable{buf: buf, pos: int(le.Uint32(buf))}
+}
+
+// fieldPos returns the position of field in the buffer, 0 if the field is not present.
+func (t fbTable) fieldPos(field int) int {
+	checkBounds(t.buf, t.pos, 4)
+	vtable := t.pos - int(int32(le.Uint32(t.buf[t.pos:])))
+	checkBounds(t.buf, vtable, 4)
+	vtableSize := int(le.Uint16(t.buf[vtable:]))
+	entry := 4 + 2*field
+	if entry+2 > vtableSize {
+		return 0
+	}
+
+	checkBounds(t.buf, vtable+entry, 2)
+	offset := int(le.Uint16(t.buf[vtable+entry:]))
+	if offset == 0 {
+		return 0
+	}
+	return t.pos + offset
+}
+
+func (t fbTable) uint8(field int, def uint8) uint8 {
+	pos := t.fieldPos(field)
+	if pos == 0 {
+		return def
+	}
+	checkBounds(t.buf, pos, 1)
+	return t.buf[pos]
+}
+
+func (t fbTable) bool(field int) bool {
+	return t.uint8(field, 0) != 0
+}
+
+func (t fbTable) int16(field int, def int16) int16 {
+	pos := t.fieldPos(field)
+	if pos == 0 {
+		return def
+	}
+	checkBounds(t.buf, pos, 2)
+	return int16(le.Uint16(t.buf[pos:]))
+}
+
+func (t fbTable) int32(field int, def int32) int32 {
+	pos := t.fieldPos(field)
+	if pos == 0 {
+		return def
+	}
+	checkBounds(t.buf, pos, 4)
+	return int32(le.Uint32(t.buf[pos:]))
+}
+
+func (t fbTable) int64(field int, def int64) int64 {
+	pos := t.fieldPos(field)
+	if pos == 0 {
+		return def
+	}
+	checkBounds(t.buf, pos, 8)
+	return int64(le.Uint64(t.buf[pos:]))
+}
+
+// indirect returns the position referenced by the offset stored at field, 0 if not present.
+func (t fbTable) indirect(field int) int {
+	pos := t.fieldPos(field)
+	if pos == 0 {
+		return 0
+	}
+	checkBounds(t.buf, pos, 4)
+	return pos + int(le.Uint32(t.buf[pos:]))
+}
+
+func (t fbTable) table(field int) (fbTable, bool) {
+	pos := t.indirect(field)
+	return fbTable{buf: t.buf, pos: pos}, pos != 0
+}
+
+func (t fbTable) string(field int) string {
+	start, length := t.vector(field)
+	checkBounds(t.buf, start, length)
+	return string(t.buf[start : start+length])
+}
+
+// vector returns the position of the first element and the length of the vector at field.
+func (t fbTable) vector(field int) (int, int) {
+	pos := t.indirect(field)
+	if pos == 0 {
+		return 0, 0
+	}
+	checkBounds(t.buf, pos, 4)
+	return pos + 4, int(le.Uint32(t.buf[pos:]))
+}
+
+// tables returns the tables of the vector of tables at field.
+func (t fbTable) tables(field int) []fbTable {
+	start, length := t.vector(field)
+	checkBounds(t.buf, start, 4*length)
+	result := make([]fbTable, length)
+	for i := range result {
+		pos := start + 4*i
+		result[i] = fbTable{buf: t.buf, pos: pos + int(le.Uint32(t.buf[pos:]))}
+	}
+	return result
+}
+
+// structs returns the raw bytes of the vector of structs of size structSize at field.
+func (t fbTable) structs(field int, structSize int) [][]byte {
+	start, length := t.vector(field)
+	checkBounds(t.buf, start, structSize*length)
+	result := make([][]byte, length)
+	for i := range result {
+		result[i] = t.buf[start+structSize*i : start+structSize*(i+1)]
+	}
+	return result
+}
+
+// fbObject is an object that can be serialized into a flatbuffer.
+type fbObject interface {
+	// write appends the object to the builder and returns its position.
+	write(b *fbBuilder) int
+}
+
+// fbBuilder serializes flatbuffers front to back. Objects referenced from a table,
+// or a vector, are written after it since flatbuffer offsets must point forward.
+type fbBuilder struct {
+	buf []byte
+}
+
+func (b *fbBuilder) align(n int) {
+	for len(b.buf)%n != 0 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+func (b *fbBuilder) patchOffset(pos int, target int) {
+	le.PutUint32(b.buf[pos:], uint32(target-pos))
+}
+
+// fbFinish serializes root into a flatbuffer, padded to a multiple of 8 bytes.
+func fbFinish(root fbObject) []byte {
+	b := &fbBuilder{buf: make([]byte, 4, 1024)}
+	pos := root.write(b)
+	b.patchOffset(0, pos)
+	b.align(8)
+	return b.buf
+}
+
+type fbField struct {
+	slot  int
+	size  int
+	value uint64
+	ref   fbObject
+}
+
+// fbTableBuilder describes a table to be serialized.
+type fbTableBuilder struct {
+	fields []fbField
+}
+
+func newTable() *fbTableBuilder {
+	return &fbTableBuilder{}
+}
+
+func (t *fbTableBuilder) scalar(slot, size int, value uint64) *fbTableBuilder {
+	t.fields = append(t.fields, fbField{slot: slot, size: size, value: value})
+	return t
+}
+
+func (t *fbTableBuilder) uint8(slot int, v uint8) *fbTableBuilder {
+	return t.scalar(slot, 1, uint64(v))
+}
+
+func (t *fbTableBuilder) bool(slot int, v bool) *fbTableBuilder {
+	if v {
+		return t.uint8(slot, 1)
+	}
+	return t.uint8(slot, 0)
+}
+
+func (t *fbTableBuilder) int16(slot int, v int16) *fbTableBuilder {
+	return t.scalar(slot, 2, uint64(uint16(v)))
+}
+
+func (t *fbTableBuilder) int32(slot int, v int32) *fbTableBuilder {
+	return t.scalar(slot, 4, uint64(uint32(v)))
+}
+
+func (t *fbTableBuilder) int64(slot int, v int64) *fbTableBuilder {
+	return t.scalar(slot, 8, uint64(v))
+}
+
+func (t *fbTableBuilder) ref(slot int, obj fbObject) *fbTableBuilder {
+	t.fields = append(t.fields, fbField{slot: slot, size: 4, ref: obj})
+	return t
+}
+
+func (t *fbTableBuilder) string(slot int, s string) *fbTableBuilder {
+	return t.ref(slot, fbString(s))
+}
+
+func (t *fbTableBuilder) write(b *fbBuilder) int {
+	slots := 0
+	for _, f := range t.fields {
+		if f.slot+1 > slots {
+			slots = f.slot + 1
+		}
+	}
+
+	// Fields are laid out largest first to minimize padding
+	ordered := make([]fbField, 0, len(t.fields))
+	for _, size := range []int{8, 4, 2, 1} {
+		for _, f := range t.fields {
+			if f.size == size {
+				ordered = append(ordered, f)
+			}
+		}
+	}
+
+	// The table starts with a 4 byte offset to the vtable, place it such that
+	// 8 byte fields following it are aligned.
+	vtableSize := 4 + 2*slots
+	b.align(2)
+	vtablePos := len(b.buf)
+	tablePos := vtablePos + vtableSize
+	for tablePos%8 != 4 {
+		tablePos++
+	}
+
+	fieldOffsets := make([]int, len(ordered))
+	tableSize := 4
+	for i, f := range ordered {
+		for (tablePos+tableSize)%f.size != 0 {
+			tableSize++
+		}
+		fieldOffsets[i] = tableSize
+		tableSize += f.size
+	}
+
+	b.buf = append(b.buf, make([]byte, tablePos+tableSize-vtablePos)...)
+	le.PutUint16(b.buf[vtablePos:], uint16(vtableSize))
+	le.PutUint16(b.buf[vtablePos+2:], uint16(tableSize))
+	le.PutUint32(b.buf[tablePos:], uint32(tablePos-vtablePos))
+	for i, f := range ordered {
+		le.PutUint16(b.buf[vtablePos+4+2*f.slot:], uint16(fieldOffsets[i]))
+		pos := tablePos + fieldOffsets[i]
+		switch f.size {
+		case 1:
+			b.buf[pos] = uint8(f.value)
+		case 2:
+			le.PutUint16(b.buf[pos:], uint16(f.value))
+		case 4:
+			le.PutUint32(b.buf[pos:], uint32(f.value))
+		case 8:
+			le.PutUint64(b.buf[pos:], f.value)
+		}
+	}
+
+	for i, f := range ordered {
+		if f.ref != nil {
+			b.patchOffset(tablePos+fieldOffsets[i], f.ref.write(b))
+		}
+	}
+
+	return tablePos
+}
+
+type fbString string
+
+func (s fbString) write(b *fbBuilder) int {
+	b.align(4)
+	pos := len(b.buf)
+	b.buf = append(b.buf, 0, 0, 0, 0)
+	le.PutUint32(b.buf[pos:], uint32(len(s)))
+	b.buf = append(b.buf, s...)
+	b.buf = append(b.buf, 0)
+	return pos
+}
+
+// fbTables is a vector of tables.
+type fbTables []fbObject
+
+func (v fbTables) write(b *fbBuilder) int {
+	b.align(4)
+	pos := len(b.buf)
+	b.buf = append(b.buf, make([]byte, 4+4*len(v))...)
+	le.PutUint32(b.buf[pos:], uint32(len(v)))
+	for i, t := range v {
+		b.patchOffset(pos+4+4*i, t.write(b))
+	}
+	return pos
+}
+
+// fbInt64Structs is a vector of structs consisting of int64 fields only.
+type fbInt64Structs struct {
+	fieldCount int
+	values     []int64
+}
+
+func (v fbInt64Structs) write(b *fbBuilder) int {
+	// The elements following the length must be 8 byte aligned
+	for len(b.buf)%8 != 4 {
+		b.buf = append(b.buf, 0)
+	}
+	pos := len(b.buf)
+	b.buf = append(b.buf, 0, 0, 0, 0)
+	le.PutUint32(b.buf[pos:], uint32(len(v.values)/v.fieldCount))
+	for _, x := range v.values {
+		b.buf = append(b.buf, 0, 0, 0, 0, 0, 0, 0, 0)
+		le.PutUint64(b.buf[len(b.buf)-8:], uint64(x))
+	}
+	return pos
+}
@@ -0,0 +1,91 @@
+package arrow
+
+// Constants from the Arrow flatbuffer schemas, Schema.fbs and Message.fbs.
+// See https://github.com/apache/arrow/tree/master/format
+
+// MetadataVersion
+const metadataV4 = 3
+
+// MessageHeader union
+const (
+	headerSchema          = 1
+	headerDictionaryBatch = 2
+	headerRecordBatch     = 3
+)
+
+// Type union
+const (
+	typeInt           = 2
+	typeFloatingPoint = 3
+	typeUtf8          = 5
+	typeBool          = 6
+	typeTimestamp     = 10
+	typeLargeUtf8     = 20
+)
+
+// Endianness of Schema
+const littleEndian = 0
+
+// Precision of FloatingPoint
+const (
+	precisionSingle = 1
+	precisionDouble = 2
+)
+
+// TimeUnit of Timestamp
+const (
+	unitSecond      = 0
+	unitMillisecond = 1
+	unitMicrosecond = 2
+	unitNanosecond  = 3
+)
+
+// Field slots of the tables used
+const (
+	messageVersion    = 0
+	messageHeaderType = 1
+	messageHeader     = 2
+	messageBodyLength = 3
+
+	schemaEndianness = 0
+	schemaFields     = 1
+
+	fieldName       = 0
+	fieldNullable   = 1
+	fieldTypeType   = 2
+	fieldType       = 3
+	fieldDictionary = 4
+	fieldChildren   = 5
+
+	intBitWidth = 0
+	intIsSigned = 1
+
+	floatPrecision = 0
+
+	timestampUnit     = 0
+	timestampTimezone = 1
+
+	dictEncodingID        = 0
+	dictEncodingIndexType = 1
+	dictEncodingIsOrdered = 2
+
+	recordBatchLength  = 0
+	recordBatchNodes   = 1
+	recordBatchBuffers = 2
+
+	dictBatchID      = 0
+	dictBatchData    = 1
+	dictBatchIsDelta = 2
+)
+
+// Size of the FieldNode and Buffer structs
+const (
+	fieldNodeSize = 16
+	bufferSize    = 16
+)
+
+// Marks the start of a message in streams written by Arrow >= 0.15
+const continuationMarker = 0xFFFFFFFF
+
+// Buffers in the message body are padded to a multiple of this
+const bodyAlignment = 8
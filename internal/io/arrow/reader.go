@@ -0,0 +1,575 @@
+package arrow
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/tobgu/qframe/errors"
+	"github.com/tobgu/qframe/internal/bcolumn"
+	"github.com/tobgu/qframe/internal/bitmap"
+	"github.com/tobgu/qframe/internal/ecolumn"
+	"github.com/tobgu/qframe/internal/icolumn"
+	qfstrings "github.com/tobgu/qframe/internal/strings"
+	"github.com/tobgu/qframe/internal/tcolumn"
+)
+
+type arrowType struct {
+	id        byte
+	bitWidth  int
+	signed    bool
+	precision int16
+	unit      int16
+	timezone  string
+}
+
+type field struct {
+	name       string
+	typ        arrowType
+	dictionary bool
+	dictID     int64
+	indexType  arrowType
+}
+
+type fieldNode struct {
+	length    int
+	nullCount int
+}
+
+// batch is a record batch being decoded, nodes and buffers are consumed in order.
+type batch struct {
+	length  int
+	nodes   []fieldNode
+	buffers [][]byte
+}
+
+func (b *batch) nextNode() fieldNode {
+	if len(b.nodes) == 0 {
+		panic(errInvalid("too few field nodes in record batch"))
+	}
+	n := b.nodes[0]
+	b.nodes = b.nodes[1:]
+	if n.length != b.length {
+		panic(errInvalid(fmt.Sprintf("field node length %d does not match record batch length %d", n.length, b.length)))
+	}
+	return n
+}
+
+func (b *batch) nextBuffer() []byte {
+	if len(b.buffers) == 0 {
+		panic(errInvalid("too few buffers in record batch"))
+	}
+	buf := b.buffers[0]
+	b.buffers = b.buffers[1:]
+	return buf
+}
+
+func readBatch(t fbTable, body []byte) *batch {
+	result := &batch{length: int(t.int64(recordBatchLength, 0))}
+	for _, n := range t.structs(recordBatchNodes, fieldNodeSize) {
+		result.nodes = append(result.nodes, fieldNode{length: int(le.Uint64(n)), nullCount: int(le.Uint64(n[8:]))})
+	}
+
+	// All supported arrays use at least one bit of the body per value
+	if result.length < 0 || (len(result.nodes) > 0 && result.length > 8*len(body)) {
+		panic(errInvalid(fmt.Sprintf("invalid record batch length: %d", result.length)))
+	}
+
+	for _, b := range t.structs(recordBatchBuffers, bufferSize) {
+		offset, length := int(le.Uint64(b)), int(le.Uint64(b[8:]))
+		checkBounds(body, offset, length)
+		result.buffers = append(result.buffers, body[offset:offset+length])
+	}
+
+	return result
+}
+
+func readType(typeType byte, t fbTable) arrowType {
+	result := arrowType{id: typeType}
+	switch typeType {
+	case typeInt:
+		result.bitWidth = int(t.int32(intBitWidth, 0))
+		result.signed = t.bool(intIsSigned)
+	case typeFloatingPoint:
+		result.precision = t.int16(floatPrecision, 0)
+	case typeTimestamp:
+		result.unit = t.int16(timestampUnit, 0)
+		result.timezone = t.string(timestampTimezone)
+	}
+	return result
+}
+
+func readSchema(t fbTable) []field {
+	fieldTables := t.tables(schemaFields)
+	result := make([]field, len(fieldTables))
+	for i, ft := range fieldTables {
+		typeTable, _ := ft.table(fieldType)
+		f := field{name: ft.string(fieldName), typ: readType(ft.uint8(fieldTypeType, 0), typeTable)}
+		if dict, ok := ft.table(fieldDictionary); ok {
+			f.dictionary = true
+			f.dictID = dict.int64(dictEncodingID, 0)
+			f.indexType = arrowType{id: typeInt, bitWidth: 32, signed: true}
+			if indexTable, ok := dict.table(dictEncodingIndexType); ok {
+				f.indexType = readType(typeInt, indexTable)
+			}
+		}
+		result[i] = f
+	}
+	return result
+}
+
+// checkSize panics if buf is too small to hold size bytes of values.
+func checkSize(buf []byte, size int) {
+	if len(buf) < size {
+		panic(errInvalid(fmt.Sprintf("buffer too small: %d bytes, expected %d", len(buf), size)))
+	}
+}
+
+// checkValidity panics if validity, when present, does not hold a bit per value of node.
+func checkValidity(validity []byte, node fieldNode) {
+	if node.nullCount > 0 && len(validity) > 0 {
+		checkSize(validity, (node.length+7)/8)
+	}
+}
+
+// values decoded from a record batch, the type depends on the column type.
+type columnData struct {
+	ints   []int
+	int64s []int64
+	floats []float64
+	bools  []bool
+	strs   []*string
+	nulls  []uint32
+	blob   qfstrings.StringBlob
+}
+
+func (c *columnData) len() int {
+	return len(c.ints) + len(c.int64s) + len(c.floats) + len(c.bools) + len(c.strs) + len(c.blob.Pointers)
+}
+
+func isValid(validity []byte, nullCount int, i int) bool {
+	if nullCount == 0 || len(validity) == 0 {
+		return true
+	}
+	checkBounds(validity, i>>3, 1)
+	return validity[i>>3]&(1<<uint(i&7)) != 0
+}
+
+func intAt(buf []byte, typ arrowType, i int) int64 {
+	width := typ.bitWidth / 8
+	checkBounds(buf, i*width, width)
+	switch {
+	case width == 1 && typ.signed:
+		return int64(int8(buf[i]))
+	case width == 1:
+		return int64(buf[i])
+	case width == 2 && typ.signed:
+		return int64(int16(le.Uint16(buf[2*i:])))
+	case width == 2:
+		return int64(le.Uint16(buf[2*i:]))
+	case width == 4 && typ.signed:
+		return int64(int32(le.Uint32(buf[4*i:])))
+	case width == 4:
+		return int64(le.Uint32(buf[4*i:]))
+	case width == 8:
+		return int64(le.Uint64(buf[8*i:]))
+	}
+	panic(errInvalid(fmt.Sprintf("unsupported int bit width: %d", typ.bitWidth)))
+}
+
+func unitNanos(unit int16) int64 {
+	switch unit {
+	case unitSecond:
+		return int64(time.Second)
+	case unitMillisecond:
+		return int64(time.Millisecond)
+	case unitMicrosecond:
+		return int64(time.Microsecond)
+	case unitNanosecond:
+		return 1
+	}
+	panic(errInvalid(fmt.Sprintf("unknown time unit: %d", unit)))
+}
+
+// decodeStrings appends the utf8 strings of the next array in b to col.
+func decodeStrings(b *batch, typ arrowType, col *columnData) {
+	node := b.nextNode()
+	validity, offsets, data := b.nextBuffer(), b.nextBuffer(), b.nextBuffer()
+	offsetType := arrowType{id: typeInt, bitWidth: 32, signed: true}
+	if typ.id == typeLargeUtf8 {
+		offsetType.bitWidth = 64
+	}
+
+	checkValidity(validity, node)
+
+	// The data buffer may be padded, only the bytes referenced by the offsets are kept
+	used := 0
+	if node.length > 0 {
+		checkSize(offsets, (node.length+1)*offsetType.bitWidth/8)
+		used = int(intAt(offsets, offsetType, node.length))
+		checkBounds(data, 0, used)
+	}
+
+	base := len(col.blob.Data)
+	col.blob.Data = append(col.blob.Data, data[:used]...)
+	for i := 0; i < node.length; i++ {
+		start, end := int(intAt(offsets, offsetType, i)), int(intAt(offsets, offsetType, i+1))
+		checkBounds(data, start, end-start)
+		isNull := !isValid(validity, node.nullCount, i)
+		col.blob.Pointers = append(col.blob.Pointers, qfstrings.NewPointer(base+start, end-start, isNull))
+	}
+}
+
+// decode appends the values of the next array in b to col.
+func decode(b *batch, f field, dictionaries map[int64][]*string, col *columnData) error {
+	if f.dictionary {
+		dict, ok := dictionaries[f.dictID]
+		if !ok {
+			return errors.New("ReadArrow", "missing dictionary %d for column %s", f.dictID, f.name)
+		}
+
+		node := b.nextNode()
+		validity, indices := b.nextBuffer(), b.nextBuffer()
+		checkValidity(validity, node)
+		checkSize(indices, node.length*f.indexType.bitWidth/8)
+		for i := 0; i < node.length; i++ {
+			if !isValid(validity, node.nullCount, i) {
+				col.strs = append(col.strs, nil)
+				continue
+			}
+
+			ix := intAt(indices, f.indexType, i)
+			if ix < 0 || ix >= int64(len(dict)) {
+				return errors.New("ReadArrow", "dictionary index out of range in column %s: %d", f.name, ix)
+			}
+			col.strs = append(col.strs, dict[ix])
+		}
+		return nil
+	}
+
+	switch f.typ.id {
+	case typeUtf8, typeLargeUtf8:
+		decodeStrings(b, f.typ, col)
+		return nil
+	}
+
+	node := b.nextNode()
+	validity, data := b.nextBuffer(), b.nextBuffer()
+	checkValidity(validity, node)
+	switch f.typ.id {
+	case typeInt:
+		checkSize(data, node.length*f.typ.bitWidth/8)
+	case typeFloatingPoint:
+		if f.typ.precision == precisionDouble {
+			checkSize(data, 8*node.length)
+		} else {
+			checkSize(data, 4*node.length)
+		}
+	case typeBool:
+		checkSize(data, (node.length+7)/8)
+	case typeTimestamp:
+		checkSize(data, 8*node.length)
+	}
+
+	offset := len(col.ints) + len(col.int64s) + len(col.floats) + len(col.bools)
+	for i := 0; i < node.length; i++ {
+		valid := isValid(validity, node.nullCount, i)
+		if !valid {
+			col.nulls = append(col.nulls, uint32(offset+i))
+		}
+
+		switch f.typ.id {
+		case typeInt:
+			col.ints = append(col.ints, int(intAt(data, f.typ, i)))
+		case typeFloatingPoint:
+			x := math.NaN()
+			if f.typ.precision == precisionDouble {
+				checkBounds(data, 8*i, 8)
+				if valid {
+					x = math.Float64frombits(le.Uint64(data[8*i:]))
+				}
+			} else {
+				checkBounds(data, 4*i, 4)
+				if valid {
+					x = float64(math.Float32frombits(le.Uint32(data[4*i:])))
+				}
+			}
+			col.floats = append(col.floats, x)
+		case typeBool:
+			checkBounds(data, i>>3, 1)
+			col.bools = append(col.bools, data[i>>3]&(1<<uint(i&7)) != 0)
+		case typeTimestamp:
+			col.int64s = append(col.int64s, intAt(data, arrowType{bitWidth: 64}, i)*unitNanos(f.typ.unit))
+		}
+	}
+
+	return nil
+}
+
+func checkSupported(f field) error {
+	if f.dictionary {
+		if f.typ.id != typeUtf8 && f.typ.id != typeLargeUtf8 {
+			return errors.New("ReadArrow", "unsupported dictionary value type in column %s: %d", f.name, f.typ.id)
+		}
+
+		if f.indexType.bitWidth != 8 && f.indexType.bitWidth != 16 && f.indexType.bitWidth != 32 && f.indexType.bitWidth != 64 {
+			return errors.New("ReadArrow", "unsupported dictionary index width in column %s: %d", f.name, f.indexType.bitWidth)
+		}
+		return nil
+	}
+
+	switch f.typ.id {
+	case typeInt:
+		if f.typ.bitWidth != 8 && f.typ.bitWidth != 16 && f.typ.bitWidth != 32 && f.typ.bitWidth != 64 {
+			return errors.New("ReadArrow", "unsupported int width in column %s: %d", f.name, f.typ.bitWidth)
+		}
+	case typeFloatingPoint:
+		if f.typ.precision != precisionSingle && f.typ.precision != precisionDouble {
+			return errors.New("ReadArrow", "unsupported float precision in column %s", f.name)
+		}
+	case typeUtf8, typeLargeUtf8, typeBool:
+	case typeTimestamp:
+		if f.typ.unit < unitSecond || f.typ.unit > unitNanosecond {
+			return errors.New("ReadArrow", "unsupported time unit in column %s: %d", f.name, f.typ.unit)
+		}
+	default:
+		return errors.New("ReadArrow", "unsupported type in column %s: %d", f.name, f.typ.id)
+	}
+	return nil
+}
+
+func location(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err == nil {
+		return loc, nil
+	}
+
+	// Fixed offsets such as "+01:00"
+	t, tErr := time.Parse("-07:00", timezone)
+	if tErr != nil {
+		return nil, errors.Propagate("ReadArrow", err)
+	}
+	_, offset := t.Zone()
+	return time.FixedZone(timezone, offset), nil
+}
+
+func nullBitmap(size int, nulls []uint32) bitmap.Bitmap {
+	if len(nulls) == 0 {
+		return nil
+	}
+
+	valid := bitmap.New(size, true)
+	for _, n := range nulls {
+		valid.SetNull(n)
+	}
+	return valid
+}
+
+func toColumnData(f field, col *columnData, dictionaries map[int64][]*string) (interface{}, error) {
+	if f.dictionary {
+		values := make([]string, 0)
+		for _, v := range dictionaries[f.dictID] {
+			if v != nil {
+				values = append(values, *v)
+			}
+		}
+
+		c, err := ecolumn.New(col.strs, values)
+		if err != nil {
+			return nil, errors.Propagate("ReadArrow", err)
+		}
+		return c, nil
+	}
+
+	switch f.typ.id {
+	case typeInt:
+		return icolumn.NewNullable(col.ints, nullBitmap(len(col.ints), col.nulls)), nil
+	case typeFloatingPoint:
+		if col.floats == nil {
+			col.floats = []float64{}
+		}
+		return col.floats, nil
+	case typeBool:
+		return bcolumn.NewNullable(col.bools, nullBitmap(len(col.bools), col.nulls)), nil
+	case typeUtf8, typeLargeUtf8:
+		if col.blob.Pointers == nil {
+			col.blob.Pointers = []qfstrings.Pointer{}
+		}
+		return col.blob, nil
+	case typeTimestamp:
+		loc, err := location(f.typ.timezone)
+		if err != nil {
+			return nil, err
+		}
+		return tcolumn.NewNanos(col.int64s, nullBitmap(len(col.int64s), col.nulls), loc), nil
+	}
+
+	return nil, errors.New("ReadArrow", "unsupported type in column %s: %d", f.name, f.typ.id)
+}
+
+// readMessage reads the next message from r, ok is false at the end of the stream.
+func readMessage(r io.Reader) (msg fbTable, body []byte, ok bool, err error) {
+	var prefix [4]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		if err == io.EOF {
+			return fbTable{}, nil, false, nil
+		}
+		return fbTable{}, nil, false, err
+	}
+
+	size := le.Uint32(prefix[:])
+	if size == continuationMarker {
+		if _, err := io.ReadFull(r, prefix[:]); err != nil {
+			return fbTable{}, nil, false, err
+		}
+		size = le.Uint32(prefix[:])
+	}
+
+	if size == 0 {
+		return fbTable{}, nil, false, nil
+	}
+
+	meta, err := readBytes(r, int64(size))
+	if err != nil {
+		return fbTable{}, nil, false, err
+	}
+
+	msg = fbRoot(meta)
+	bodyLength := msg.int64(messageBodyLength, 0)
+	if bodyLength < 0 {
+		panic(errInvalid("negative body length"))
+	}
+
+	body, err = readBytes(r, bodyLength)
+	if err != nil {
+		return fbTable{}, nil, false, err
+	}
+
+	return msg, body, true, nil
+}
+
+// readBytes reads exactly n bytes from r. The lengths are read from the stream and can
+// not be trusted, memory is therefore allocated as data is read rather than up front.
+func readBytes(r io.Reader, n int64) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if _, err := io.CopyN(buf, r, n); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Read reads an Arrow IPC stream from r. It returns the data of the columns, as
+// accepted by qframe.New, and the column names in the order of the schema.
+func Read(r io.Reader) (data map[string]interface{}, names []string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg, ok := r.(errInvalid)
+			if !ok {
+				panic(r)
+			}
+			data, names, err = nil, nil, errors.New("ReadArrow", "invalid arrow data: %s", string(msg))
+		}
+	}()
+
+	var fields []field
+	var columns []*columnData
+	rows := 0
+	dictionaries := make(map[int64][]*string)
+	for {
+		msg, body, ok, err := readMessage(r)
+		if err != nil {
+			return nil, nil, errors.Propagate("ReadArrow", err)
+		}
+
+		if !ok {
+			break
+		}
+
+		header, _ := msg.table(messageHeader)
+		switch msg.uint8(messageHeaderType, 0) {
+		case headerSchema:
+			if fields != nil {
+				return nil, nil, errors.New("ReadArrow", "multiple schemas in stream")
+			}
+
+			fields = readSchema(header)
+			columns = make([]*columnData, len(fields))
+			for i, f := range fields {
+				if err := checkSupported(f); err != nil {
+					return nil, nil, err
+				}
+				columns[i] = &columnData{}
+			}
+		case headerDictionaryBatch:
+			id := header.int64(dictBatchID, 0)
+			dataTable, _ := header.table(dictBatchData)
+			valueType := arrowType{id: typeUtf8}
+			for _, f := range fields {
+				if f.dictionary && f.dictID == id {
+					valueType = f.typ
+				}
+			}
+
+			col := &columnData{}
+			decodeStrings(readBatch(dataTable, body), valueType, col)
+			values := make([]*string, len(col.blob.Pointers))
+			for i, p := range col.blob.Pointers {
+				if !p.IsNull() {
+					s := string(col.blob.Data[p.Offset() : p.Offset()+p.Len()])
+					values[i] = &s
+				}
+			}
+
+			if header.bool(dictBatchIsDelta) {
+				values = append(dictionaries[id], values...)
+			} else if _, ok := dictionaries[id]; ok {
+				return nil, nil, errors.New("ReadArrow", "replacement of dictionary %d is not supported", id)
+			}
+			dictionaries[id] = values
+		case headerRecordBatch:
+			if fields == nil {
+				return nil, nil, errors.New("ReadArrow", "record batch before schema")
+			}
+
+			b := readBatch(header, body)
+			rows += b.length
+			for i, f := range fields {
+				if err := decode(b, f, dictionaries, columns[i]); err != nil {
+					return nil, nil, err
+				}
+			}
+		default:
+			return nil, nil, errors.New("ReadArrow", "unsupported message type: %d", msg.uint8(messageHeaderType, 0))
+		}
+	}
+
+	if fields == nil {
+		return nil, nil, errors.New("ReadArrow", "no schema in stream")
+	}
+
+	data = make(map[string]interface{}, len(fields))
+	names = make([]string, len(fields))
+	for i, f := range fields {
+		if n := columns[i].len(); n != rows {
+			return nil, nil, errors.New("ReadArrow", "expected %d values in column %s, found %d", rows, f.name, n)
+		}
+
+		colData, err := toColumnData(f, columns[i], dictionaries)
+		if err != nil {
+			return nil, nil, err
+		}
+		data[f.name] = colData
+		names[i] = f.name
+	}
+
+	return data, names, nil
+}
@@ -0,0 +1,273 @@
+package arrow
+
+import (
+	"io"
+	"math"
+	"time"
+
+	"github.com/tobgu/qframe/errors"
+	"github.com/tobgu/qframe/internal/bcolumn"
+	"github.com/tobgu/qframe/internal/column"
+	"github.com/tobgu/qframe/internal/ecolumn"
+	"github.com/tobgu/qframe/internal/fcolumn"
+	"github.com/tobgu/qframe/internal/icolumn"
+	"github.com/tobgu/qframe/internal/index"
+	"github.com/tobgu/qframe/internal/scolumn"
+	"github.com/tobgu/qframe/internal/tcolumn"
+)
+
+// array holds the buffers of a column encoded as an Arrow array.
+type array struct {
+	length    int
+	nullCount int
+	buffers   [][]byte
+}
+
+// validityBuilder builds a validity bitmap, the bitmap is only allocated if there are nulls.
+type validityBuilder struct {
+	size      int
+	nullCount int
+	bits      []byte
+}
+
+func (v *validityBuilder) setNull(i int) {
+	if v.bits == nil {
+		v.bits = make([]byte, (v.size+7)/8)
+		for j := range v.bits {
+			v.bits[j] = 0xFF
+		}
+	}
+	v.bits[i>>3] &^= 1 << uint(i&7)
+	v.nullCount++
+}
+
+func (v *validityBuilder) array(buffers ...[]byte) array {
+	bits := v.bits
+	if bits == nil {
+		bits = []byte{}
+	}
+	return array{length: v.size, nullCount: v.nullCount, buffers: append([][]byte{bits}, buffers...)}
+}
+
+func int64Buffer(size int, fn func(i int) int64) []byte {
+	buf := make([]byte, 8*size)
+	for i := 0; i < size; i++ {
+		le.PutUint64(buf[8*i:], uint64(fn(i)))
+	}
+	return buf
+}
+
+func stringArray(strs []*string) (array, byte) {
+	total := 0
+	for _, s := range strs {
+		if s != nil {
+			total += len(*s)
+		}
+	}
+
+	typeID, offsetWidth := byte(typeUtf8), 4
+	if total > math.MaxInt32 {
+		typeID, offsetWidth = typeLargeUtf8, 8
+	}
+
+	validity := validityBuilder{size: len(strs)}
+	offsets := make([]byte, offsetWidth*(len(strs)+1))
+	data := make([]byte, 0, total)
+	for i, s := range strs {
+		if s == nil {
+			validity.setNull(i)
+		} else {
+			data = append(data, *s...)
+		}
+
+		if offsetWidth == 4 {
+			le.PutUint32(offsets[4*(i+1):], uint32(len(data)))
+		} else {
+			le.PutUint64(offsets[8*(i+1):], uint64(len(data)))
+		}
+	}
+
+	return validity.array(offsets, data), typeID
+}
+
+func intType(bitWidth int32) *fbTableBuilder {
+	return newTable().int32(intBitWidth, bitWidth).bool(intIsSigned, true)
+}
+
+// encodeColumn returns the field describing col in the schema, the array holding its
+// data and for enum columns the dictionary.
+func encodeColumn(name string, id int, col column.Column, ix index.Int) (*fbTableBuilder, array, *array, error) {
+	f := newTable().string(fieldName, name).bool(fieldNullable, true).ref(fieldChildren, fbTables{})
+	validity := validityBuilder{size: len(ix)}
+	switch c := col.(type) {
+	case icolumn.Column:
+		view := c.View(ix)
+		data := int64Buffer(len(ix), func(i int) int64 {
+			if view.IsNull(i) {
+				validity.setNull(i)
+				return 0
+			}
+			return int64(view.ItemAt(i))
+		})
+		f.uint8(fieldTypeType, typeInt).ref(fieldType, intType(64))
+		return f, validity.array(data), nil, nil
+	case fcolumn.Column:
+		view := c.View(ix)
+		data := int64Buffer(len(ix), func(i int) int64 {
+			x := view.ItemAt(i)
+			if math.IsNaN(x) {
+				validity.setNull(i)
+			}
+			return int64(math.Float64bits(x))
+		})
+		f.uint8(fieldTypeType, typeFloatingPoint).ref(fieldType, newTable().int16(floatPrecision, precisionDouble))
+		return f, validity.array(data), nil, nil
+	case bcolumn.Column:
+		view := c.View(ix)
+		data := make([]byte, (len(ix)+7)/8)
+		for i := range ix {
+			if view.IsNull(i) {
+				validity.setNull(i)
+			} else if view.ItemAt(i) {
+				data[i>>3] |= 1 << uint(i&7)
+			}
+		}
+		f.uint8(fieldTypeType, typeBool).ref(fieldType, newTable())
+		return f, validity.array(data), nil, nil
+	case scolumn.Column:
+		a, typeID := stringArray(c.View(ix).Slice())
+		f.uint8(fieldTypeType, typeID).ref(fieldType, newTable())
+		return f, a, nil, nil
+	case ecolumn.Column:
+		values := c.Values()
+		codes := make(map[string]int64, len(values))
+		strs := make([]*string, len(values))
+		for i := range values {
+			codes[values[i]] = int64(i)
+			strs[i] = &values[i]
+		}
+
+		view := c.View(ix)
+		indices := make([]byte, 4*len(ix))
+		for i := range ix {
+			if s := view.ItemAt(i); s == nil {
+				validity.setNull(i)
+			} else {
+				le.PutUint32(indices[4*i:], uint32(codes[*s]))
+			}
+		}
+
+		dict, typeID := stringArray(strs)
+		encoding := newTable().int64(dictEncodingID, int64(id)).ref(dictEncodingIndexType, intType(32)).bool(dictEncodingIsOrdered, true)
+		f.uint8(fieldTypeType, typeID).ref(fieldType, newTable()).ref(fieldDictionary, encoding)
+		return f, validity.array(indices), &dict, nil
+	case tcolumn.Column:
+		view := c.View(ix)
+		data := int64Buffer(len(ix), func(i int) int64 {
+			if view.IsNull(i) {
+				validity.setNull(i)
+				return 0
+			}
+			return view.ItemAt(i).UnixNano()
+		})
+		timestamp := newTable().int16(timestampUnit, unitNanosecond).string(timestampTimezone, timezone(c.Location()))
+		f.uint8(fieldTypeType, typeTimestamp).ref(fieldType, timestamp)
+		return f, validity.array(data), nil, nil
+	}
+
+	return nil, array{}, nil, errors.New("WriteArrow", "unsupported column type: %s", col.DataType())
+}
+
+// timezone returns the name of loc, unnamed fixed zones are given as offsets such as "+02:00".
+func timezone(loc *time.Location) string {
+	if name := loc.String(); name != "" {
+		return name
+	}
+	return time.Unix(0, 0).In(loc).Format("-07:00")
+}
+
+// recordBatch returns the RecordBatch table describing arrays and the message body holding their buffers.
+func recordBatch(length int, arrays []array) (*fbTableBuilder, []byte) {
+	var body []byte
+	nodes := fbInt64Structs{fieldCount: 2}
+	buffers := fbInt64Structs{fieldCount: 2}
+	for _, a := range arrays {
+		nodes.values = append(nodes.values, int64(a.length), int64(a.nullCount))
+		for _, b := range a.buffers {
+			buffers.values = append(buffers.values, int64(len(body)), int64(len(b)))
+			body = append(body, b...)
+			for len(body)%bodyAlignment != 0 {
+				body = append(body, 0)
+			}
+		}
+	}
+
+	batch := newTable().int64(recordBatchLength, int64(length)).ref(recordBatchNodes, nodes).ref(recordBatchBuffers, buffers)
+	return batch, body
+}
+
+func writeMessage(w io.Writer, headerType uint8, header fbObject, body []byte) error {
+	msg := newTable().
+		int16(messageVersion, metadataV4).
+		uint8(messageHeaderType, headerType).
+		ref(messageHeader, header).
+		int64(messageBodyLength, int64(len(body)))
+	meta := fbFinish(msg)
+
+	prefix := make([]byte, 8)
+	le.PutUint32(prefix, continuationMarker)
+	le.PutUint32(prefix[4:], uint32(len(meta)))
+	for _, b := range [][]byte{prefix, meta, body} {
+		if _, err := w.Write(b); err != nil {
+			return errors.Propagate("WriteArrow", err)
+		}
+	}
+	return nil
+}
+
+// Write writes the elements referenced by ix of the columns to w as an Arrow IPC stream
+// consisting of a schema, one dictionary batch per enum column and a single record batch.
+func Write(w io.Writer, names []string, columns []column.Column, ix index.Int) error {
+	fields := make(fbTables, len(columns))
+	arrays := make([]array, len(columns))
+	dictionaries := make(map[int]array)
+	for i, col := range columns {
+		f, a, dict, err := encodeColumn(names[i], i, col, ix)
+		if err != nil {
+			return err
+		}
+
+		fields[i], arrays[i] = f, a
+		if dict != nil {
+			dictionaries[i] = *dict
+		}
+	}
+
+	if err := writeMessage(w, headerSchema, newTable().int16(schemaEndianness, littleEndian).ref(schemaFields, fields), nil); err != nil {
+		return err
+	}
+
+	for i := range columns {
+		dict, ok := dictionaries[i]
+		if !ok {
+			continue
+		}
+
+		data, body := recordBatch(dict.length, []array{dict})
+		header := newTable().int64(dictBatchID, int64(i)).ref(dictBatchData, data)
+		if err := writeMessage(w, headerDictionaryBatch, header, body); err != nil {
+			return err
+		}
+	}
+
+	data, body := recordBatch(len(ix), arrays)
+	if err := writeMessage(w, headerRecordBatch, data, body); err != nil {
+		return err
+	}
+
+	// End of stream
+	eos := make([]byte, 8)
+	le.PutUint32(eos, continuationMarker)
+	_, err := w.Write(eos)
+	return err
+}
@@ -0,0 +1,97 @@
+package io
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+
+	"github.com/tobgu/qframe/errors"
+)
+
+// Compression identifies the compression, if any, of written data.
+type Compression byte
+
+const (
+	NoCompression Compression = iota
+	GzipCompression
+)
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+
+	// A bzip2 stream starts with a block, or the end of stream marker if empty
+	bzip2BlockMagic = []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}
+	bzip2EndMagic   = []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90}
+)
+
+// bzip2HeaderLen is the length of the magic bytes, the block size and the block magic.
+const bzip2HeaderLen = 10
+
+// isBzip2 returns true if header starts with a bzip2 header. Text starting with "BZh"
+// is not mistaken for bzip2 since the block size and block magic are also verified.
+func isBzip2(header []byte) bool {
+	if len(header) < bzip2HeaderLen || !bytes.HasPrefix(header, bzip2Magic) {
+		return false
+	}
+
+	if header[3] < '1' || header[3] > '9' {
+		return false
+	}
+
+	blockMagic := header[4:bzip2HeaderLen]
+	return bytes.Equal(blockMagic, bzip2BlockMagic) || bytes.Equal(blockMagic, bzip2EndMagic)
+}
+
+// Decompress returns a reader that decompresses the data in r if it starts with the
+// magic bytes of gzip or bzip2, otherwise a reader returning the data as is.
+//
+// Only the bytes needed to detect the compression are buffered, larger reads of
+// uncompressed data go directly to r without any intermediate copying.
+func Decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReaderSize(r, 16)
+	magic, err := br.Peek(bzip2HeaderLen)
+	if err != nil && err != io.EOF {
+		return nil, errors.Propagate("Decompress", err)
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, errors.Propagate("Decompress gzip", err)
+		}
+		return gr, nil
+	case isBzip2(magic):
+		return bzip2.NewReader(br), nil
+	}
+
+	return br, nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// Compress returns a writer that compresses data written to it into w. The returned
+// writer must be closed to flush all data, closing it does not close w.
+func Compress(w io.Writer, compression Compression, level int) (io.WriteCloser, error) {
+	switch compression {
+	case NoCompression:
+		return nopWriteCloser{Writer: w}, nil
+	case GzipCompression:
+		gw, err := gzip.NewWriterLevel(w, level)
+		if err != nil {
+			return nil, errors.Propagate("Compress gzip", err)
+		}
+		return gw, nil
+	}
+
+	return nil, errors.New("Compress", "unknown compression: %d", compression)
+}
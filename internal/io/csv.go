@@ -3,11 +3,16 @@ package io
 import (
 	"io"
 	"math"
+	"time"
 
 	"github.com/tobgu/qframe/errors"
+	"github.com/tobgu/qframe/internal/bcolumn"
+	"github.com/tobgu/qframe/internal/bitmap"
 	"github.com/tobgu/qframe/internal/ecolumn"
 	"github.com/tobgu/qframe/internal/fastcsv"
+	"github.com/tobgu/qframe/internal/icolumn"
 	"github.com/tobgu/qframe/internal/strings"
+	"github.com/tobgu/qframe/internal/tcolumn"
 	"github.com/tobgu/qframe/types"
 )
 
@@ -23,6 +28,23 @@ type CSVConfig struct {
 	Delimiter        byte
 	Types            map[string]types.DataType
 	EnumVals         map[string][]string
+	Rename           map[string]string
+	AutoEnum         int
+	TimeLayout       string
+	TimeLocation     *time.Location
+	SkipRows         int
+	CommentChar      byte
+	Headers          []string
+	Columns          []string
+	MaxRows          int
+	NullValues       []string
+	ColumnNullValues map[string][]string
+	TrueValues       []string
+	FalseValues      []string
+
+	// enumDict holds the values of enum columns read in earlier chunks,
+	// new values are added after these.
+	enumDict map[string][]string
 }
 
 func isEmptyLine(fields [][]byte) bool {
@@ -30,85 +52,261 @@ func isEmptyLine(fields [][]byte) bool {
 }
 
 func ReadCSV(reader io.Reader, conf CSVConfig) (map[string]interface{}, []string, error) {
-	r := fastcsv.NewReader(reader, conf.Delimiter)
-	byteHeader, err := r.Read()
+	r, err := NewCSVReader(reader, conf)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dataMap, _, err := r.ReadChunk(0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dataMap, r.Headers(), nil
+}
+
+// CSVReader reads CSV data in chunks of rows. The types of the columns, and the
+// values of enum columns, are determined by the first chunk and kept for all
+// following chunks.
+type CSVReader struct {
+	r          fastcsv.Reader
+	conf       CSVConfig
+	headers    []string
+	columns    []int
+	fieldCount int
+	row        int
+	rowsRead   int
+	done       bool
+	schema     map[string]types.DataType
+	enumDict   map[string][]string
+}
+
+// NewCSVReader creates a new CSVReader, the header is read immediately.
+// Input compressed with gzip or bzip2 is decompressed transparently.
+func NewCSVReader(reader io.Reader, conf CSVConfig) (*CSVReader, error) {
+	reader, err := Decompress(reader)
 	if err != nil {
-		return nil, nil, errors.Propagate("ReadCSV read header", err)
+		return nil, errors.Propagate("ReadCSV", err)
+	}
+
+	r := fastcsv.NewReader(reader, conf.Delimiter)
+	r.SetComment(conf.CommentChar)
+	if err := r.SkipLines(conf.SkipRows); err != nil {
+		return nil, errors.Propagate("ReadCSV skip rows", err)
+	}
+
+	headers, row := conf.Headers, conf.SkipRows
+	if headers == nil {
+		byteHeader, err := r.Read()
+		if err != nil {
+			return nil, errors.Propagate("ReadCSV read header", err)
+		}
+
+		headers = make([]string, len(byteHeader))
+		for i := range headers {
+			headers[i] = string(byteHeader[i])
+		}
+		row++
+	}
+
+	columns := make([]int, len(headers))
+	for i := range columns {
+		columns[i] = i
+	}
+
+	if conf.Columns != nil {
+		positions := make(map[string]int, len(headers))
+		for i, h := range headers {
+			positions[h] = i
+		}
+
+		columns = make([]int, len(conf.Columns))
+		for i, c := range conf.Columns {
+			pos, ok := positions[c]
+			if !ok {
+				return nil, errors.New("ReadCSV", `unknown column in Columns: "%s"`, c)
+			}
+			columns[i] = pos
+		}
+	}
+
+	selected := make([]string, len(columns))
+	for i, pos := range columns {
+		selected[i] = headers[pos]
 	}
 
-	headers := make([]string, len(byteHeader))
-	colPointers := make([][]bytePointer, len(headers))
-	for i := range headers {
-		headers[i] = string(byteHeader[i])
+	return &CSVReader{r: r, conf: conf, headers: selected, columns: columns, fieldCount: len(headers), row: row}, nil
+}
+
+// Headers returns the names of the columns read.
+func (r *CSVReader) Headers() []string {
+	return r.headers
+}
+
+// ReadChunk reads up to maxRows rows, all remaining rows if maxRows <= 0.
+// The number of rows read is returned, 0 when there is no more data to read.
+func (r *CSVReader) ReadChunk(maxRows int) (map[string]interface{}, int, error) {
+	colPointers := make([][]bytePointer, len(r.headers))
+	for i := range colPointers {
 		colPointers[i] = []bytePointer{}
 	}
 
 	// All bytes in a column
-	colBytes := make([][]byte, len(headers))
+	colBytes := make([][]byte, len(r.headers))
 
-	row := 1
-	for r.Next() {
-		if r.Err() != nil {
-			return nil, nil, errors.Propagate("ReadCSV read body", r.Err())
+	rowCount := 0
+	for !r.done && (maxRows <= 0 || rowCount < maxRows) {
+		if r.conf.MaxRows > 0 && r.rowsRead >= r.conf.MaxRows {
+			r.done = true
+			break
 		}
 
-		row++
-		fields := r.Fields()
-		if len(fields) != len(headers) {
-			if isEmptyLine(fields) && conf.IgnoreEmptyLines {
+		if !r.r.Next() {
+			r.done = true
+			break
+		}
+
+		if r.r.Err() != nil {
+			return nil, 0, errors.Propagate("ReadCSV read body", r.r.Err())
+		}
+
+		r.row++
+		fields := r.r.Fields()
+		if len(fields) != r.fieldCount {
+			if isEmptyLine(fields) && r.conf.IgnoreEmptyLines {
 				continue
 			}
 
-			return nil, nil, errors.New("ReadCSV", "Wrong number of columns on line %d, expected %d, was %d",
-				row, len(headers), len(fields))
+			return nil, 0, errors.New("ReadCSV", "Wrong number of columns on line %d, expected %d, was %d",
+				r.row, r.fieldCount, len(fields))
 		}
 
-		if isEmptyLine(fields) && conf.IgnoreEmptyLines {
+		if isEmptyLine(fields) && r.conf.IgnoreEmptyLines {
 			continue
 		}
 
-		for i, col := range fields {
+		for i, pos := range r.columns {
 			start := len(colBytes[i])
-			colBytes[i] = append(colBytes[i], col...)
+			colBytes[i] = append(colBytes[i], fields[pos]...)
 			colPointers[i] = append(colPointers[i], bytePointer{start: uint32(start), end: uint32(len(colBytes[i]))})
 		}
+		rowCount++
+		r.rowsRead++
 	}
 
-	dataMap := make(map[string]interface{}, len(headers))
-	for i, header := range headers {
+	if r.r.Err() != nil {
+		return nil, 0, errors.Propagate("ReadCSV read body", r.r.Err())
+	}
+
+	if r.done && rowCount == 0 && r.schema != nil {
+		return nil, 0, nil
+	}
+
+	conf := r.chunkConfig()
+	dataMap := make(map[string]interface{}, len(r.headers))
+	for i, header := range r.headers {
 		data, err := columnToData(colBytes[i], colPointers[i], header, conf)
 		if err != nil {
-			return nil, nil, errors.Propagate("ReadCSV convert data", err)
+			return nil, 0, errors.Propagate("ReadCSV convert data", err)
 		}
 
 		dataMap[header] = data
 	}
 
 	if len(conf.EnumVals) > 0 {
-		return nil, nil, errors.New("Read csv", "Enum values specified for non enum column")
+		return nil, 0, errors.New("Read csv", "Enum values specified for non enum column")
 	}
 
-	return dataMap, headers, nil
+	r.updateSchema(dataMap)
+	return dataMap, rowCount, nil
+}
+
+// chunkConfig returns the config to use when converting the data of a chunk.
+func (r *CSVReader) chunkConfig() CSVConfig {
+	conf := r.conf
+	conf.EnumVals = make(map[string][]string, len(r.conf.EnumVals))
+	for k, v := range r.conf.EnumVals {
+		conf.EnumVals[k] = v
+	}
+
+	if r.schema != nil {
+		conf.Types = r.schema
+		conf.enumDict = r.enumDict
+	}
+
+	return conf
+}
+
+func (r *CSVReader) updateSchema(dataMap map[string]interface{}) {
+	if r.schema == nil {
+		r.schema = make(map[string]types.DataType, len(dataMap))
+		r.enumDict = make(map[string][]string)
+		for name, data := range dataMap {
+			r.schema[name] = dataType(data)
+		}
+	}
+
+	for name, data := range dataMap {
+		if c, ok := data.(ecolumn.Column); ok {
+			r.enumDict[name] = c.Values()
+		}
+	}
+}
+
+func dataType(data interface{}) types.DataType {
+	switch data.(type) {
+	case icolumn.Column:
+		return types.Int
+	case []float64:
+		return types.Float
+	case bcolumn.Column:
+		return types.Bool
+	case ecolumn.Column:
+		return types.Enum
+	case tcolumn.Column:
+		return types.Time
+	default:
+		return types.String
+	}
 }
 
-// Convert bytes to data columns, try, in turn int, float, bool and last string.
 func columnToData(bytes []byte, pointers []bytePointer, colName string, conf CSVConfig) (interface{}, error) {
 	var err error
 	dataType := conf.Types[colName]
+	nulls := newNullValues(colName, conf)
+
+	// Empty cells and null values in int and bool columns are null. Columns with
+	// only null cells are not considered int or bool columns unless explicitly typed.
+	hasValues := false
+	for _, p := range pointers {
+		if !nulls.isNull(bytes[p.start:p.end]) {
+			hasValues = true
+			break
+		}
+	}
+
+	if dataType == types.Int || (dataType == types.None && hasValues) {
+		intData := make([]int, len(pointers))
+		var valid bitmap.Bitmap
+		for i, p := range pointers {
+			if nulls.isNull(bytes[p.start:p.end]) {
+				if valid == nil {
+					valid = bitmap.New(len(pointers), true)
+				}
+				valid.SetNull(uint32(i))
+				continue
+			}
 
-	if dataType == types.Int || dataType == types.None {
-		intData := make([]int, 0, len(pointers))
-		for _, p := range pointers {
 			x, intErr := strings.ParseInt(bytes[p.start:p.end])
 			if intErr != nil {
 				err = intErr
 				break
 			}
-			intData = append(intData, int(x))
+			intData[i] = int(x)
 		}
 
 		if err == nil {
-			return intData, nil
+			return icolumn.NewNullable(intData, valid), nil
 		}
 
 		if dataType == types.Int {
@@ -120,7 +318,7 @@ func columnToData(bytes []byte, pointers []bytePointer, colName string, conf CSV
 		err = nil
 		floatData := make([]float64, 0, len(pointers))
 		for _, p := range pointers {
-			if p.start == p.end {
+			if nulls.isNull(bytes[p.start:p.end]) {
 				floatData = append(floatData, math.NaN())
 				continue
 			}
@@ -142,20 +340,29 @@ func columnToData(bytes []byte, pointers []bytePointer, colName string, conf CSV
 		}
 	}
 
-	if dataType == types.Bool || dataType == types.None {
+	if dataType == types.Bool || (dataType == types.None && hasValues) {
 		err = nil
-		boolData := make([]bool, 0, len(pointers))
-		for _, p := range pointers {
-			x, boolErr := strings.ParseBool(bytes[p.start:p.end])
+		boolData := make([]bool, len(pointers))
+		var valid bitmap.Bitmap
+		for i, p := range pointers {
+			if nulls.isNull(bytes[p.start:p.end]) {
+				if valid == nil {
+					valid = bitmap.New(len(pointers), true)
+				}
+				valid.SetNull(uint32(i))
+				continue
+			}
+
+			x, boolErr := nulls.parseBool(bytes[p.start:p.end])
 			if boolErr != nil {
 				err = boolErr
 				break
 			}
-			boolData = append(boolData, x)
+			boolData[i] = x
 		}
 
 		if err == nil {
-			return boolData, nil
+			return bcolumn.NewNullable(boolData, valid), nil
 		}
 
 		if dataType == types.Bool {
@@ -163,10 +370,16 @@ func columnToData(bytes []byte, pointers []bytePointer, colName string, conf CSV
 		}
 	}
 
+	if dataType == types.None && conf.AutoEnum > 0 {
+		if c, ok := autoEnum(bytes, pointers, nulls, conf.AutoEnum); ok {
+			return c, nil
+		}
+	}
+
 	if dataType == types.String || dataType == types.None {
 		stringPointers := make([]strings.Pointer, len(pointers))
 		for i, p := range pointers {
-			if p.start == p.end && conf.EmptyNull {
+			if nulls.isNullString(bytes[p.start:p.end]) {
 				stringPointers[i] = strings.NewPointer(int(p.start), 0, true)
 			} else {
 				stringPointers[i] = strings.NewPointer(int(p.start), int(p.end-p.start), false)
@@ -177,15 +390,18 @@ func columnToData(bytes []byte, pointers []bytePointer, colName string, conf CSV
 	}
 
 	if dataType == types.Enum {
-		values := conf.EnumVals[colName]
+		values, ok := conf.EnumVals[colName]
 		delete(conf.EnumVals, colName)
 		factory, err := ecolumn.NewFactory(values, len(pointers))
+		if dict, dictOk := conf.enumDict[colName]; dictOk && !ok {
+			factory, err = ecolumn.NewExtendableFactory(dict, len(pointers))
+		}
 		if err != nil {
 			return nil, err
 		}
 
 		for _, p := range pointers {
-			if p.start == p.end && conf.EmptyNull {
+			if nulls.isNullString(bytes[p.start:p.end]) {
 				factory.AppendNil()
 			} else {
 				err := factory.AppendByteString(bytes[p.start:p.end])
@@ -198,5 +414,120 @@ func columnToData(bytes []byte, pointers []bytePointer, colName string, conf CSV
 		return factory.ToColumn(), nil
 	}
 
+	if dataType == types.Time {
+		loc := conf.TimeLocation
+		if loc == nil {
+			loc = time.UTC
+		}
+
+		nanos := make([]int64, len(pointers))
+		var valid bitmap.Bitmap
+		for i, p := range pointers {
+			if nulls.isNull(bytes[p.start:p.end]) {
+				if valid == nil {
+					valid = bitmap.New(len(pointers), true)
+				}
+				valid.SetNull(uint32(i))
+				continue
+			}
+
+			t, err := time.ParseInLocation(conf.TimeLayout, string(bytes[p.start:p.end]), loc)
+			if err != nil {
+				return nil, errors.Propagate("Create time column", err)
+			}
+			nanos[i] = t.UnixNano()
+		}
+
+		return tcolumn.NewNanos(nanos, valid, loc), nil
+	}
+
 	return nil, errors.New("Create column", "unknown data type: %s", dataType)
 }
+
+// autoEnum creates an enum column from the data unless it contains more than
+// maxCardinality distinct values.
+func autoEnum(bytes []byte, pointers []bytePointer, nulls nullValues, maxCardinality int) (ecolumn.Column, bool) {
+	factory, _ := ecolumn.NewFactory(nil, len(pointers))
+	for _, p := range pointers {
+		if nulls.isNullString(bytes[p.start:p.end]) {
+			factory.AppendNil()
+			continue
+		}
+
+		if err := factory.AppendByteString(bytes[p.start:p.end]); err != nil || factory.Cardinality() > maxCardinality {
+			return ecolumn.Column{}, false
+		}
+	}
+
+	return factory.ToColumn(), true
+}
+
+// nullValues decides which cells of a column are null and how bools are spelled.
+type nullValues struct {
+	tokens      map[string]struct{}
+	emptyNull   bool
+	trueValues  map[string]struct{}
+	falseValues map[string]struct{}
+}
+
+func toSet(values []string) map[string]struct{} {
+	if len(values) == 0 {
+		return nil
+	}
+
+	result := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		result[v] = struct{}{}
+	}
+	return result
+}
+
+func newNullValues(colName string, conf CSVConfig) nullValues {
+	tokens, ok := conf.ColumnNullValues[colName]
+	if !ok {
+		tokens = conf.NullValues
+	}
+
+	return nullValues{
+		tokens:      toSet(tokens),
+		emptyNull:   conf.EmptyNull,
+		trueValues:  toSet(conf.TrueValues),
+		falseValues: toSet(conf.FalseValues),
+	}
+}
+
+func (n nullValues) isToken(b []byte) bool {
+	if n.tokens == nil {
+		return false
+	}
+
+	_, ok := n.tokens[string(b)]
+	return ok
+}
+
+// isNull reports if b is null in a column that is not a string or enum column.
+// Empty cells are always null in such columns.
+func (n nullValues) isNull(b []byte) bool {
+	return len(b) == 0 || n.isToken(b)
+}
+
+// isNullString reports if b is null in a string or enum column.
+func (n nullValues) isNullString(b []byte) bool {
+	return (len(b) == 0 && n.emptyNull) || n.isToken(b)
+}
+
+func (n nullValues) parseBool(b []byte) (bool, error) {
+	if n.trueValues != nil {
+		if _, ok := n.trueValues[string(b)]; ok {
+			return true, nil
+		}
+	}
+
+	if n.falseValues != nil {
+		if _, ok := n.falseValues[string(b)]; ok {
+			return false, nil
+		}
+	}
+
+	return strings.ParseBool(b)
+}
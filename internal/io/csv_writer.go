@@ -0,0 +1,237 @@
+package io
+
+import (
+	"io"
+	"math"
+	"strconv"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tobgu/qframe/errors"
+	"github.com/tobgu/qframe/internal/bcolumn"
+	"github.com/tobgu/qframe/internal/column"
+	"github.com/tobgu/qframe/internal/ecolumn"
+	"github.com/tobgu/qframe/internal/fcolumn"
+	"github.com/tobgu/qframe/internal/icolumn"
+	"github.com/tobgu/qframe/internal/index"
+	"github.com/tobgu/qframe/internal/scolumn"
+	"github.com/tobgu/qframe/internal/tcolumn"
+)
+
+// Size at which the buffered output is written to the underlying writer
+const csvFlushSize = 64 * 1024
+
+type CSVWriteConfig struct {
+	Delimiter        byte
+	NullRep          string
+	Header           bool
+	QuoteAll         bool
+	FloatFormat      byte
+	FloatPrec        int
+	Compression      Compression
+	CompressionLevel int
+}
+
+// cellAppender appends the element at position i in ix to buf.
+// The returned bool is true if the element is null, nothing is appended in that case.
+type cellAppender func(buf []byte, ix index.Int, i int) ([]byte, bool)
+
+func newCellAppender(col column.Column, conf CSVWriteConfig) (cellAppender, error) {
+	switch c := col.(type) {
+	case icolumn.Column:
+		return func(buf []byte, ix index.Int, i int) ([]byte, bool) {
+			if c.View(ix).IsNull(i) {
+				return buf, true
+			}
+			return c.AppendByteStringAt(buf, ix[i]), false
+		}, nil
+	case bcolumn.Column:
+		return func(buf []byte, ix index.Int, i int) ([]byte, bool) {
+			if c.View(ix).IsNull(i) {
+				return buf, true
+			}
+			return c.AppendByteStringAt(buf, ix[i]), false
+		}, nil
+	case fcolumn.Column:
+		return func(buf []byte, ix index.Int, i int) ([]byte, bool) {
+			f := c.View(ix).ItemAt(i)
+			if math.IsNaN(f) {
+				return buf, true
+			}
+			return strconv.AppendFloat(buf, f, conf.FloatFormat, conf.FloatPrec, 64), false
+		}, nil
+	case scolumn.Column:
+		return func(buf []byte, ix index.Int, i int) ([]byte, bool) {
+			s := c.View(ix).ItemAt(i)
+			if s == nil {
+				return buf, true
+			}
+			return append(buf, *s...), false
+		}, nil
+	case ecolumn.Column:
+		return func(buf []byte, ix index.Int, i int) ([]byte, bool) {
+			s := c.View(ix).ItemAt(i)
+			if s == nil {
+				return buf, true
+			}
+			return append(buf, *s...), false
+		}, nil
+	case tcolumn.Column:
+		return func(buf []byte, ix index.Int, i int) ([]byte, bool) {
+			view := c.View(ix)
+			if view.IsNull(i) {
+				return buf, true
+			}
+			return view.ItemAt(i).AppendFormat(buf, time.RFC3339Nano), false
+		}, nil
+	}
+
+	return nil, errors.New("WriteCSV", "unknown column type: %s", col.DataType())
+}
+
+func validateCSVWriteConfig(conf CSVWriteConfig) error {
+	switch conf.Delimiter {
+	case '"', '\r', '\n', 0:
+		return errors.New("WriteCSV", "invalid delimiter: %q", conf.Delimiter)
+	}
+
+	switch conf.FloatFormat {
+	case 'b', 'e', 'E', 'f', 'g', 'G', 'x', 'X':
+	default:
+		return errors.New("WriteCSV", "invalid float format: %q", conf.FloatFormat)
+	}
+
+	return nil
+}
+
+// WriteCSV writes the elements referenced by ix of the columns to writer in CSV format.
+func WriteCSV(writer io.Writer, headers []string, columns []column.Column, ix index.Int, conf CSVWriteConfig) error {
+	if err := validateCSVWriteConfig(conf); err != nil {
+		return err
+	}
+
+	appenders := make([]cellAppender, len(columns))
+	for i, col := range columns {
+		appender, err := newCellAppender(col, conf)
+		if err != nil {
+			return err
+		}
+		appenders[i] = appender
+	}
+
+	cw, err := Compress(writer, conf.Compression, conf.CompressionLevel)
+	if err != nil {
+		return err
+	}
+
+	if err := writeCSV(cw, headers, appenders, ix, conf); err != nil {
+		return err
+	}
+
+	return cw.Close()
+}
+
+func writeCSV(writer io.Writer, headers []string, appenders []cellAppender, ix index.Int, conf CSVWriteConfig) error {
+	w := csvWriter{writer: writer, conf: conf, buf: make([]byte, 0, csvFlushSize+1024)}
+	if conf.Header {
+		for i, h := range headers {
+			w.cell = append(w.cell[:0], h...)
+			w.appendField(i, w.cell)
+		}
+
+		if err := w.endLine(); err != nil {
+			return err
+		}
+	}
+
+	for i := range ix {
+		for j, appender := range appenders {
+			cell, isNull := appender(w.cell[:0], ix, i)
+			if isNull {
+				w.appendNull(j)
+			} else {
+				w.appendField(j, cell)
+			}
+			w.cell = cell
+		}
+
+		if err := w.endLine(); err != nil {
+			return err
+		}
+	}
+
+	return w.flush()
+}
+
+// csvWriter buffers CSV output, quoting fields as needed.
+type csvWriter struct {
+	writer io.Writer
+	conf   CSVWriteConfig
+	buf    []byte
+	cell   []byte
+}
+
+func (w *csvWriter) appendSeparator(col int) {
+	if col > 0 {
+		w.buf = append(w.buf, w.conf.Delimiter)
+	}
+}
+
+// appendNull appends the null representation, it is never quoted to keep it
+// distinguishable from strings when quoting all fields.
+func (w *csvWriter) appendNull(col int) {
+	w.appendSeparator(col)
+	w.buf = append(w.buf, w.conf.NullRep...)
+}
+
+func (w *csvWriter) appendField(col int, field []byte) {
+	w.appendSeparator(col)
+	if !w.conf.QuoteAll && !w.needsQuotes(field) {
+		w.buf = append(w.buf, field...)
+		return
+	}
+
+	w.buf = append(w.buf, '"')
+	for _, b := range field {
+		if b == '"' {
+			w.buf = append(w.buf, '"')
+		}
+		w.buf = append(w.buf, b)
+	}
+	w.buf = append(w.buf, '"')
+}
+
+// needsQuotes follows the rules of encoding/csv.
+func (w *csvWriter) needsQuotes(field []byte) bool {
+	if len(field) == 0 {
+		return false
+	}
+
+	if len(field) == 2 && field[0] == '\\' && field[1] == '.' {
+		return true
+	}
+
+	for _, b := range field {
+		if b == w.conf.Delimiter || b == '"' || b == '\r' || b == '\n' {
+			return true
+		}
+	}
+
+	r, _ := utf8.DecodeRune(field)
+	return unicode.IsSpace(r)
+}
+
+func (w *csvWriter) endLine() error {
+	w.buf = append(w.buf, '\n')
+	if len(w.buf) >= csvFlushSize {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *csvWriter) flush() error {
+	_, err := w.writer.Write(w.buf)
+	w.buf = w.buf[:0]
+	return err
+}
@@ -126,14 +126,25 @@ func jsonRecordsToData(records JSONRecords) (map[string]interface{}, error) {
 }
 
 // UnmarshalJSON transforms JSON containing data records or columns into a map of columns
-// that can be used to create a QFrame.
+// that can be used to create a QFrame. Input compressed with gzip or bzip2 is decompressed
+// transparently.
 func UnmarshalJSON(r io.Reader) (map[string]interface{}, error) {
+	r, err := Decompress(r)
+	if err != nil {
+		return nil, errors.Propagate("UnmarshalJSON", err)
+	}
+
 	var records JSONRecords
 	decoder := json.NewDecoder(r)
-	err := decoder.Decode(&records)
+	err = decoder.Decode(&records)
 	if err != nil {
 		return nil, errors.Propagate("UnmarshalJSON", err)
 	}
 
 	return jsonRecordsToData(records)
 }
+
+type JSONWriteConfig struct {
+	Compression      Compression
+	CompressionLevel int
+}
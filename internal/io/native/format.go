@@ -0,0 +1,114 @@
+/*
+Package native implements the qframe binary format.
+
+The format stores the columns of a frame using the same memory layout as the
+columns themselves so that they can be written and read without conversion.
+All integers in the format are stored using the byte order of the host that
+wrote the file. Files can only be read on hosts with the same byte order and
+int size.
+
+A file starts with a header:
+
+	magic       4 bytes, "QFRB"
+	version     1 byte
+	byte order  1 byte, 1 = little endian, 2 = big endian
+	int size    1 byte, size of a Go int in bytes
+	reserved    1 byte
+	rows        uint64
+	columns     uint64
+
+followed by the columns. Each column consists of its name, a type tag and a
+number of buffers depending on the type:
+
+	int, bool          data, validity bitmap
+	float              data
+	string             pointers, data blob
+	enum               code width, codes, value offsets, value blob
+	time               data, validity bitmap, location name, location offset
+
+Each buffer is stored as an uint64 byte length followed by the data, padded with
+zeros to a multiple of eight bytes. All buffers are hence eight byte aligned
+relative to the start of the file. An empty validity bitmap means that the
+column contains no nulls.
+*/
+package native
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+var magic = [4]byte{'Q', 'F', 'R', 'B'}
+
+const version = 1
+
+const headerSize = 24
+
+// minColumnSize is the size of a column holding only an empty name and a type tag.
+const minColumnSize = 16
+
+const (
+	littleEndian = 1
+	bigEndian    = 2
+)
+
+// Column type tags
+const (
+	tagInt    = 1
+	tagFloat  = 2
+	tagBool   = 3
+	tagString = 4
+	tagEnum   = 5
+	tagTime   = 6
+)
+
+const alignment = 8
+
+var hostByteOrder = func() byte {
+	x := uint16(1)
+	if *(*byte)(unsafe.Pointer(&x)) == 1 {
+		return littleEndian
+	}
+	return bigEndian
+}()
+
+const intSize = byte(unsafe.Sizeof(int(0)))
+
+// rawBytes returns the memory backing slice as a byte slice without copying.
+func rawBytes(slice interface{}) []byte {
+	v := reflect.ValueOf(slice)
+	size := v.Len() * int(v.Type().Elem().Size())
+	if size == 0 {
+		return nil
+	}
+
+	var b []byte
+	h := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	h.Data = v.Pointer()
+	h.Len = size
+	h.Cap = size
+	return b
+}
+
+// castSlice sets the slice pointed to by slicePtr to reference the memory of b.
+// b must be aligned for the element type of the slice.
+func castSlice(b []byte, slicePtr interface{}) {
+	if len(b) == 0 {
+		return
+	}
+
+	v := reflect.ValueOf(slicePtr).Elem()
+	size := int(v.Type().Elem().Size())
+	h := (*reflect.SliceHeader)(unsafe.Pointer(v.UnsafeAddr()))
+	h.Data = uintptr(unsafe.Pointer(&b[0]))
+	h.Len = len(b) / size
+	h.Cap = len(b) / size
+}
+
+func elemSize(slice interface{}) int {
+	return int(reflect.TypeOf(slice).Elem().Size())
+}
+
+func padding(size int) int {
+	return (alignment - size%alignment) % alignment
+}
@@ -0,0 +1,68 @@
+package native
+
+import (
+	"os"
+	"sync"
+
+	"github.com/tobgu/qframe/errors"
+)
+
+// Mapping is a read only memory mapping of a file in the qframe binary format.
+type Mapping struct {
+	data []byte
+	once sync.Once
+	err  error
+}
+
+// Close unmaps the file. Any data referencing the mapping must not be used after
+// the mapping has been closed. Calling Close more than once has no effect.
+func (m *Mapping) Close() error {
+	m.once.Do(func() {
+		if m.data != nil {
+			m.err = munmap(m.data)
+			m.data = nil
+		}
+	})
+	return m.err
+}
+
+// Open maps the file at path into memory and reads the frame in it. It returns
+// the data of the columns, as accepted by qframe.New, the column names in order
+// and the mapping. Int, float, bool, time and string data reference the mapping
+// directly rather than being copied, the mapping must hence be kept open for as
+// long as the data is in use.
+func Open(path string) (map[string]interface{}, []string, *Mapping, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, nil, errors.Propagate("Open", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, nil, nil, errors.Propagate("Open", err)
+	}
+
+	size := info.Size()
+	if size < headerSize {
+		return nil, nil, nil, errors.New("Open", "file too small to be a qframe binary file: %d bytes", size)
+	}
+
+	if size != int64(int(size)) {
+		return nil, nil, nil, errors.New("Open", "file too large to map: %d bytes", size)
+	}
+
+	data, err := mmap(f, int(size))
+	if err != nil {
+		return nil, nil, nil, errors.Propagate("Open", err)
+	}
+
+	m := &Mapping{data: data}
+	columns, names, err := read(&reader{op: "Open", mapped: data})
+	if err != nil {
+		m.Close()
+		return nil, nil, nil, err
+	}
+
+	return columns, names, m, nil
+}
@@ -0,0 +1,54 @@
+package native
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"unsafe"
+
+	"github.com/tobgu/qframe/internal/column"
+	"github.com/tobgu/qframe/internal/icolumn"
+	"github.com/tobgu/qframe/internal/index"
+	"github.com/tobgu/qframe/internal/scolumn"
+	qfstrings "github.com/tobgu/qframe/internal/strings"
+)
+
+func TestOpenReferencesMapping(t *testing.T) {
+	f, err := ioutil.TempFile("", "qframe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	columns := []column.Column{icolumn.New([]int{1, 2, 3}), scolumn.NewStrings([]string{"a", "bb", "ccc"})}
+	if _, err := Write(f, []string{"INT", "STRING"}, columns, index.NewAscending(3)); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	data, names, m, err := Open(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	if len(names) != 2 || names[0] != "INT" || names[1] != "STRING" {
+		t.Errorf("unexpected names: %v", names)
+	}
+
+	start := uintptr(unsafe.Pointer(&m.data[0]))
+	end := start + uintptr(len(m.data))
+	inMapping := func(p unsafe.Pointer) bool {
+		return uintptr(p) >= start && uintptr(p) < end
+	}
+
+	ints, _ := data["INT"].(icolumn.Column).Raw()
+	if len(ints) != 3 || ints[2] != 3 || !inMapping(unsafe.Pointer(&ints[0])) {
+		t.Errorf("expected ints to reference the mapping: %v", ints)
+	}
+
+	blob := data["STRING"].(qfstrings.StringBlob)
+	if string(blob.Data) != "abbccc" || !inMapping(unsafe.Pointer(&blob.Data[0])) || !inMapping(unsafe.Pointer(&blob.Pointers[0])) {
+		t.Errorf("expected strings to reference the mapping: %s", blob.Data)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package native
+
+import (
+	"io"
+	"os"
+)
+
+// mmap reads size bytes of f into eight byte aligned memory on platforms
+// without support for memory mapping.
+func mmap(f *os.File, size int) ([]byte, error) {
+	b := rawBytes(make([]uint64, (size+alignment-1)/alignment))[:size]
+	if _, err := io.ReadFull(f, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func munmap(b []byte) error {
+	return nil
+}
@@ -0,0 +1,385 @@
+package native
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+
+	"github.com/tobgu/qframe/errors"
+	"github.com/tobgu/qframe/internal/bcolumn"
+	"github.com/tobgu/qframe/internal/bitmap"
+	"github.com/tobgu/qframe/internal/ecolumn"
+	"github.com/tobgu/qframe/internal/icolumn"
+	qfstrings "github.com/tobgu/qframe/internal/strings"
+	"github.com/tobgu/qframe/internal/tcolumn"
+)
+
+// reader reads the format either from a stream, into newly allocated memory, or
+// from a memory mapped file in which case the returned slices reference the mapping.
+// The size of a stream is zero if not known up front.
+type reader struct {
+	op     string
+	r      io.Reader
+	size   int
+	mapped []byte
+	pos    int
+	err    error
+}
+
+// Lengths read from a stream of unknown size cannot be trusted. Memory is therefore
+// allocated as data arrives, starting with chunks of this size, rather than up front.
+const chunkSize = 1 << 20
+
+func (r *reader) fail(format string, args ...interface{}) {
+	if r.err == nil {
+		r.err = errors.New(r.op, format, args...)
+	}
+}
+
+// next returns the following n bytes. Memory read from a stream is eight byte aligned.
+func (r *reader) next(n int) []byte {
+	if r.err != nil || n == 0 {
+		return nil
+	}
+
+	if r.r == nil {
+		if n > len(r.mapped)-r.pos {
+			r.err = errors.Propagate(r.op, io.ErrUnexpectedEOF)
+			return nil
+		}
+
+		b := r.mapped[r.pos : r.pos+n : r.pos+n]
+		r.pos += n
+		return b
+	}
+
+	if r.size > 0 && n > r.size-r.pos {
+		r.err = errors.Propagate(r.op, io.ErrUnexpectedEOF)
+		return nil
+	}
+
+	// The buffer is grown by doubling its size, allocating at most twice the data read
+	words := (n + alignment - 1) / alignment
+	buf := make([]uint64, 0, minInt(words, chunkSize/alignment))
+	for len(buf) < words {
+		start := len(buf)
+		end := minInt(words, start+maxInt(start, chunkSize/alignment))
+		if end > cap(buf) {
+			grown := make([]uint64, start, end)
+			copy(grown, buf)
+			buf = grown
+		}
+
+		buf = buf[:end]
+		b := rawBytes(buf[start:end])
+		if end == words {
+			b = b[:n-start*alignment]
+		}
+
+		if _, err := io.ReadFull(r.r, b); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			r.err = errors.Propagate(r.op, err)
+			return nil
+		}
+	}
+
+	r.pos += n
+	return rawBytes(buf)[:n]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func (r *reader) uint64() uint64 {
+	b := r.next(8)
+	if b == nil {
+		return 0
+	}
+
+	if hostByteOrder == littleEndian {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+// buffer returns the content of a buffer holding elements of size bytes.
+func (r *reader) buffer(size int) []byte {
+	length := r.uint64()
+	if r.err != nil {
+		return nil
+	}
+
+	if length%uint64(size) != 0 || length > uint64(int(^uint(0)>>1)) {
+		r.fail("invalid buffer length: %d", length)
+		return nil
+	}
+
+	b := r.next(int(length))
+	r.next(padding(int(length)))
+	return b
+}
+
+func (r *reader) bytes() []byte {
+	return r.buffer(1)
+}
+
+func (r *reader) ints() []int {
+	var data []int
+	castSlice(r.buffer(int(intSize)), &data)
+	return data
+}
+
+func (r *reader) int64s() []int64 {
+	var data []int64
+	castSlice(r.buffer(8), &data)
+	return data
+}
+
+func (r *reader) uint64s() []uint64 {
+	var data []uint64
+	castSlice(r.buffer(8), &data)
+	return data
+}
+
+func (r *reader) floats() []float64 {
+	var data []float64
+	castSlice(r.buffer(8), &data)
+	return data
+}
+
+func (r *reader) bools() []bool {
+	b := r.buffer(1)
+	for _, x := range b {
+		if x > 1 {
+			r.fail("invalid bool value")
+			break
+		}
+	}
+
+	var data []bool
+	castSlice(b, &data)
+	return data
+}
+
+func (r *reader) pointers() []qfstrings.Pointer {
+	var data []qfstrings.Pointer
+	castSlice(r.buffer(8), &data)
+	return data
+}
+
+func (r *reader) codes(width uint64) interface{} {
+	switch width {
+	case 1:
+		data := r.bytes()
+		if data == nil {
+			data = []uint8{}
+		}
+		return data
+	case 2:
+		data := []uint16{}
+		castSlice(r.buffer(2), &data)
+		return data
+	case 4:
+		data := []uint32{}
+		castSlice(r.buffer(4), &data)
+		return data
+	}
+
+	r.fail("invalid enum code width: %d", width)
+	return nil
+}
+
+func (r *reader) valid(rows int) bitmap.Bitmap {
+	valid := r.uint64s()
+	if len(valid) == 0 {
+		return nil
+	}
+
+	if len(valid) != (rows+63)/64 {
+		r.fail("invalid validity bitmap length: %d", len(valid))
+	}
+	return valid
+}
+
+func (r *reader) checkLen(name string, length, rows int) {
+	if length != rows && r.err == nil {
+		r.fail("invalid length of column %s: %d, expected %d", name, length, rows)
+	}
+}
+
+func (r *reader) header() (rows int, columns int) {
+	head := r.next(8)
+	if r.err != nil {
+		return 0, 0
+	}
+
+	switch {
+	case !bytes.Equal(head[:4], magic[:]):
+		r.fail("not a qframe binary file")
+	case head[4] != version:
+		r.fail("unsupported version: %d", head[4])
+	case head[5] != hostByteOrder:
+		r.fail("incompatible byte order")
+	case head[6] != intSize:
+		r.fail("incompatible int size: %d", head[6])
+	}
+
+	rowCount, columnCount := r.uint64(), r.uint64()
+	if rowCount > math.MaxUint32 {
+		r.fail("invalid number of rows: %d", rowCount)
+	}
+
+	// Each column holds at least the length of its name and its type tag
+	maxColumns := uint64(int(^uint(0)>>1)) / minColumnSize
+	if remaining := r.remaining(); remaining >= 0 {
+		maxColumns = uint64(remaining) / minColumnSize
+	}
+
+	if columnCount > maxColumns {
+		r.fail("invalid number of columns: %d", columnCount)
+	}
+	return int(rowCount), int(columnCount)
+}
+
+// remaining returns the number of bytes left to read, or -1 if not known.
+func (r *reader) remaining() int {
+	switch {
+	case r.r == nil:
+		return len(r.mapped) - r.pos
+	case r.size > 0:
+		return r.size - r.pos
+	}
+	return -1
+}
+
+func location(name string, offset int64) *time.Location {
+	if name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	return time.FixedZone(name, int(offset))
+}
+
+func (r *reader) column(rows int) (string, interface{}) {
+	name := string(r.bytes())
+	tag := r.uint64()
+	if r.err != nil {
+		return "", nil
+	}
+
+	switch tag {
+	case tagInt:
+		data, valid := r.ints(), r.valid(rows)
+		r.checkLen(name, len(data), rows)
+		return name, icolumn.NewNullable(data, valid)
+	case tagFloat:
+		data := r.floats()
+		r.checkLen(name, len(data), rows)
+		return name, data
+	case tagBool:
+		data, valid := r.bools(), r.valid(rows)
+		r.checkLen(name, len(data), rows)
+		return name, bcolumn.NewNullable(data, valid)
+	case tagString:
+		pointers, data := r.pointers(), r.bytes()
+		r.checkLen(name, len(pointers), rows)
+		for _, p := range pointers {
+			if !p.IsNull() && p.Offset()+p.Len() > len(data) {
+				r.fail("invalid string pointer in column %s", name)
+				break
+			}
+		}
+		return name, qfstrings.StringBlob{Pointers: pointers, Data: data}
+	case tagEnum:
+		codes := r.codes(r.uint64())
+		offsets, blob := r.uint64s(), r.bytes()
+		if r.err != nil {
+			return "", nil
+		}
+
+		if len(offsets) == 0 {
+			r.fail("invalid enum values in column %s", name)
+			return "", nil
+		}
+
+		values := make([]string, len(offsets)-1)
+		for i := range values {
+			if offsets[i] > offsets[i+1] || offsets[i+1] > uint64(len(blob)) {
+				r.fail("invalid enum values in column %s", name)
+				return "", nil
+			}
+			values[i] = string(blob[offsets[i]:offsets[i+1]])
+		}
+
+		col, err := ecolumn.NewCodes(codes, values)
+		if err != nil {
+			r.err = errors.Propagate(r.op, err)
+			return "", nil
+		}
+		r.checkLen(name, col.Len(), rows)
+		return name, col
+	case tagTime:
+		data, valid := r.int64s(), r.valid(rows)
+		locName := string(r.bytes())
+		offset := int64(r.uint64())
+		r.checkLen(name, len(data), rows)
+		return name, tcolumn.NewNanos(data, valid, location(locName, offset))
+	}
+
+	r.fail("unknown column type: %d", tag)
+	return "", nil
+}
+
+// Read reads a frame in the qframe binary format from r. It returns the data of
+// the columns, as accepted by qframe.New, and the column names in order.
+func Read(r io.Reader) (map[string]interface{}, []string, error) {
+	return read(&reader{op: "ReadFrom", r: r})
+}
+
+// ReadBytes reads a frame in the qframe binary format from data. Lengths in the data
+// are checked against the size of data before any memory is allocated.
+func ReadBytes(data []byte) (map[string]interface{}, []string, error) {
+	return read(&reader{op: "UnmarshalBinary", r: bytes.NewReader(data), size: len(data)})
+}
+
+func read(br *reader) (map[string]interface{}, []string, error) {
+	rows, columns := br.header()
+	if br.err != nil {
+		return nil, nil, br.err
+	}
+
+	data := make(map[string]interface{})
+	var names []string
+	for i := 0; i < columns && br.err == nil; i++ {
+		name, col := br.column(rows)
+		if br.err == nil {
+			if _, ok := data[name]; ok {
+				br.fail("duplicate column name: %s", name)
+			}
+			data[name] = col
+			names = append(names, name)
+		}
+	}
+
+	if br.err != nil {
+		return nil, nil, br.err
+	}
+
+	return data, names, nil
+}
@@ -0,0 +1,143 @@
+package native
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/tobgu/qframe/errors"
+	"github.com/tobgu/qframe/internal/bcolumn"
+	"github.com/tobgu/qframe/internal/column"
+	"github.com/tobgu/qframe/internal/ecolumn"
+	"github.com/tobgu/qframe/internal/fcolumn"
+	"github.com/tobgu/qframe/internal/icolumn"
+	"github.com/tobgu/qframe/internal/index"
+	"github.com/tobgu/qframe/internal/scolumn"
+	"github.com/tobgu/qframe/internal/tcolumn"
+)
+
+type writer struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (w *writer) write(b []byte) {
+	if w.err != nil {
+		return
+	}
+
+	n, err := w.w.Write(b)
+	w.n += int64(n)
+	w.err = err
+}
+
+func (w *writer) uint64(x uint64) {
+	var buf [8]byte
+	if hostByteOrder == littleEndian {
+		binary.LittleEndian.PutUint64(buf[:], x)
+	} else {
+		binary.BigEndian.PutUint64(buf[:], x)
+	}
+	w.write(buf[:])
+}
+
+func (w *writer) buffer(b []byte) {
+	var pad [alignment]byte
+	w.uint64(uint64(len(b)))
+	w.write(b)
+	w.write(pad[:padding(len(b))])
+}
+
+func (w *writer) column(name string, col column.Column) {
+	w.buffer([]byte(name))
+	switch c := col.(type) {
+	case icolumn.Column:
+		data, valid := c.Raw()
+		w.uint64(tagInt)
+		w.buffer(rawBytes(data))
+		w.buffer(rawBytes([]uint64(valid)))
+	case fcolumn.Column:
+		// Null floats are represented by NaN, there is no validity bitmap
+		data := c.Raw()
+		w.uint64(tagFloat)
+		w.buffer(rawBytes(data))
+	case bcolumn.Column:
+		data, valid := c.Raw()
+		w.uint64(tagBool)
+		w.buffer(rawBytes(data))
+		w.buffer(rawBytes([]uint64(valid)))
+	case scolumn.Column:
+		pointers, data := c.Raw()
+		w.uint64(tagString)
+		w.buffer(rawBytes(pointers))
+		w.buffer(data)
+	case ecolumn.Column:
+		codes := c.Codes()
+		values := c.Values()
+		offsets := make([]uint64, len(values)+1)
+		var blob []byte
+		for i, v := range values {
+			blob = append(blob, v...)
+			offsets[i+1] = uint64(len(blob))
+		}
+
+		w.uint64(tagEnum)
+		w.uint64(uint64(elemSize(codes)))
+		w.buffer(rawBytes(codes))
+		w.buffer(rawBytes(offsets))
+		w.buffer(blob)
+	case tcolumn.Column:
+		data, valid := c.Raw()
+		loc := c.Location()
+		_, offset := time.Unix(0, 0).In(loc).Zone()
+		w.uint64(tagTime)
+		w.buffer(rawBytes(data))
+		w.buffer(rawBytes([]uint64(valid)))
+		w.buffer([]byte(loc.String()))
+		w.uint64(uint64(int64(offset)))
+	default:
+		if w.err == nil {
+			w.err = errors.New("WriteTo", "unsupported column type: %s", col.DataType())
+		}
+	}
+}
+
+func isIdentity(ix index.Int, size int) bool {
+	if len(ix) != size {
+		return false
+	}
+
+	for i, x := range ix {
+		if uint32(i) != x {
+			return false
+		}
+	}
+	return true
+}
+
+// Write writes the elements referenced by ix of the columns to w in the qframe
+// binary format and returns the number of bytes written. Columns are written
+// as they are if ix references all their elements in order, otherwise the
+// referenced elements are copied into new columns first.
+func Write(w io.Writer, names []string, columns []column.Column, ix index.Int) (int64, error) {
+	bw := &writer{w: bufio.NewWriter(w)}
+	bw.write(magic[:])
+	bw.write([]byte{version, hostByteOrder, intSize, 0})
+	bw.uint64(uint64(len(ix)))
+	bw.uint64(uint64(len(columns)))
+	identity := len(columns) == 0 || isIdentity(ix, columns[0].Len())
+	for i, col := range columns {
+		if !identity {
+			col = col.Subset(ix)
+		}
+		bw.column(names[i], col)
+	}
+
+	if bw.err == nil {
+		bw.err = bw.w.Flush()
+	}
+
+	return bw.n, bw.err
+}
@@ -0,0 +1,430 @@
+package io
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/tobgu/qframe/errors"
+	"github.com/tobgu/qframe/internal/bcolumn"
+	"github.com/tobgu/qframe/internal/bitmap"
+	"github.com/tobgu/qframe/internal/ecolumn"
+	"github.com/tobgu/qframe/internal/icolumn"
+	qfstrings "github.com/tobgu/qframe/internal/strings"
+	"github.com/tobgu/qframe/internal/tcolumn"
+	"github.com/tobgu/qframe/types"
+)
+
+type NDJSONConfig struct {
+	Types        map[string]types.DataType
+	EnumVals     map[string][]string
+	AutoEnum     int
+	TimeLayout   string
+	TimeLocation *time.Location
+}
+
+// ndjsonColumn collects the values of a column while decoding. Values are stored
+// according to the type of the column which is either given as a hint or inferred
+// from the first non null value. The type is None until then.
+type ndjsonColumn struct {
+	name     string
+	dataType types.DataType
+	hinted   bool
+	ints     []int
+	floats   []float64
+	bools    []bool
+	nanos    []int64
+	blob     qfstrings.StringBlob
+	nulls    []uint32
+	size     int
+}
+
+func (c *ndjsonColumn) appendNull() {
+	c.nulls = append(c.nulls, uint32(c.size))
+	c.size++
+	switch c.dataType {
+	case types.Int:
+		c.ints = append(c.ints, 0)
+	case types.Float:
+		c.floats = append(c.floats, math.NaN())
+	case types.Bool:
+		c.bools = append(c.bools, false)
+	case types.Time:
+		c.nanos = append(c.nanos, 0)
+	case types.String, types.Enum:
+		c.blob.Pointers = append(c.blob.Pointers, qfstrings.NewPointer(len(c.blob.Data), 0, true))
+	}
+}
+
+// setType sets the type of a column that has only contained nulls so far.
+func (c *ndjsonColumn) setType(dataType types.DataType) {
+	size, nulls := c.size, c.nulls
+	c.dataType, c.size, c.nulls = dataType, 0, nil
+	for i := 0; i < size; i++ {
+		c.appendNull()
+	}
+	c.nulls = nulls
+}
+
+// toFloat converts an int column into a float column.
+func (c *ndjsonColumn) toFloat() {
+	c.floats = make([]float64, len(c.ints))
+	for i, x := range c.ints {
+		c.floats[i] = float64(x)
+	}
+
+	for _, n := range c.nulls {
+		c.floats[n] = math.NaN()
+	}
+	c.ints, c.dataType = nil, types.Float
+}
+
+func (c *ndjsonColumn) appendString(s []byte) {
+	c.blob.Pointers = append(c.blob.Pointers, qfstrings.NewPointer(len(c.blob.Data), len(s), false))
+	c.blob.Data = append(c.blob.Data, s...)
+	c.size++
+}
+
+// unquote returns the content of the JSON string raw.
+func unquote(raw []byte) ([]byte, error) {
+	if bytes.IndexByte(raw, '\\') < 0 {
+		return raw[1 : len(raw)-1], nil
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+func (c *ndjsonColumn) wrongType(line int, raw []byte) error {
+	return errors.New("ReadNDJSON", "wrong type for column %s, line %d, expected %s: %s", c.name, line, c.dataType, raw)
+}
+
+// append adds the JSON value raw, given on line, to the column.
+func (c *ndjsonColumn) append(raw []byte, line int, conf *NDJSONConfig) error {
+	kind := raw[0]
+	if kind == 'n' {
+		c.appendNull()
+		return nil
+	}
+
+	isNumber := kind == '-' || (kind >= '0' && kind <= '9')
+	isBool := kind == 't' || kind == 'f'
+	if c.dataType == types.None {
+		switch {
+		case isNumber:
+			if _, err := strconv.Atoi(string(raw)); err == nil {
+				c.setType(types.Int)
+			} else {
+				c.setType(types.Float)
+			}
+		case isBool:
+			c.setType(types.Bool)
+		default:
+			c.setType(types.String)
+		}
+	}
+
+	switch c.dataType {
+	case types.Int:
+		if !isNumber {
+			return c.wrongType(line, raw)
+		}
+
+		x, err := strconv.Atoi(string(raw))
+		if err != nil {
+			if c.hinted {
+				return c.wrongType(line, raw)
+			}
+
+			// Ints are promoted to floats when a float is found in an inferred column
+			c.toFloat()
+			return c.append(raw, line, conf)
+		}
+		c.ints = append(c.ints, x)
+	case types.Float:
+		if !isNumber {
+			return c.wrongType(line, raw)
+		}
+
+		x, err := strconv.ParseFloat(string(raw), 64)
+		if err != nil {
+			return c.wrongType(line, raw)
+		}
+		c.floats = append(c.floats, x)
+	case types.Bool:
+		if !isBool {
+			return c.wrongType(line, raw)
+		}
+		c.bools = append(c.bools, kind == 't')
+	case types.Time:
+		if kind != '"' {
+			return c.wrongType(line, raw)
+		}
+
+		s, err := unquote(raw)
+		if err != nil {
+			return errors.Propagate("ReadNDJSON", err)
+		}
+
+		t, err := time.ParseInLocation(conf.TimeLayout, string(s), conf.TimeLocation)
+		if err != nil {
+			return errors.Propagate("ReadNDJSON", err)
+		}
+		c.nanos = append(c.nanos, t.UnixNano())
+	case types.String, types.Enum:
+		if kind != '"' {
+			if !c.hinted && kind != '{' && kind != '[' {
+				return c.wrongType(line, raw)
+			}
+
+			// Objects and arrays, and other values in columns hinted to hold
+			// strings, are kept as JSON text
+			c.appendString(raw)
+			return nil
+		}
+
+		s, err := unquote(raw)
+		if err != nil {
+			return errors.Propagate("ReadNDJSON", err)
+		}
+		c.appendString(s)
+		return nil
+	default:
+		return errors.New("ReadNDJSON", "unsupported type for column %s: %s", c.name, c.dataType)
+	}
+
+	c.size++
+	return nil
+}
+
+func (c *ndjsonColumn) valid() bitmap.Bitmap {
+	if len(c.nulls) == 0 {
+		return nil
+	}
+
+	valid := bitmap.New(c.size, true)
+	for _, n := range c.nulls {
+		valid.SetNull(n)
+	}
+	return valid
+}
+
+func (c *ndjsonColumn) stringPointers() []*string {
+	result := make([]*string, len(c.blob.Pointers))
+	for i, p := range c.blob.Pointers {
+		if !p.IsNull() {
+			s := string(c.blob.Data[p.Offset() : p.Offset()+p.Len()])
+			result[i] = &s
+		}
+	}
+	return result
+}
+
+// data returns the column data in a form accepted by qframe.New.
+func (c *ndjsonColumn) data(conf *NDJSONConfig) (interface{}, error) {
+	switch c.dataType {
+	case types.Int:
+		return icolumn.NewNullable(c.ints, c.valid()), nil
+	case types.Float:
+		return c.floats, nil
+	case types.Bool:
+		return bcolumn.NewNullable(c.bools, c.valid()), nil
+	case types.Time:
+		return tcolumn.NewNanos(c.nanos, c.valid(), conf.TimeLocation), nil
+	case types.Enum:
+		col, err := ecolumn.New(c.stringPointers(), conf.EnumVals[c.name])
+		if err != nil {
+			return nil, errors.Propagate("ReadNDJSON", err)
+		}
+		return col, nil
+	case types.None:
+		// Columns containing only nulls are read as string columns
+		c.setType(types.String)
+	}
+
+	if !c.hinted && conf.AutoEnum > 0 {
+		if col, ok := ecolumn.NewAuto(c.stringPointers(), conf.AutoEnum); ok {
+			return col, nil
+		}
+	}
+
+	if c.blob.Pointers == nil {
+		c.blob.Pointers = []qfstrings.Pointer{}
+	}
+	return c.blob, nil
+}
+
+// lineCounter keeps track of the positions of the newlines read from r to
+// map offsets in the input to line numbers.
+type lineCounter struct {
+	r        io.Reader
+	read     int64
+	newlines []int64
+	line     int
+}
+
+func (c *lineCounter) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	for i, b := range p[:n] {
+		if b == '\n' {
+			c.newlines = append(c.newlines, c.read+int64(i))
+		}
+	}
+	c.read += int64(n)
+	return n, err
+}
+
+// lineAt returns the line number of the byte preceding offset. Offsets must not decrease
+// between calls.
+func (c *lineCounter) lineAt(offset int64) int {
+	for len(c.newlines) > 0 && c.newlines[0] < offset-1 {
+		c.newlines = c.newlines[1:]
+		c.line++
+	}
+	return c.line + 1
+}
+
+func newNDJSONColumn(name string, conf *NDJSONConfig) (*ndjsonColumn, error) {
+	c := &ndjsonColumn{name: name}
+	if dataType, ok := conf.Types[name]; ok {
+		switch dataType {
+		case types.Int, types.Float, types.Bool, types.String, types.Enum, types.Time:
+		default:
+			return nil, errors.New("ReadNDJSON", "unsupported type for column %s: %s", name, dataType)
+		}
+		c.dataType, c.hinted = dataType, true
+	}
+	return c, nil
+}
+
+// ReadNDJSON reads newline delimited JSON, one JSON object per line, from r. It returns
+// the data of the columns, as accepted by qframe.New, and the column names in the order
+// they first appear in the input. Values are decoded one at a time into their columns.
+// Keys missing from an object are null, columns listed in conf.Types that are not
+// present at all contain only nulls. Input compressed with gzip or bzip2 is
+// decompressed transparently.
+func ReadNDJSON(r io.Reader, conf NDJSONConfig) (map[string]interface{}, []string, error) {
+	r, err := Decompress(r)
+	if err != nil {
+		return nil, nil, errors.Propagate("ReadNDJSON", err)
+	}
+
+	if conf.TimeLocation == nil {
+		conf.TimeLocation = time.UTC
+	}
+
+	if conf.TimeLayout == "" {
+		conf.TimeLayout = time.RFC3339Nano
+	}
+
+	var columns []*ndjsonColumn
+	byName := map[string]*ndjsonColumn{}
+	lines := &lineCounter{r: r}
+	decoder := json.NewDecoder(lines)
+	var raw json.RawMessage
+	rows, prevLine := 0, 0
+	for ; ; rows++ {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, nil, errors.Propagate("ReadNDJSON", err)
+		}
+
+		line := lines.lineAt(decoder.InputOffset())
+		if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+			return nil, nil, errors.New("ReadNDJSON", "expected JSON object on line %d", line)
+		}
+
+		if line == prevLine {
+			return nil, nil, errors.New("ReadNDJSON", "expected one JSON object per line, line %d", line)
+		}
+
+		for decoder.More() {
+			tok, err := decoder.Token()
+			if err != nil {
+				return nil, nil, errors.Propagate("ReadNDJSON", err)
+			}
+
+			name := tok.(string)
+			col, ok := byName[name]
+			if !ok {
+				if col, err = newNDJSONColumn(name, &conf); err != nil {
+					return nil, nil, err
+				}
+
+				for i := 0; i < rows; i++ {
+					col.appendNull()
+				}
+				byName[name] = col
+				columns = append(columns, col)
+			}
+
+			if col.size > rows {
+				return nil, nil, errors.New("ReadNDJSON", "duplicate key %s on line %d", name, lines.lineAt(decoder.InputOffset()))
+			}
+
+			if err := decoder.Decode(&raw); err != nil {
+				return nil, nil, errors.Propagate("ReadNDJSON", err)
+			}
+
+			if err := col.append(raw, lines.lineAt(decoder.InputOffset()), &conf); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if _, err := decoder.Token(); err != nil {
+			return nil, nil, errors.Propagate("ReadNDJSON", err)
+		}
+		prevLine = lines.lineAt(decoder.InputOffset())
+
+		for _, col := range columns {
+			if col.size == rows {
+				col.appendNull()
+			}
+		}
+	}
+
+	// Hinted columns not present in the input
+	var missing []string
+	for name := range conf.Types {
+		if _, ok := byName[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+
+	for _, name := range missing {
+		col, err := newNDJSONColumn(name, &conf)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for i := 0; i < rows; i++ {
+			col.appendNull()
+		}
+		columns = append(columns, col)
+	}
+
+	data := make(map[string]interface{}, len(columns))
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		colData, err := col.data(&conf)
+		if err != nil {
+			return nil, nil, err
+		}
+		data[col.name] = colData
+		names[i] = col.name
+	}
+
+	return data, names, nil
+}
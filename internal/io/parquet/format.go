@@ -0,0 +1,138 @@
+package parquet
+
+import (
+	"github.com/tobgu/qframe/errors"
+)
+
+// Constants and thrift field ids from parquet.thrift.
+// See https://github.com/apache/parquet-format/blob/master/src/main/thrift/parquet.thrift
+
+var magic = []byte("PAR1")
+
+// Type
+const (
+	typeBoolean           = 0
+	typeInt32             = 1
+	typeInt64             = 2
+	typeInt96             = 3
+	typeFloat             = 4
+	typeDouble            = 5
+	typeByteArray         = 6
+	typeFixedLenByteArray = 7
+)
+
+// ConvertedType
+const (
+	convertedUTF8            = 0
+	convertedEnum            = 4
+	convertedDate            = 6
+	convertedTimestampMillis = 9
+	convertedTimestampMicros = 10
+)
+
+// FieldRepetitionType
+const (
+	repetitionRequired = 0
+	repetitionOptional = 1
+	repetitionRepeated = 2
+)
+
+// Encoding
+const (
+	encodingPlain           = 0
+	encodingPlainDictionary = 2
+	encodingRLE             = 3
+	encodingRLEDictionary   = 8
+)
+
+// CompressionCodec
+const (
+	codecUncompressed = 0
+	codecSnappy       = 1
+	codecGzip         = 2
+)
+
+// PageType
+const (
+	pageData       = 0
+	pageDictionary = 2
+	pageDataV2     = 3
+)
+
+// LogicalType union members
+const (
+	logicalString    = 1
+	logicalEnum      = 4
+	logicalDate      = 6
+	logicalTimestamp = 8
+)
+
+// TimeUnit union members
+const (
+	unitMillis = 1
+	unitMicros = 2
+	unitNanos  = 3
+)
+
+// Thrift field ids
+const (
+	fileMetaVersion   = 1
+	fileMetaSchema    = 2
+	fileMetaNumRows   = 3
+	fileMetaRowGroups = 4
+	fileMetaCreatedBy = 6
+
+	schemaType          = 1
+	schemaRepetition    = 3
+	schemaName          = 4
+	schemaNumChildren   = 5
+	schemaConvertedType = 6
+	schemaLogicalType   = 10
+
+	timestampIsAdjustedToUTC = 1
+	timestampUnit            = 2
+
+	rowGroupColumns       = 1
+	rowGroupTotalByteSize = 2
+	rowGroupNumRows       = 3
+
+	columnChunkFileOffset = 2
+	columnChunkMetaData   = 3
+
+	columnMetaType                  = 1
+	columnMetaEncodings             = 2
+	columnMetaPathInSchema          = 3
+	columnMetaCodec                 = 4
+	columnMetaNumValues             = 5
+	columnMetaTotalUncompressedSize = 6
+	columnMetaTotalCompressedSize   = 7
+	columnMetaDataPageOffset        = 9
+	columnMetaDictionaryPageOffset  = 11
+
+	pageHeaderType                 = 1
+	pageHeaderUncompressedPageSize = 2
+	pageHeaderCompressedPageSize   = 3
+	pageHeaderDataPageHeader       = 5
+	pageHeaderDictionaryPageHeader = 7
+	pageHeaderDataPageHeaderV2     = 8
+
+	dataPageNumValues               = 1
+	dataPageEncoding                = 2
+	dataPageDefinitionLevelEncoding = 3
+	dataPageRepetitionLevelEncoding = 4
+
+	dictionaryPageNumValues = 1
+	dictionaryPageEncoding  = 2
+
+	dataPageV2NumValues                  = 1
+	dataPageV2NumNulls                   = 2
+	dataPageV2NumRows                    = 3
+	dataPageV2Encoding                   = 4
+	dataPageV2DefinitionLevelsByteLength = 5
+	dataPageV2RepetitionLevelsByteLength = 6
+	dataPageV2IsCompressed               = 7
+)
+
+func errParquet(format string, args ...interface{}) error {
+	return errors.New("parquet", format, args...)
+}
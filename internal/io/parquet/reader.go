@@ -0,0 +1,790 @@
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"math"
+	"time"
+
+	"github.com/tobgu/qframe/errors"
+	"github.com/tobgu/qframe/internal/bcolumn"
+	"github.com/tobgu/qframe/internal/bitmap"
+	"github.com/tobgu/qframe/internal/ecolumn"
+	"github.com/tobgu/qframe/internal/icolumn"
+	qfstrings "github.com/tobgu/qframe/internal/strings"
+	"github.com/tobgu/qframe/internal/tcolumn"
+	"github.com/tobgu/qframe/types"
+)
+
+type ReadConfig struct {
+	Columns   []string
+	RowGroups []int
+}
+
+// Number of days between the Julian day epoch and the Unix epoch, used by INT96 timestamps
+const julianUnixEpochDays = 2440588
+
+// leaf describes a top level column of the file.
+type leaf struct {
+	name      string
+	chunk     int
+	physical  int64
+	dataType  types.DataType
+	timeUnit  int64
+	optional  bool
+	supported bool
+}
+
+// dataType maps the schema element of a column to a qframe data type, None if not supported.
+func dataType(element tstruct) (types.DataType, int64) {
+	logical := element.strct(schemaLogicalType)
+	converted := int64(-1)
+	if element.has(schemaConvertedType) {
+		converted = element.int(schemaConvertedType)
+	}
+
+	switch element.int(schemaType) {
+	case typeBoolean:
+		return types.Bool, 0
+	case typeInt32:
+		if logical.has(logicalDate) || converted == convertedDate {
+			return types.Time, int64(24 * time.Hour)
+		}
+		return types.Int, 0
+	case typeInt64:
+		if ts := logical.strct(logicalTimestamp); ts != nil {
+			unit := ts.strct(timestampUnit)
+			switch {
+			case unit.has(unitMillis):
+				return types.Time, int64(time.Millisecond)
+			case unit.has(unitMicros):
+				return types.Time, int64(time.Microsecond)
+			case unit.has(unitNanos):
+				return types.Time, 1
+			}
+			return types.None, 0
+		}
+
+		switch converted {
+		case convertedTimestampMillis:
+			return types.Time, int64(time.Millisecond)
+		case convertedTimestampMicros:
+			return types.Time, int64(time.Microsecond)
+		}
+		return types.Int, 0
+	case typeInt96:
+		return types.Time, 1
+	case typeFloat, typeDouble:
+		return types.Float, 0
+	case typeByteArray:
+		if logical.has(logicalEnum) || converted == convertedEnum {
+			return types.Enum, 0
+		}
+		return types.String, 0
+	}
+
+	return types.None, 0
+}
+
+// readLeaves returns the top level columns of the schema. Nested and repeated
+// columns are returned as unsupported.
+func readLeaves(schema []tstruct) []leaf {
+	var result []leaf
+	chunk := 0
+	var walk func(pos int, top bool) int
+	walk = func(pos int, top bool) int {
+		if pos >= len(schema) {
+			invalid("invalid schema")
+		}
+
+		element := schema[pos]
+		children := int(element.int(schemaNumChildren))
+		repeated := element.int(schemaRepetition) == repetitionRepeated
+		if top {
+			l := leaf{name: element.string(schemaName), chunk: chunk, optional: element.int(schemaRepetition) == repetitionOptional}
+			l.dataType, l.timeUnit = dataType(element)
+			l.physical = element.int(schemaType)
+			l.supported = children == 0 && !repeated && l.dataType != types.None
+			result = append(result, l)
+		}
+
+		if children == 0 {
+			chunk++
+			return pos + 1
+		}
+
+		pos++
+		for i := 0; i < children; i++ {
+			pos = walk(pos, false)
+		}
+		return pos
+	}
+
+	if len(schema) == 0 {
+		invalid("empty schema")
+	}
+
+	pos := 1
+	for i := 0; i < int(schema[0].int(schemaNumChildren)); i++ {
+		pos = walk(pos, true)
+	}
+	return result
+}
+
+// values holds decoded values of one physical type.
+type values struct {
+	ints   []int64
+	floats []float64
+	bools  []bool
+	bytes  [][]byte
+}
+
+func (v values) len() int {
+	return len(v.ints) + len(v.floats) + len(v.bools) + len(v.bytes)
+}
+
+func (v values) gather(indices []uint32) (values, error) {
+	size := v.len()
+	result := values{}
+	for _, ix := range indices {
+		if int(ix) >= size {
+			return values{}, errParquet("dictionary index out of range: %d", ix)
+		}
+
+		switch {
+		case v.ints != nil:
+			result.ints = append(result.ints, v.ints[ix])
+		case v.floats != nil:
+			result.floats = append(result.floats, v.floats[ix])
+		case v.bools != nil:
+			result.bools = append(result.bools, v.bools[ix])
+		default:
+			result.bytes = append(result.bytes, v.bytes[ix])
+		}
+	}
+	return result, nil
+}
+
+func decodePlain(buf []byte, physical int64, count int) (values, error) {
+	result := values{}
+	need := func(n int) error {
+		if n > len(buf) || n < 0 {
+			return errParquet("truncated page values")
+		}
+		return nil
+	}
+
+	// Every value takes at least one bit
+	if count < 0 || count > 8*len(buf) {
+		return result, errParquet("invalid number of page values: %d", count)
+	}
+
+	switch physical {
+	case typeBoolean:
+		if err := need((count + 7) / 8); err != nil {
+			return result, err
+		}
+		result.bools = make([]bool, count)
+		for i := range result.bools {
+			result.bools[i] = buf[i>>3]&(1<<uint(i&7)) != 0
+		}
+	case typeInt32:
+		if err := need(4 * count); err != nil {
+			return result, err
+		}
+		result.ints = make([]int64, count)
+		for i := range result.ints {
+			result.ints[i] = int64(int32(binary.LittleEndian.Uint32(buf[4*i:])))
+		}
+	case typeInt64:
+		if err := need(8 * count); err != nil {
+			return result, err
+		}
+		result.ints = make([]int64, count)
+		for i := range result.ints {
+			result.ints[i] = int64(binary.LittleEndian.Uint64(buf[8*i:]))
+		}
+	case typeInt96:
+		if err := need(12 * count); err != nil {
+			return result, err
+		}
+		result.ints = make([]int64, count)
+		for i := range result.ints {
+			nanosOfDay := int64(binary.LittleEndian.Uint64(buf[12*i:]))
+			julianDay := int64(binary.LittleEndian.Uint32(buf[12*i+8:]))
+			result.ints[i] = (julianDay-julianUnixEpochDays)*int64(24*time.Hour) + nanosOfDay
+		}
+	case typeFloat:
+		if err := need(4 * count); err != nil {
+			return result, err
+		}
+		result.floats = make([]float64, count)
+		for i := range result.floats {
+			result.floats[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:])))
+		}
+	case typeDouble:
+		if err := need(8 * count); err != nil {
+			return result, err
+		}
+		result.floats = make([]float64, count)
+		for i := range result.floats {
+			result.floats[i] = math.Float64frombits(binary.LittleEndian.Uint64(buf[8*i:]))
+		}
+	case typeByteArray:
+		if err := need(4 * count); err != nil {
+			return result, err
+		}
+		result.bytes = make([][]byte, count)
+		pos := 0
+		for i := range result.bytes {
+			if err := need(pos + 4); err != nil {
+				return result, err
+			}
+			length := int(binary.LittleEndian.Uint32(buf[pos:]))
+			pos += 4
+			if err := need(pos + length); err != nil {
+				return result, err
+			}
+			result.bytes[i] = buf[pos : pos+length]
+			pos += length
+		}
+	default:
+		return result, errParquet("unsupported physical type: %d", physical)
+	}
+
+	return result, nil
+}
+
+func decompress(codec int64, buf []byte) ([]byte, error) {
+	switch codec {
+	case codecUncompressed:
+		return buf, nil
+	case codecSnappy:
+		return snappyDecode(buf)
+	case codecGzip:
+		r, err := gzip.NewReader(bytes.NewReader(buf))
+		if err != nil {
+			return nil, err
+		}
+		result, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	return nil, errParquet("unsupported compression codec: %d", codec)
+}
+
+// columnReader accumulates the values of a column across pages and row groups.
+type columnReader struct {
+	leaf       leaf
+	dictionary *values
+	ints       []int64
+	floats     []float64
+	bools      []bool
+	strs       []*string
+	enumVals   []string
+	enumSeen   map[string]bool
+	blob       qfstrings.StringBlob
+	nulls      []uint32
+	size       int
+}
+
+func (c *columnReader) append(defined []bool, v values) error {
+	if v.len() > len(defined) {
+		return errParquet("too many values in page of column %s", c.leaf.name)
+	}
+
+	j := 0
+	for _, d := range defined {
+		if !d {
+			c.nulls = append(c.nulls, uint32(c.size))
+		} else if j >= v.len() {
+			return errParquet("too few values in page of column %s", c.leaf.name)
+		}
+
+		switch c.leaf.dataType {
+		case types.Int, types.Time:
+			x := int64(0)
+			if d {
+				x = v.ints[j]
+			}
+			c.ints = append(c.ints, x)
+		case types.Float:
+			x := math.NaN()
+			if d {
+				x = v.floats[j]
+			}
+			c.floats = append(c.floats, x)
+		case types.Bool:
+			x := false
+			if d {
+				x = v.bools[j]
+			}
+			c.bools = append(c.bools, x)
+		case types.Enum:
+			var s *string
+			if d {
+				str := string(v.bytes[j])
+				s = &str
+			}
+			c.strs = append(c.strs, s)
+		case types.String:
+			offset, length := len(c.blob.Data), 0
+			if d {
+				c.blob.Data = append(c.blob.Data, v.bytes[j]...)
+				length = len(v.bytes[j])
+			}
+			c.blob.Pointers = append(c.blob.Pointers, qfstrings.NewPointer(offset, length, !d))
+		}
+
+		if d {
+			j++
+		}
+		c.size++
+	}
+
+	return nil
+}
+
+// addEnumVal adds s to the values of an enum column unless already present. Values are added
+// in dictionary page order to keep the order, and unused values, of the written enum.
+func (c *columnReader) addEnumVal(s string) {
+	if c.enumSeen == nil {
+		c.enumSeen = map[string]bool{}
+	}
+
+	if !c.enumSeen[s] {
+		c.enumSeen[s] = true
+		c.enumVals = append(c.enumVals, s)
+	}
+}
+
+// decodeValues decodes count non null values of a data page.
+func (c *columnReader) decodeValues(buf []byte, encoding int64, count int) (values, error) {
+	switch encoding {
+	case encodingPlain:
+		return decodePlain(buf, c.leaf.physical, count)
+	case encodingPlainDictionary, encodingRLEDictionary:
+		if c.dictionary == nil {
+			return values{}, errParquet("missing dictionary page in column %s", c.leaf.name)
+		}
+
+		if count == 0 {
+			return values{}, nil
+		}
+
+		if len(buf) == 0 {
+			return values{}, errParquet("truncated dictionary indices")
+		}
+
+		indices, err := rleDecode(buf[1:], int(buf[0]), count)
+		if err != nil {
+			return values{}, err
+		}
+		return c.dictionary.gather(indices)
+	case encodingRLE:
+		if c.leaf.physical != typeBoolean {
+			break
+		}
+
+		if len(buf) < 4 {
+			return values{}, errParquet("truncated boolean values")
+		}
+
+		bits, err := rleDecode(buf[4:], 1, count)
+		if err != nil {
+			return values{}, err
+		}
+
+		result := values{bools: make([]bool, count)}
+		for i, b := range bits {
+			result.bools[i] = b == 1
+		}
+		return result, nil
+	}
+
+	return values{}, errParquet("unsupported encoding %d in column %s", encoding, c.leaf.name)
+}
+
+func (c *columnReader) definitionLevels(buf []byte, count int) ([]bool, error) {
+	defined := make([]bool, count)
+	if !c.leaf.optional {
+		for i := range defined {
+			defined[i] = true
+		}
+		return defined, nil
+	}
+
+	levels, err := rleDecode(buf, 1, count)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, l := range levels {
+		defined[i] = l == 1
+	}
+	return defined, nil
+}
+
+func countDefined(defined []bool) int {
+	n := 0
+	for _, d := range defined {
+		if d {
+			n++
+		}
+	}
+	return n
+}
+
+// readChunk reads all pages of a column chunk.
+func (c *columnReader) readChunk(chunk []byte, codec int64, numValues int64) error {
+	read := int64(0)
+	pageCount := func(count int64) (int, error) {
+		if count < 0 || count > numValues-read {
+			return 0, errParquet("invalid number of values in page of column %s: %d", c.leaf.name, count)
+		}
+		return int(count), nil
+	}
+
+	pos := 0
+	for read < numValues && pos < len(chunk) {
+		header, n := decodeStruct(chunk[pos:])
+		pos += n
+
+		compressedSize := int(header.int(pageHeaderCompressedPageSize))
+		if compressedSize < 0 || pos+compressedSize > len(chunk) {
+			return errParquet("truncated page in column %s", c.leaf.name)
+		}
+
+		page := chunk[pos : pos+compressedSize]
+		pos += compressedSize
+
+		switch header.int(pageHeaderType) {
+		case pageDictionary:
+			dictHeader := header.strct(pageHeaderDictionaryPageHeader)
+			data, err := decompress(codec, page)
+			if err != nil {
+				return err
+			}
+
+			dict, err := decodePlain(data, c.leaf.physical, int(dictHeader.int(dictionaryPageNumValues)))
+			if err != nil {
+				return err
+			}
+			c.dictionary = &dict
+			if c.leaf.dataType == types.Enum {
+				for _, v := range dict.bytes {
+					c.addEnumVal(string(v))
+				}
+			}
+		case pageData:
+			dataHeader := header.strct(pageHeaderDataPageHeader)
+			count, err := pageCount(dataHeader.int(dataPageNumValues))
+			if err != nil {
+				return err
+			}
+
+			data, err := decompress(codec, page)
+			if err != nil {
+				return err
+			}
+
+			// Definition levels are prefixed by their length in v1 pages
+			var levels []byte
+			levelsEnd := 0
+			if c.leaf.optional {
+				if len(data) < 4 {
+					return errParquet("truncated definition levels in column %s", c.leaf.name)
+				}
+				levelsEnd = 4 + int(binary.LittleEndian.Uint32(data))
+				if levelsEnd > len(data) || levelsEnd < 4 {
+					return errParquet("truncated definition levels in column %s", c.leaf.name)
+				}
+				levels = data[4:levelsEnd]
+			}
+
+			defined, err := c.definitionLevels(levels, count)
+			if err != nil {
+				return err
+			}
+
+			v, err := c.decodeValues(data[levelsEnd:], dataHeader.int(dataPageEncoding), countDefined(defined))
+			if err != nil {
+				return err
+			}
+
+			if err := c.append(defined, v); err != nil {
+				return err
+			}
+			read += int64(count)
+		case pageDataV2:
+			dataHeader := header.strct(pageHeaderDataPageHeaderV2)
+			count, err := pageCount(dataHeader.int(dataPageV2NumValues))
+			if err != nil {
+				return err
+			}
+
+			repLength := int(dataHeader.int(dataPageV2RepetitionLevelsByteLength))
+			defLength := int(dataHeader.int(dataPageV2DefinitionLevelsByteLength))
+			if repLength < 0 || defLength < 0 || repLength+defLength > len(page) {
+				return errParquet("truncated levels in column %s", c.leaf.name)
+			}
+
+			defined, err := c.definitionLevels(page[repLength:repLength+defLength], count)
+			if err != nil {
+				return err
+			}
+
+			data := page[repLength+defLength:]
+			if !dataHeader.has(dataPageV2IsCompressed) || dataHeader.bool(dataPageV2IsCompressed) {
+				if data, err = decompress(codec, data); err != nil {
+					return err
+				}
+			}
+
+			v, err := c.decodeValues(data, dataHeader.int(dataPageV2Encoding), countDefined(defined))
+			if err != nil {
+				return err
+			}
+
+			if err := c.append(defined, v); err != nil {
+				return err
+			}
+			read += int64(count)
+		}
+	}
+
+	if read != numValues {
+		return errParquet("expected %d values in column %s, found %d", numValues, c.leaf.name, read)
+	}
+
+	return nil
+}
+
+func (c *columnReader) nullBitmap() bitmap.Bitmap {
+	if len(c.nulls) == 0 {
+		return nil
+	}
+
+	valid := bitmap.New(c.size, true)
+	for _, n := range c.nulls {
+		valid.SetNull(n)
+	}
+	return valid
+}
+
+func (c *columnReader) data() (interface{}, error) {
+	switch c.leaf.dataType {
+	case types.Int:
+		ints := make([]int, len(c.ints))
+		for i, x := range c.ints {
+			ints[i] = int(x)
+		}
+		return icolumn.NewNullable(ints, c.nullBitmap()), nil
+	case types.Time:
+		nanos := c.ints
+		if c.leaf.timeUnit > 1 {
+			for i := range nanos {
+				nanos[i] *= c.leaf.timeUnit
+			}
+		}
+		return tcolumn.NewNanos(nanos, c.nullBitmap(), time.UTC), nil
+	case types.Float:
+		if c.floats == nil {
+			c.floats = []float64{}
+		}
+		return c.floats, nil
+	case types.Bool:
+		return bcolumn.NewNullable(c.bools, c.nullBitmap()), nil
+	case types.Enum:
+		// Values only found in plain encoded pages are added last
+		for _, s := range c.strs {
+			if s != nil {
+				c.addEnumVal(*s)
+			}
+		}
+
+		col, err := ecolumn.New(c.strs, c.enumVals)
+		if err != nil {
+			return nil, err
+		}
+		return col, nil
+	case types.String:
+		if c.blob.Pointers == nil {
+			c.blob.Pointers = []qfstrings.Pointer{}
+		}
+		return c.blob, nil
+	}
+
+	return nil, errParquet("unsupported column type: %s", c.leaf.dataType)
+}
+
+func readFooter(r io.ReaderAt, size int64) (tstruct, error) {
+	if size < int64(2*len(magic)+4) {
+		return nil, errParquet("file too small")
+	}
+
+	tail := make([]byte, 8)
+	if _, err := r.ReadAt(tail, size-8); err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 4)
+	if _, err := r.ReadAt(head, 0); err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(tail[4:], magic) || !bytes.Equal(head, magic) {
+		return nil, errParquet("not a parquet file")
+	}
+
+	metaSize := int64(binary.LittleEndian.Uint32(tail))
+	if metaSize > size-12 {
+		return nil, errParquet("invalid metadata size: %d", metaSize)
+	}
+
+	meta := make([]byte, metaSize)
+	if _, err := r.ReadAt(meta, size-8-metaSize); err != nil {
+		return nil, err
+	}
+
+	fileMeta, _ := decodeStruct(meta)
+	return fileMeta, nil
+}
+
+func selectLeaves(leaves []leaf, columns []string) ([]leaf, error) {
+	if columns == nil {
+		for _, l := range leaves {
+			if !l.supported {
+				return nil, errParquet("unsupported column: %s, use Columns to select other columns", l.name)
+			}
+		}
+		return leaves, nil
+	}
+
+	byName := make(map[string]leaf, len(leaves))
+	for _, l := range leaves {
+		byName[l.name] = l
+	}
+
+	result := make([]leaf, len(columns))
+	for i, name := range columns {
+		l, ok := byName[name]
+		if !ok {
+			return nil, errParquet("unknown column: %s", name)
+		}
+
+		if !l.supported {
+			return nil, errParquet("unsupported column: %s", name)
+		}
+		result[i] = l
+	}
+	return result, nil
+}
+
+// Read reads a Parquet file of size bytes from r. It returns the data of the columns,
+// as accepted by qframe.New, and the column names in order.
+func Read(r io.ReaderAt, size int64, conf ReadConfig) (data map[string]interface{}, names []string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg, ok := r.(errInvalid)
+			if !ok {
+				panic(r)
+			}
+			data, names, err = nil, nil, errParquet("invalid parquet data: %s", string(msg))
+		}
+	}()
+
+	fileMeta, err := readFooter(r, size)
+	if err != nil {
+		return nil, nil, errors.Propagate("ReadParquet", err)
+	}
+
+	leaves, err := selectLeaves(readLeaves(fileMeta.structs(fileMetaSchema)), conf.Columns)
+	if err != nil {
+		return nil, nil, errors.Propagate("ReadParquet", err)
+	}
+
+	rowGroups := fileMeta.structs(fileMetaRowGroups)
+	selected := conf.RowGroups
+	if selected == nil {
+		selected = make([]int, len(rowGroups))
+		for i := range selected {
+			selected[i] = i
+		}
+	}
+
+	readers := make([]*columnReader, len(leaves))
+	for i, l := range leaves {
+		readers[i] = &columnReader{leaf: l}
+	}
+
+	rows := int64(0)
+	for _, g := range selected {
+		if g < 0 || g >= len(rowGroups) {
+			return nil, nil, errParquet("row group out of range: %d", g)
+		}
+
+		// Frames are indexed using uint32
+		numRows := rowGroups[g].int(rowGroupNumRows)
+		if numRows < 0 || rows+numRows > math.MaxUint32 {
+			return nil, nil, errParquet("invalid number of rows in row group %d: %d", g, numRows)
+		}
+		rows += numRows
+
+		chunks := rowGroups[g].structs(rowGroupColumns)
+		for _, c := range readers {
+			if c.leaf.chunk >= len(chunks) {
+				return nil, nil, errParquet("missing column chunk for column %s", c.leaf.name)
+			}
+
+			meta := chunks[c.leaf.chunk].strct(columnChunkMetaData)
+			start := meta.int(columnMetaDataPageOffset)
+			if meta.has(columnMetaDictionaryPageOffset) && meta.int(columnMetaDictionaryPageOffset) < start {
+				start = meta.int(columnMetaDictionaryPageOffset)
+			}
+
+			length := meta.int(columnMetaTotalCompressedSize)
+			if start < 0 || length < 0 || start+length > size {
+				return nil, nil, errParquet("invalid column chunk for column %s", c.leaf.name)
+			}
+
+			chunk := make([]byte, length)
+			if _, err := r.ReadAt(chunk, start); err != nil {
+				return nil, nil, errors.Propagate("ReadParquet", err)
+			}
+
+			// Columns are flat, one value per row
+			if numValues := meta.int(columnMetaNumValues); numValues != numRows {
+				return nil, nil, errParquet("expected %d values in column %s, found %d", numRows, c.leaf.name, numValues)
+			}
+
+			c.dictionary = nil
+			if err := c.readChunk(chunk, meta.int(columnMetaCodec), numRows); err != nil {
+				return nil, nil, errors.Propagate("ReadParquet", err)
+			}
+		}
+	}
+
+	data = make(map[string]interface{}, len(readers))
+	names = make([]string, len(readers))
+	for i, c := range readers {
+		if int64(c.size) != rows {
+			return nil, nil, errParquet("expected %d values in column %s, found %d", rows, c.leaf.name, c.size)
+		}
+
+		colData, err := c.data()
+		if err != nil {
+			return nil, nil, errors.Propagate("ReadParquet", err)
+		}
+		data[c.leaf.name] = colData
+		names[i] = c.leaf.name
+	}
+
+	return data, names, nil
+}
@@ -0,0 +1,199 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tobgu/qframe/internal/bcolumn"
+	"github.com/tobgu/qframe/internal/icolumn"
+	"github.com/tobgu/qframe/internal/tcolumn"
+)
+
+func testPage(pageType int64, levels, data []byte, headerID int16, header tfields) []byte {
+	compressed := snappyEncode(data)
+	body := append(append([]byte{}, levels...), compressed...)
+	buf := encodeStruct(nil, tfields{
+		{pageHeaderType, ti32(pageType)},
+		{pageHeaderUncompressedPageSize, ti32(len(levels) + len(data))},
+		{pageHeaderCompressedPageSize, ti32(len(body))},
+		{headerID, header},
+	})
+	return append(buf, body...)
+}
+
+func le32(values ...uint32) []byte {
+	buf := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(buf[4*i:], v)
+	}
+	return buf
+}
+
+func dataPageV1(count int, encoding int64, data []byte) []byte {
+	header := tfields{
+		{dataPageNumValues, ti32(count)},
+		{dataPageEncoding, ti32(encoding)},
+		{dataPageDefinitionLevelEncoding, ti32(encodingRLE)},
+		{dataPageRepetitionLevelEncoding, ti32(encodingRLE)}}
+	return testPage(pageData, nil, data, pageHeaderDataPageHeader, header)
+}
+
+func dataPageV2(count int, encoding int64, levels, data []byte) []byte {
+	header := tfields{
+		{dataPageV2NumValues, ti32(count)},
+		{dataPageV2NumNulls, ti32(0)},
+		{dataPageV2NumRows, ti32(count)},
+		{dataPageV2Encoding, ti32(encoding)},
+		{dataPageV2DefinitionLevelsByteLength, ti32(len(levels))},
+		{dataPageV2RepetitionLevelsByteLength, ti32(0)}}
+	return testPage(pageDataV2, levels, data, pageHeaderDataPageHeaderV2, header)
+}
+
+func leafElement(name string, physical int64, repetition int64, extra ...tfield) tfields {
+	return append(tfields{{schemaType, ti32(physical)}, {schemaRepetition, ti32(repetition)}, {schemaName, tbin(name)}}, extra...)
+}
+
+// testFile builds a file with a single row group of three rows using encodings,
+// page versions and types not produced by the writer.
+func testFile() []byte {
+	julian := make([]byte, 12)
+	binary.LittleEndian.PutUint64(julian, uint64(time.Hour))
+	binary.LittleEndian.PutUint32(julian[8:], julianUnixEpochDays+1)
+	int96 := append(append(append([]byte{}, julian...), julian...), julian...)
+
+	chunks := [][]byte{
+		// Dictionary encoded int32
+		append(
+			testPage(pageDictionary, nil, le32(10, 20), pageHeaderDictionaryPageHeader,
+				tfields{{dictionaryPageNumValues, ti32(2)}, {dictionaryPageEncoding, ti32(encodingPlain)}}),
+			dataPageV2(3, encodingRLEDictionary, nil, rleEncode([]byte{1}, []uint32{1, 0, 1}, 1))...),
+		// Nested group with one child, skipped
+		dataPageV1(3, encodingPlain, le32(1, 2, 3)),
+		// Optional float with a null
+		dataPageV2(3, encodingPlain, rleEncode(nil, []uint32{1, 0, 1}, 1), le32(math.Float32bits(1.5), math.Float32bits(2.5))),
+		// RLE encoded booleans
+		dataPageV1(3, encodingRLE, append(le32(2), rleEncode(nil, []uint32{1, 0, 1}, 1)...)),
+		dataPageV1(3, encodingPlain, int96),
+		dataPageV1(3, encodingPlain, le32(0, 1, 365)),
+		dataPageV1(3, encodingPlain, append(le32(1000, 0, 2000, 0), le32(3000, 0)...)),
+	}
+
+	schema := tlist{
+		tfields{{schemaName, tbin("schema")}, {schemaNumChildren, ti32(7)}},
+		leafElement("i32", typeInt32, repetitionRequired),
+		tfields{{schemaRepetition, ti32(repetitionOptional)}, {schemaName, tbin("nested")}, {schemaNumChildren, ti32(1)}},
+		leafElement("child", typeInt32, repetitionRequired),
+		leafElement("f32", typeFloat, repetitionOptional),
+		leafElement("b", typeBoolean, repetitionRequired),
+		leafElement("ts", typeInt96, repetitionRequired),
+		leafElement("date", typeInt32, repetitionRequired, tfield{schemaConvertedType, ti32(convertedDate)}),
+		leafElement("millis", typeInt64, repetitionRequired, tfield{schemaConvertedType, ti32(convertedTimestampMillis)}),
+	}
+	return buildFile(schema, chunks, 3, 3)
+}
+
+// buildFile builds a file with a single row group of numRows rows from schema and chunks,
+// each chunk declaring numValues values.
+func buildFile(schema tlist, chunks [][]byte, numValues, numRows int64) []byte {
+
+	file := append([]byte{}, magic...)
+	var columnChunks tlist
+	for _, c := range chunks {
+		meta := tfields{
+			{columnMetaCodec, ti32(codecSnappy)},
+			{columnMetaNumValues, ti64(numValues)},
+			{columnMetaTotalCompressedSize, ti64(len(c))},
+			{columnMetaDataPageOffset, ti64(len(file))},
+		}
+		columnChunks = append(columnChunks, tfields{{columnChunkFileOffset, ti64(len(file))}, {columnChunkMetaData, meta}})
+		file = append(file, c...)
+	}
+
+	meta := encodeStruct(nil, tfields{
+		{fileMetaVersion, ti32(2)},
+		{fileMetaSchema, schema},
+		{fileMetaNumRows, ti64(numRows)},
+		{fileMetaRowGroups, tlist{tfields{{rowGroupColumns, columnChunks}, {rowGroupNumRows, ti64(numRows)}}}},
+	})
+	file = append(file, meta...)
+	file = append(file, le32(uint32(len(meta)))...)
+	return append(file, magic...)
+}
+
+func TestRead(t *testing.T) {
+	file := testFile()
+	_, _, err := Read(bytes.NewReader(file), int64(len(file)), ReadConfig{})
+	if err == nil || !bytes.Contains([]byte(err.Error()), []byte("unsupported column: nested")) {
+		t.Fatalf("expected unsupported column error, got: %v", err)
+	}
+
+	columns := []string{"i32", "f32", "b", "ts", "date", "millis"}
+	data, names, err := Read(bytes.NewReader(file), int64(len(file)), ReadConfig{Columns: columns})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(names, columns) {
+		t.Errorf("unexpected column names: %v", names)
+	}
+
+	day := int64(24 * time.Hour)
+	expected := map[string]interface{}{
+		"i32":    icolumn.New([]int{20, 10, 20}),
+		"b":      bcolumn.New([]bool{true, false, true}),
+		"ts":     tcolumn.NewNanos([]int64{day + int64(time.Hour), day + int64(time.Hour), day + int64(time.Hour)}, nil, time.UTC),
+		"date":   tcolumn.NewNanos([]int64{0, day, 365 * day}, nil, time.UTC),
+		"millis": tcolumn.NewNanos([]int64{int64(time.Second), 2 * int64(time.Second), 3 * int64(time.Second)}, nil, time.UTC),
+	}
+
+	for name, col := range expected {
+		if !reflect.DeepEqual(data[name], col) {
+			t.Errorf("unexpected data in %s: %v", name, data[name])
+		}
+	}
+
+	floats := data["f32"].([]float64)
+	if len(floats) != 3 || floats[0] != 1.5 || !math.IsNaN(floats[1]) || floats[2] != 2.5 {
+		t.Errorf("unexpected data in f32: %v", floats)
+	}
+}
+
+func TestReadInvalidCounts(t *testing.T) {
+	schema := tlist{
+		tfields{{schemaName, tbin("schema")}, {schemaNumChildren, ti32(1)}},
+		leafElement("i32", typeInt32, repetitionRequired),
+	}
+	page := dataPageV1(3, encodingPlain, le32(1, 2, 3))
+	dictionary := testPage(pageDictionary, nil, le32(10, 20), pageHeaderDictionaryPageHeader,
+		tfields{{dictionaryPageNumValues, ti32(-1)}, {dictionaryPageEncoding, ti32(encodingPlain)}})
+
+	table := []struct {
+		name      string
+		chunk     []byte
+		numValues int64
+		numRows   int64
+		err       string
+	}{
+		{name: "negative page count", chunk: dataPageV1(-1, encodingPlain, le32(1, 2, 3)), numValues: 3, numRows: 3, err: "invalid number of values in page"},
+		{name: "large page count", chunk: dataPageV1(1<<30, encodingPlain, le32(1, 2, 3)), numValues: 3, numRows: 3, err: "invalid number of values in page"},
+		{name: "negative v2 page count", chunk: dataPageV2(-1, encodingPlain, nil, le32(1, 2, 3)), numValues: 3, numRows: 3, err: "invalid number of values in page"},
+		{name: "dictionary count", chunk: append(dictionary, page...), numValues: 3, numRows: 3, err: "invalid number of page values"},
+		{name: "chunk values", chunk: page, numValues: 0, numRows: 3, err: "expected 3 values in column i32, found 0"},
+		{name: "negative rows", chunk: page, numValues: -1, numRows: -1, err: "invalid number of rows"},
+		{name: "too many rows", chunk: page, numValues: 1 << 33, numRows: 1 << 33, err: "invalid number of rows"},
+	}
+
+	for _, tc := range table {
+		t.Run(tc.name, func(t *testing.T) {
+			file := buildFile(schema, [][]byte{tc.chunk}, tc.numValues, tc.numRows)
+			_, _, err := Read(bytes.NewReader(file), int64(len(file)), ReadConfig{})
+			if err == nil || !bytes.Contains([]byte(err.Error()), []byte(tc.err)) {
+				t.Errorf("expected error containing %q, got: %v", tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package parquet
+
+import (
+	"encoding/binary"
+)
+
+// Definition levels, dictionary indices and booleans are encoded using the
+// RLE/bit-packing hybrid encoding.
+// See https://github.com/apache/parquet-format/blob/master/Encodings.md
+
+// rleDecode decodes count values of bitWidth bits from buf.
+func rleDecode(buf []byte, bitWidth int, count int) ([]uint32, error) {
+	if bitWidth < 0 || bitWidth > 32 {
+		return nil, errParquet("invalid bit width: %d", bitWidth)
+	}
+
+	result := make([]uint32, 0, count)
+	byteWidth := (bitWidth + 7) / 8
+	pos := 0
+	for len(result) < count {
+		header, n := binary.Uvarint(buf[pos:])
+		if n <= 0 {
+			return nil, errParquet("invalid rle header")
+		}
+		pos += n
+
+		if header&1 == 0 {
+			// RLE run
+			runLength := int(header >> 1)
+			if pos+byteWidth > len(buf) {
+				return nil, errParquet("truncated rle run")
+			}
+
+			var value uint32
+			for i := 0; i < byteWidth; i++ {
+				value |= uint32(buf[pos+i]) << uint(8*i)
+			}
+			pos += byteWidth
+
+			for i := 0; i < runLength && len(result) < count; i++ {
+				result = append(result, value)
+			}
+			continue
+		}
+
+		// Bit packed run of groups of eight values
+		groups := int(header >> 1)
+		size := groups * bitWidth
+		if size < 0 || pos+size > len(buf) {
+			return nil, errParquet("truncated bit packed run")
+		}
+
+		values := unpackBits(buf[pos:pos+size], bitWidth, groups*8)
+		pos += size
+		for _, v := range values {
+			if len(result) == count {
+				break
+			}
+			result = append(result, v)
+		}
+	}
+
+	return result, nil
+}
+
+// unpackBits unpacks count values of bitWidth bits, packed from the least significant bit.
+func unpackBits(buf []byte, bitWidth int, count int) []uint32 {
+	result := make([]uint32, count)
+	if bitWidth == 0 {
+		return result
+	}
+
+	bit := 0
+	for i := range result {
+		var v uint32
+		for b := 0; b < bitWidth; b++ {
+			if buf[bit>>3]&(1<<uint(bit&7)) != 0 {
+				v |= 1 << uint(b)
+			}
+			bit++
+		}
+		result[i] = v
+	}
+	return result
+}
+
+// rleEncode encodes values of bitWidth bits. Runs of at least eight equal values
+// are RLE encoded, all other values are bit packed.
+func rleEncode(buf []byte, values []uint32, bitWidth int) []byte {
+	byteWidth := (bitWidth + 7) / 8
+	var tmp [binary.MaxVarintLen64]byte
+
+	// Bit packed values are collected in groups of eight and written once a run
+	// of equal values, or the end of input, is found.
+	packStart := 0
+	flushPacked := func(end int) {
+		if end == packStart {
+			return
+		}
+
+		groups := (end - packStart + 7) / 8
+		n := binary.PutUvarint(tmp[:], uint64(groups<<1|1))
+		buf = append(buf, tmp[:n]...)
+		packed := make([]byte, groups*bitWidth)
+		bit := 0
+		for _, v := range values[packStart:end] {
+			for b := 0; b < bitWidth; b++ {
+				if v&(1<<uint(b)) != 0 {
+					packed[bit>>3] |= 1 << uint(bit&7)
+				}
+				bit++
+			}
+		}
+		buf = append(buf, packed...)
+	}
+
+	for i := 0; i < len(values); {
+		runEnd := i + 1
+		for runEnd < len(values) && values[runEnd] == values[i] {
+			runEnd++
+		}
+
+		// Bit packed groups must contain eight values, except the last one. Runs are
+		// only started at group boundaries to avoid padding in the middle of the data.
+		if runEnd-i >= 8 && (i-packStart)%8 == 0 {
+			flushPacked(i)
+			n := binary.PutUvarint(tmp[:], uint64((runEnd-i)<<1))
+			buf = append(buf, tmp[:n]...)
+			for b := 0; b < byteWidth; b++ {
+				buf = append(buf, byte(values[i]>>uint(8*b)))
+			}
+			i = runEnd
+			packStart = i
+			continue
+		}
+
+		i++
+	}
+
+	flushPacked(len(values))
+	return buf
+}
+
+// bitWidth returns the number of bits needed to represent max.
+func bitWidth(max int) int {
+	width := 0
+	for max > 0 {
+		width++
+		max >>= 1
+	}
+	return width
+}
@@ -0,0 +1,53 @@
+package parquet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRLERoundTrip(t *testing.T) {
+	table := []struct {
+		name     string
+		values   []uint32
+		bitWidth int
+	}{
+		{name: "empty", values: []uint32{}, bitWidth: 1},
+		{name: "zero width", values: []uint32{0, 0, 0}, bitWidth: 0},
+		{name: "packed", values: []uint32{1, 0, 1, 1, 0}, bitWidth: 1},
+		{name: "run", values: []uint32{5, 5, 5, 5, 5, 5, 5, 5, 5, 5}, bitWidth: 3},
+		{name: "mixed", values: []uint32{1, 2, 3, 4, 5, 6, 7, 0, 9, 9, 9, 9, 9, 9, 9, 9, 9, 1, 2}, bitWidth: 4},
+		{name: "wide", values: []uint32{70000, 1, 70000, 70000, 70000, 70000, 70000, 70000, 70000, 70000}, bitWidth: 17},
+	}
+
+	for _, tc := range table {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded := rleEncode(nil, tc.values, tc.bitWidth)
+			decoded, err := rleDecode(encoded, tc.bitWidth, len(tc.values))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(decoded, tc.values) {
+				t.Errorf("expected %v, got %v", tc.values, decoded)
+			}
+		})
+	}
+}
+
+func TestRLEDecode(t *testing.T) {
+	// RLE run of four 3:s followed by one bit packed group, from the Parquet encoding docs
+	buf := []byte{4 << 1, 3, 1<<1 | 1, 0x88, 0xC6, 0xFA}
+	decoded, err := rleDecode(buf, 3, 12)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []uint32{3, 3, 3, 3, 0, 1, 2, 3, 4, 5, 6, 7}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("expected %v, got %v", expected, decoded)
+	}
+
+	if _, err := rleDecode(buf[:4], 3, 12); err == nil {
+		t.Errorf("expected error on truncated input")
+	}
+}
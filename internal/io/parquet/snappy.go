@@ -0,0 +1,194 @@
+package parquet
+
+import (
+	"encoding/binary"
+)
+
+// Parquet pages compressed with snappy use the raw snappy block format.
+// See https://github.com/google/snappy/blob/master/format_description.txt
+
+const (
+	snappyLiteral = 0
+	snappyCopy1   = 1
+	snappyCopy2   = 2
+	snappyCopy4   = 3
+)
+
+// Max size of a block within which the encoder looks for matches
+const snappyBlockSize = 1 << 16
+
+const snappyHashBits = 14
+
+func snappyDecode(src []byte) ([]byte, error) {
+	length, n := binary.Uvarint(src)
+	if n <= 0 || length > uint64(0xFFFFFFFF) {
+		return nil, errSnappy("invalid length header")
+	}
+
+	dst := make([]byte, 0, length)
+	for s := n; s < len(src); {
+		tag := src[s]
+		s++
+		var offset, size int
+		switch tag & 0x03 {
+		case snappyLiteral:
+			size = int(tag >> 2)
+			if size >= 60 {
+				extra := size - 59
+				if s+extra > len(src) {
+					return nil, errSnappy("truncated literal length")
+				}
+
+				size = 0
+				for i := 0; i < extra; i++ {
+					size |= int(src[s+i]) << uint(8*i)
+				}
+				s += extra
+			}
+			size++
+
+			if size <= 0 || s+size > len(src) || s+size < s {
+				return nil, errSnappy("truncated literal")
+			}
+			dst = append(dst, src[s:s+size]...)
+			s += size
+			continue
+		case snappyCopy1:
+			if s+1 > len(src) {
+				return nil, errSnappy("truncated copy")
+			}
+			size = int(tag>>2&0x07) + 4
+			offset = int(tag>>5)<<8 | int(src[s])
+			s++
+		case snappyCopy2:
+			if s+2 > len(src) {
+				return nil, errSnappy("truncated copy")
+			}
+			size = int(tag>>2) + 1
+			offset = int(binary.LittleEndian.Uint16(src[s:]))
+			s += 2
+		case snappyCopy4:
+			if s+4 > len(src) {
+				return nil, errSnappy("truncated copy")
+			}
+			size = int(tag>>2) + 1
+			offset = int(binary.LittleEndian.Uint32(src[s:]))
+			s += 4
+		}
+
+		if offset <= 0 || offset > len(dst) {
+			return nil, errSnappy("invalid copy offset")
+		}
+
+		// Copies may overlap the bytes being written, hence byte by byte
+		start := len(dst) - offset
+		for i := 0; i < size; i++ {
+			dst = append(dst, dst[start+i])
+		}
+	}
+
+	if uint64(len(dst)) != length {
+		return nil, errSnappy("length mismatch")
+	}
+
+	return dst, nil
+}
+
+type errSnappy string
+
+func (e errSnappy) Error() string {
+	return "snappy: " + string(e)
+}
+
+func snappyEncode(src []byte) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], uint64(len(src)))
+	dst := make([]byte, 0, n+len(src)+len(src)/6+32)
+	dst = append(dst, tmp[:n]...)
+
+	var table [1 << snappyHashBits]int32
+	for start := 0; start < len(src); start += snappyBlockSize {
+		end := start + snappyBlockSize
+		if end > len(src) {
+			end = len(src)
+		}
+		dst = snappyEncodeBlock(dst, src[start:end], &table)
+	}
+
+	return dst
+}
+
+func snappyHash(u uint32) uint32 {
+	return (u * 0x1e35a7bd) >> (32 - snappyHashBits)
+}
+
+// snappyEncodeBlock greedily replaces repeated sequences of at least four bytes
+// in block with copies.
+func snappyEncodeBlock(dst, block []byte, table *[1 << snappyHashBits]int32) []byte {
+	for i := range table {
+		table[i] = -1
+	}
+
+	literalStart := 0
+	for i := 0; i+4 <= len(block); {
+		current := binary.LittleEndian.Uint32(block[i:])
+		h := snappyHash(current)
+		candidate := int(table[h])
+		table[h] = int32(i)
+		if candidate < 0 || binary.LittleEndian.Uint32(block[candidate:]) != current {
+			i++
+			continue
+		}
+
+		dst = snappyEmitLiteral(dst, block[literalStart:i])
+		length := 4
+		for i+length < len(block) && block[candidate+length] == block[i+length] {
+			length++
+		}
+
+		dst = snappyEmitCopy(dst, i-candidate, length)
+		i += length
+		literalStart = i
+	}
+
+	return snappyEmitLiteral(dst, block[literalStart:])
+}
+
+func snappyEmitLiteral(dst, literal []byte) []byte {
+	if len(literal) == 0 {
+		return dst
+	}
+
+	n := len(literal) - 1
+	switch {
+	case n < 60:
+		dst = append(dst, byte(n)<<2|snappyLiteral)
+	case n < 1<<8:
+		dst = append(dst, 60<<2|snappyLiteral, byte(n))
+	case n < 1<<16:
+		dst = append(dst, 61<<2|snappyLiteral, byte(n), byte(n>>8))
+	case n < 1<<24:
+		dst = append(dst, 62<<2|snappyLiteral, byte(n), byte(n>>8), byte(n>>16))
+	default:
+		dst = append(dst, 63<<2|snappyLiteral, byte(n), byte(n>>8), byte(n>>16), byte(n>>24))
+	}
+	return append(dst, literal...)
+}
+
+func snappyEmitCopy(dst []byte, offset, length int) []byte {
+	for length >= 68 {
+		dst = append(dst, 63<<2|snappyCopy2, byte(offset), byte(offset>>8))
+		length -= 64
+	}
+
+	if length > 64 {
+		dst = append(dst, 59<<2|snappyCopy2, byte(offset), byte(offset>>8))
+		length -= 60
+	}
+
+	if length < 12 && offset < 2048 {
+		return append(dst, byte(offset>>8)<<5|byte(length-4)<<2|snappyCopy1, byte(offset))
+	}
+
+	return append(dst, byte(length-1)<<2|snappyCopy2, byte(offset), byte(offset>>8))
+}
@@ -0,0 +1,60 @@
+package parquet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSnappyRoundTrip(t *testing.T) {
+	random := make([]byte, 100000)
+	x := uint32(1)
+	for i := range random {
+		x = x*1664525 + 1013904223
+		random[i] = byte(x >> 24)
+	}
+
+	table := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: []byte{}},
+		{name: "short", data: []byte("abc")},
+		{name: "repeated", data: []byte(strings.Repeat("abcdefgh", 20000))},
+		{name: "random", data: random},
+		{name: "long literal", data: random[:5000]},
+	}
+
+	for _, tc := range table {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded := snappyEncode(tc.data)
+			decoded, err := snappyDecode(encoded)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !bytes.Equal(decoded, tc.data) {
+				t.Errorf("round trip failed, got %d bytes, expected %d", len(decoded), len(tc.data))
+			}
+		})
+	}
+}
+
+func TestSnappyDecode(t *testing.T) {
+	// Length 12, literal "abcd", copy with offset 4 and length 8
+	src := []byte{12, 3 << 2, 'a', 'b', 'c', 'd', 4<<2 | snappyCopy1, 4}
+	decoded, err := snappyDecode(src)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if string(decoded) != "abcdabcdabcd" {
+		t.Errorf("unexpected result: %s", decoded)
+	}
+
+	for _, invalid := range [][]byte{{}, {12, 3 << 2, 'a'}, {4, 4<<2 | snappyCopy1, 4}, {13, 3 << 2, 'a', 'b', 'c', 'd', 4<<2 | snappyCopy1, 4}} {
+		if _, err := snappyDecode(invalid); err == nil {
+			t.Errorf("expected error for %v", invalid)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// The Parquet metadata is serialized using the Thrift compact protocol. Only the
+// parts of the protocol needed to read and write Parquet metadata are implemented.
+// See https://github.com/apache/thrift/blob/master/doc/specs/thrift-compact-protocol.md
+
+// Compact protocol types
+const (
+	tStop       = 0
+	tBoolTrue   = 1
+	tBoolFalse  = 2
+	tByte       = 3
+	tI16        = 4
+	tI32        = 5
+	tI64        = 6
+	tDouble     = 7
+	tBinary     = 8
+	tListType   = 9
+	tSetType    = 10
+	tMapType    = 11
+	tStructType = 12
+)
+
+// Max nesting of structs and containers accepted when decoding
+const maxThriftDepth = 64
+
+// errInvalid is used in panics raised on malformed input, these are recovered
+// and turned into errors by the reader.
+type errInvalid string
+
+func invalid(format string, args ...interface{}) {
+	panic(errInvalid(fmt.Sprintf(format, args...)))
+}
+
+// tstruct is a decoded thrift struct, field id -> value. Values are int64 for all
+// integer types, bool, float64, []byte for binary, []interface{} for lists and sets,
+// tstruct for structs. Maps are skipped.
+type tstruct map[int16]interface{}
+
+func (s tstruct) int(id int16) int64 {
+	v, _ := s[id].(int64)
+	return v
+}
+
+func (s tstruct) has(id int16) bool {
+	_, ok := s[id]
+	return ok
+}
+
+func (s tstruct) bool(id int16) bool {
+	v, _ := s[id].(bool)
+	return v
+}
+
+func (s tstruct) string(id int16) string {
+	v, _ := s[id].([]byte)
+	return string(v)
+}
+
+func (s tstruct) strct(id int16) tstruct {
+	v, _ := s[id].(tstruct)
+	return v
+}
+
+func (s tstruct) list(id int16) []interface{} {
+	v, _ := s[id].([]interface{})
+	return v
+}
+
+func (s tstruct) structs(id int16) []tstruct {
+	l := s.list(id)
+	result := make([]tstruct, 0, len(l))
+	for _, v := range l {
+		if st, ok := v.(tstruct); ok {
+			result = append(result, st)
+		}
+	}
+	return result
+}
+
+type thriftDecoder struct {
+	buf []byte
+	pos int
+}
+
+func (d *thriftDecoder) byte() byte {
+	if d.pos >= len(d.buf) {
+		invalid("unexpected end of thrift data")
+	}
+	b := d.buf[d.pos]
+	d.pos++
+	return b
+}
+
+func (d *thriftDecoder) uvarint() uint64 {
+	x, n := binary.Uvarint(d.buf[d.pos:])
+	if n <= 0 {
+		invalid("invalid varint in thrift data")
+	}
+	d.pos += n
+	return x
+}
+
+func (d *thriftDecoder) varint() int64 {
+	x := d.uvarint()
+	return int64(x>>1) ^ -int64(x&1)
+}
+
+func (d *thriftDecoder) bytes(n int) []byte {
+	if n < 0 || d.pos+n > len(d.buf) || d.pos+n < d.pos {
+		invalid("unexpected end of thrift data")
+	}
+	b := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return b
+}
+
+func (d *thriftDecoder) value(typ byte, depth int) interface{} {
+	if depth > maxThriftDepth {
+		invalid("thrift data nested too deep")
+	}
+
+	switch typ {
+	case tBoolTrue:
+		return true
+	case tBoolFalse:
+		return false
+	case tByte:
+		return int64(int8(d.byte()))
+	case tI16, tI32, tI64:
+		return d.varint()
+	case tDouble:
+		return math.Float64frombits(binary.LittleEndian.Uint64(d.bytes(8)))
+	case tBinary:
+		return d.bytes(int(d.uvarint()))
+	case tListType, tSetType:
+		header := d.byte()
+		size, elemType := int(header>>4), header&0x0F
+		if size == 15 {
+			size = int(d.uvarint())
+		}
+
+		if size > len(d.buf)-d.pos {
+			invalid("invalid thrift list size: %d", size)
+		}
+
+		result := make([]interface{}, size)
+		for i := range result {
+			if elemType == tBoolTrue || elemType == tBoolFalse {
+				// Bools in collections are encoded as one byte each
+				result[i] = d.byte() == tBoolTrue
+			} else {
+				result[i] = d.value(elemType, depth+1)
+			}
+		}
+		return result
+	case tMapType:
+		size := int(d.uvarint())
+		if size == 0 {
+			return nil
+		}
+
+		types := d.byte()
+		for i := 0; i < size; i++ {
+			d.value(types>>4, depth+1)
+			d.value(types&0x0F, depth+1)
+		}
+		return nil
+	case tStructType:
+		return d.strct(depth + 1)
+	}
+
+	invalid("unknown thrift type: %d", typ)
+	return nil
+}
+
+func (d *thriftDecoder) strct(depth int) tstruct {
+	result := tstruct{}
+	var id int16
+	for {
+		header := d.byte()
+		if header == tStop {
+			return result
+		}
+
+		typ := header & 0x0F
+		if delta := int16(header >> 4); delta != 0 {
+			id += delta
+		} else {
+			id = int16(d.varint())
+		}
+		result[id] = d.value(typ, depth)
+	}
+}
+
+// decodeStruct decodes a thrift struct from the start of buf and returns it together
+// with the number of bytes consumed.
+func decodeStruct(buf []byte) (tstruct, int) {
+	d := &thriftDecoder{buf: buf}
+	s := d.strct(0)
+	return s, d.pos
+}
+
+// Values used when encoding thrift structs. Each field value must be one of these types.
+type (
+	ti32  int32
+	ti64  int64
+	tbool bool
+	tbin  []byte
+	// tlist is a list of values of the same type.
+	tlist []interface{}
+	// tfields is a struct, the fields must be ordered by id.
+	tfields []tfield
+)
+
+type tfield struct {
+	id    int16
+	value interface{}
+}
+
+func thriftType(v interface{}) byte {
+	switch x := v.(type) {
+	case ti32:
+		return tI32
+	case ti64:
+		return tI64
+	case tbool:
+		if x {
+			return tBoolTrue
+		}
+		return tBoolFalse
+	case tbin:
+		return tBinary
+	case tlist:
+		return tListType
+	case tfields:
+		return tStructType
+	}
+	panic(fmt.Sprintf("unsupported thrift value: %T", v))
+}
+
+type thriftEncoder struct {
+	buf []byte
+}
+
+func (e *thriftEncoder) uvarint(x uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], x)
+	e.buf = append(e.buf, tmp[:n]...)
+}
+
+func (e *thriftEncoder) varint(x int64) {
+	e.uvarint(uint64(x<<1) ^ uint64(x>>63))
+}
+
+func (e *thriftEncoder) value(v interface{}) {
+	switch x := v.(type) {
+	case ti32:
+		e.varint(int64(x))
+	case ti64:
+		e.varint(int64(x))
+	case tbool:
+		// Encoded in the field header
+	case tbin:
+		e.uvarint(uint64(len(x)))
+		e.buf = append(e.buf, x...)
+	case tlist:
+		elemType := byte(tStructType)
+		if len(x) > 0 {
+			elemType = thriftType(x[0])
+		}
+
+		if len(x) < 15 {
+			e.buf = append(e.buf, byte(len(x))<<4|elemType)
+		} else {
+			e.buf = append(e.buf, 0xF0|elemType)
+			e.uvarint(uint64(len(x)))
+		}
+
+		for _, elem := range x {
+			e.value(elem)
+		}
+	case tfields:
+		e.strct(x)
+	}
+}
+
+func (e *thriftEncoder) strct(fields tfields) {
+	var lastID int16
+	for _, f := range fields {
+		typ := thriftType(f.value)
+		if delta := f.id - lastID; delta > 0 && delta <= 15 {
+			e.buf = append(e.buf, byte(delta)<<4|typ)
+		} else {
+			e.buf = append(e.buf, typ)
+			e.varint(int64(f.id))
+		}
+		lastID = f.id
+		e.value(f.value)
+	}
+	e.buf = append(e.buf, tStop)
+}
+
+// encodeStruct appends the compact protocol encoding of fields to buf.
+func encodeStruct(buf []byte, fields tfields) []byte {
+	e := &thriftEncoder{buf: buf}
+	e.strct(fields)
+	return e.buf
+}
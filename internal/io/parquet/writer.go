@@ -0,0 +1,366 @@
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/tobgu/qframe/internal/bcolumn"
+	"github.com/tobgu/qframe/internal/column"
+	"github.com/tobgu/qframe/internal/ecolumn"
+	"github.com/tobgu/qframe/internal/fcolumn"
+	"github.com/tobgu/qframe/internal/icolumn"
+	"github.com/tobgu/qframe/internal/index"
+	"github.com/tobgu/qframe/internal/scolumn"
+	"github.com/tobgu/qframe/internal/tcolumn"
+)
+
+// Codec is the compression codec used for pages when writing.
+type Codec int
+
+const (
+	Uncompressed Codec = codecUncompressed
+	Snappy       Codec = codecSnappy
+	Gzip         Codec = codecGzip
+)
+
+type WriteConfig struct {
+	Codec            Codec
+	CompressionLevel int
+	RowGroupSize     int
+}
+
+// chunk holds the encoded pages of a column chunk before compression.
+type chunk struct {
+	defined    []uint32
+	values     []byte
+	encoding   int64
+	dictionary []byte
+	dictSize   int
+}
+
+// encodeChunk encodes the elements referenced by ix of col.
+func encodeChunk(col column.Column, ix index.Int) (chunk, error) {
+	c := chunk{defined: make([]uint32, len(ix)), encoding: encodingPlain}
+	var tmp [8]byte
+	switch col := col.(type) {
+	case icolumn.Column:
+		view := col.View(ix)
+		for i := range ix {
+			if !view.IsNull(i) {
+				c.defined[i] = 1
+				binary.LittleEndian.PutUint64(tmp[:], uint64(view.ItemAt(i)))
+				c.values = append(c.values, tmp[:]...)
+			}
+		}
+	case fcolumn.Column:
+		view := col.View(ix)
+		for i := range ix {
+			if x := view.ItemAt(i); !math.IsNaN(x) {
+				c.defined[i] = 1
+				binary.LittleEndian.PutUint64(tmp[:], math.Float64bits(x))
+				c.values = append(c.values, tmp[:]...)
+			}
+		}
+	case bcolumn.Column:
+		view := col.View(ix)
+		c.values = make([]byte, (len(ix)+7)/8)
+		count := 0
+		for i := range ix {
+			if !view.IsNull(i) {
+				c.defined[i] = 1
+				if view.ItemAt(i) {
+					c.values[count>>3] |= 1 << uint(count&7)
+				}
+				count++
+			}
+		}
+		c.values = c.values[:(count+7)/8]
+	case scolumn.Column:
+		view := col.View(ix)
+		for i := range ix {
+			if s := view.ItemAt(i); s != nil {
+				c.defined[i] = 1
+				c.values = appendByteArray(c.values, *s)
+			}
+		}
+	case ecolumn.Column:
+		values := col.Values()
+		codes := make(map[string]uint32, len(values))
+		for i, v := range values {
+			codes[v] = uint32(i)
+			c.dictionary = appendByteArray(c.dictionary, v)
+		}
+		c.dictSize = len(values)
+
+		view := col.View(ix)
+		indices := make([]uint32, 0, len(ix))
+		for i := range ix {
+			if s := view.ItemAt(i); s != nil {
+				c.defined[i] = 1
+				indices = append(indices, codes[*s])
+			}
+		}
+
+		width := bitWidth(len(values) - 1)
+		c.encoding = encodingPlainDictionary
+		c.values = rleEncode([]byte{byte(width)}, indices, width)
+	case tcolumn.Column:
+		view := col.View(ix)
+		for i := range ix {
+			if !view.IsNull(i) {
+				c.defined[i] = 1
+				binary.LittleEndian.PutUint64(tmp[:], uint64(view.ItemAt(i).UnixNano()))
+				c.values = append(c.values, tmp[:]...)
+			}
+		}
+	default:
+		return c, errParquet("unsupported column type: %s", col.DataType())
+	}
+
+	return c, nil
+}
+
+func appendByteArray(buf []byte, s string) []byte {
+	var tmp [4]byte
+	binary.LittleEndian.PutUint32(tmp[:], uint32(len(s)))
+	return append(append(buf, tmp[:]...), s...)
+}
+
+// schemaElement returns the schema element describing col.
+func schemaElement(name string, col column.Column) (tfields, int64, error) {
+	var fields tfields
+	var physical int64
+	switch col.(type) {
+	case icolumn.Column:
+		physical = typeInt64
+		fields = tfields{{schemaType, ti32(physical)}, {schemaRepetition, ti32(repetitionOptional)}, {schemaName, tbin(name)}}
+	case fcolumn.Column:
+		physical = typeDouble
+		fields = tfields{{schemaType, ti32(physical)}, {schemaRepetition, ti32(repetitionOptional)}, {schemaName, tbin(name)}}
+	case bcolumn.Column:
+		physical = typeBoolean
+		fields = tfields{{schemaType, ti32(physical)}, {schemaRepetition, ti32(repetitionOptional)}, {schemaName, tbin(name)}}
+	case scolumn.Column:
+		physical = typeByteArray
+		fields = tfields{
+			{schemaType, ti32(physical)}, {schemaRepetition, ti32(repetitionOptional)}, {schemaName, tbin(name)},
+			{schemaConvertedType, ti32(convertedUTF8)}, {schemaLogicalType, tfields{{logicalString, tfields{}}}}}
+	case ecolumn.Column:
+		physical = typeByteArray
+		fields = tfields{
+			{schemaType, ti32(physical)}, {schemaRepetition, ti32(repetitionOptional)}, {schemaName, tbin(name)},
+			{schemaConvertedType, ti32(convertedEnum)}, {schemaLogicalType, tfields{{logicalEnum, tfields{}}}}}
+	case tcolumn.Column:
+		// There is no converted type for nanosecond timestamps
+		physical = typeInt64
+		timestamp := tfields{{timestampIsAdjustedToUTC, tbool(true)}, {timestampUnit, tfields{{unitNanos, tfields{}}}}}
+		fields = tfields{
+			{schemaType, ti32(physical)}, {schemaRepetition, ti32(repetitionOptional)}, {schemaName, tbin(name)},
+			{schemaLogicalType, tfields{{logicalTimestamp, timestamp}}}}
+	default:
+		return nil, 0, errParquet("unsupported column type: %s", col.DataType())
+	}
+
+	return fields, physical, nil
+}
+
+func compress(conf WriteConfig, data []byte) ([]byte, error) {
+	switch conf.Codec {
+	case Uncompressed:
+		return data, nil
+	case Snappy:
+		return snappyEncode(data), nil
+	case Gzip:
+		buf := &bytes.Buffer{}
+		w, err := gzip.NewWriterLevel(buf, conf.CompressionLevel)
+		if err != nil {
+			return nil, errParquet("invalid compression level: %d", conf.CompressionLevel)
+		}
+
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	return nil, errParquet("unsupported compression codec: %d", conf.Codec)
+}
+
+type countingWriter struct {
+	w      io.Writer
+	offset int64
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	n, err := w.w.Write(b)
+	w.offset += int64(n)
+	return n, err
+}
+
+// writePage writes a page header followed by the compressed data. The total compressed
+// and uncompressed sizes, including the header, are returned.
+func writePage(w *countingWriter, conf WriteConfig, pageType int64, data []byte, headerID int16, header tfields) (int64, int64, error) {
+	compressed, err := compress(conf, data)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	pageHeader := encodeStruct(nil, tfields{
+		{pageHeaderType, ti32(pageType)},
+		{pageHeaderUncompressedPageSize, ti32(len(data))},
+		{pageHeaderCompressedPageSize, ti32(len(compressed))},
+		{headerID, header},
+	})
+
+	if _, err := w.Write(pageHeader); err != nil {
+		return 0, 0, err
+	}
+
+	if _, err := w.Write(compressed); err != nil {
+		return 0, 0, err
+	}
+
+	return int64(len(pageHeader) + len(compressed)), int64(len(pageHeader) + len(data)), nil
+}
+
+// writeChunk writes the pages of a column chunk and returns its ColumnChunk metadata.
+func writeChunk(w *countingWriter, conf WriteConfig, name string, physical int64, c chunk) (tfields, int64, error) {
+	start := w.offset
+	var compressedSize, uncompressedSize int64
+	var dictionaryOffset int64 = -1
+	encodings := tlist{ti32(encodingPlain), ti32(encodingRLE)}
+	if c.dictionary != nil || c.encoding == encodingPlainDictionary {
+		dictionaryOffset = w.offset
+		header := tfields{{dictionaryPageNumValues, ti32(c.dictSize)}, {dictionaryPageEncoding, ti32(encodingPlainDictionary)}}
+		compressed, uncompressed, err := writePage(w, conf, pageDictionary, c.dictionary, pageHeaderDictionaryPageHeader, header)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		compressedSize += compressed
+		uncompressedSize += uncompressed
+		encodings = tlist{ti32(encodingPlainDictionary), ti32(encodingRLE)}
+	}
+
+	// Definition levels are prefixed by their length in v1 pages
+	levels := rleEncode(make([]byte, 4), c.defined, 1)
+	binary.LittleEndian.PutUint32(levels, uint32(len(levels)-4))
+
+	dataOffset := w.offset
+	header := tfields{
+		{dataPageNumValues, ti32(len(c.defined))},
+		{dataPageEncoding, ti32(c.encoding)},
+		{dataPageDefinitionLevelEncoding, ti32(encodingRLE)},
+		{dataPageRepetitionLevelEncoding, ti32(encodingRLE)},
+	}
+
+	compressed, uncompressed, err := writePage(w, conf, pageData, append(levels, c.values...), pageHeaderDataPageHeader, header)
+	if err != nil {
+		return nil, 0, err
+	}
+	compressedSize += compressed
+	uncompressedSize += uncompressed
+
+	meta := tfields{
+		{columnMetaType, ti32(physical)},
+		{columnMetaEncodings, encodings},
+		{columnMetaPathInSchema, tlist{tbin(name)}},
+		{columnMetaCodec, ti32(conf.Codec)},
+		{columnMetaNumValues, ti64(len(c.defined))},
+		{columnMetaTotalUncompressedSize, ti64(uncompressedSize)},
+		{columnMetaTotalCompressedSize, ti64(compressedSize)},
+		{columnMetaDataPageOffset, ti64(dataOffset)},
+	}
+
+	if dictionaryOffset >= 0 {
+		meta = append(meta, tfield{columnMetaDictionaryPageOffset, ti64(dictionaryOffset)})
+	}
+
+	return tfields{{columnChunkFileOffset, ti64(start)}, {columnChunkMetaData, meta}}, uncompressedSize, nil
+}
+
+// Write writes the elements referenced by ix of the columns to w as a Parquet file.
+// The rows are split into row groups of conf.RowGroupSize rows, all rows are written to
+// a single row group if it is zero. Each column chunk consists of one data page,
+// preceded by a dictionary page for enum columns.
+func Write(w io.Writer, names []string, columns []column.Column, ix index.Int, conf WriteConfig) error {
+	if conf.RowGroupSize < 0 {
+		return errParquet("invalid row group size: %d", conf.RowGroupSize)
+	}
+
+	schema := tlist{tfields{{schemaName, tbin("schema")}, {schemaNumChildren, ti32(len(columns))}}}
+	physicals := make([]int64, len(columns))
+	for i, col := range columns {
+		element, physical, err := schemaElement(names[i], col)
+		if err != nil {
+			return err
+		}
+		schema = append(schema, element)
+		physicals[i] = physical
+	}
+
+	cw := &countingWriter{w: w}
+	if _, err := cw.Write(magic); err != nil {
+		return err
+	}
+
+	groupSize := conf.RowGroupSize
+	if groupSize == 0 {
+		groupSize = len(ix)
+	}
+
+	var rowGroups tlist
+	for start := 0; start < len(ix); start += groupSize {
+		end := start + groupSize
+		if end > len(ix) {
+			end = len(ix)
+		}
+
+		var chunks tlist
+		var totalSize int64
+		for i, col := range columns {
+			c, err := encodeChunk(col, ix[start:end])
+			if err != nil {
+				return err
+			}
+
+			meta, size, err := writeChunk(cw, conf, names[i], physicals[i], c)
+			if err != nil {
+				return err
+			}
+			chunks = append(chunks, meta)
+			totalSize += size
+		}
+
+		rowGroups = append(rowGroups, tfields{
+			{rowGroupColumns, chunks},
+			{rowGroupTotalByteSize, ti64(totalSize)},
+			{rowGroupNumRows, ti64(end - start)},
+		})
+	}
+
+	fileMeta := encodeStruct(nil, tfields{
+		{fileMetaVersion, ti32(1)},
+		{fileMetaSchema, schema},
+		{fileMetaNumRows, ti64(len(ix))},
+		{fileMetaRowGroups, rowGroups},
+		{fileMetaCreatedBy, tbin("qframe")},
+	})
+
+	footer := make([]byte, 4)
+	binary.LittleEndian.PutUint32(footer, uint32(len(fileMeta)))
+	for _, b := range [][]byte{fileMeta, footer, magic} {
+		if _, err := cw.Write(b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -16,6 +16,9 @@ type CoerceFunc func(c *Column) func(t interface{}) error
 // BOOL as INT types natively.
 func Int64ToBool(c *Column) func(t interface{}) error {
 	return func(t interface{}) error {
+		if t == nil {
+			return c.Null()
+		}
 		v, ok := t.(int64)
 		if !ok {
 			return errors.New(
@@ -3,8 +3,13 @@ package sql
 import (
 	"math"
 	"reflect"
+	"time"
 
+	"github.com/tobgu/qframe/internal/bcolumn"
+	"github.com/tobgu/qframe/internal/bitmap"
+	"github.com/tobgu/qframe/internal/icolumn"
 	"github.com/tobgu/qframe/internal/math/float"
+	"github.com/tobgu/qframe/internal/tcolumn"
 
 	"github.com/tobgu/qframe/errors"
 )
@@ -15,6 +20,9 @@ import (
 type Column struct {
 	kind  reflect.Kind
 	nulls int
+	// positions of NULL values in
+	// int, bool and time columns
+	nullPos []uint32
 	// pointer to the data slice which
 	// contains the inferred data type
 	ptr  interface{}
@@ -23,7 +31,10 @@ type Column struct {
 		Floats  []float64
 		Bools   []bool
 		Strings []*string
+		Times   []int64
 	}
+	// location of time data
+	loc       *time.Location
 	coerce    func(t interface{}) error
 	precision int
 }
@@ -45,6 +56,15 @@ func (c *Column) Null() error {
 		c.data.Floats = append(c.data.Floats, math.NaN())
 	case reflect.String:
 		c.data.Strings = append(c.data.Strings, nil)
+	case reflect.Int:
+		c.nullPos = append(c.nullPos, uint32(len(c.data.Ints)))
+		c.data.Ints = append(c.data.Ints, 0)
+	case reflect.Bool:
+		c.nullPos = append(c.nullPos, uint32(len(c.data.Bools)))
+		c.data.Bools = append(c.data.Bools, false)
+	case reflect.Struct:
+		c.nullPos = append(c.nullPos, uint32(len(c.data.Times)))
+		c.data.Times = append(c.data.Times, 0)
 	default:
 		return errors.New("Column Null", "non-nullable type: %s", c.kind)
 	}
@@ -56,6 +76,11 @@ func (c *Column) Int(i int) {
 	if c.ptr == nil {
 		c.kind = reflect.Int
 		c.ptr = &c.data.Ints
+		// add any NULL ints previously scanned
+		for ; c.nulls > 0; c.nulls-- {
+			c.nullPos = append(c.nullPos, uint32(len(c.data.Ints)))
+			c.data.Ints = append(c.data.Ints, 0)
+		}
 	}
 	c.data.Ints = append(c.data.Ints, i)
 }
@@ -100,10 +125,30 @@ func (c *Column) Bool(b bool) {
 	if c.ptr == nil {
 		c.kind = reflect.Bool
 		c.ptr = &c.data.Bools
+		// add any NULL bools previously scanned
+		for ; c.nulls > 0; c.nulls-- {
+			c.nullPos = append(c.nullPos, uint32(len(c.data.Bools)))
+			c.data.Bools = append(c.data.Bools, false)
+		}
 	}
 	c.data.Bools = append(c.data.Bools, b)
 }
 
+// Time adds a new time to the underlying data slice
+func (c *Column) Time(t time.Time) {
+	if c.ptr == nil {
+		c.kind = reflect.Struct
+		c.ptr = &c.data.Times
+		c.loc = t.Location()
+		// add any NULL times previously scanned
+		for ; c.nulls > 0; c.nulls-- {
+			c.nullPos = append(c.nullPos, uint32(len(c.data.Times)))
+			c.data.Times = append(c.data.Times, 0)
+		}
+	}
+	c.data.Times = append(c.data.Times, t.UnixNano())
+}
+
 // Scan implements the sql.Scanner interface
 func (c *Column) Scan(t interface{}) error {
 	if c.coerce != nil {
@@ -120,6 +165,8 @@ func (c *Column) Scan(t interface{}) error {
 		c.String(string(v))
 	case float64:
 		c.Float(v)
+	case time.Time:
+		c.Time(v)
 	case nil:
 		err := c.Null()
 		if err != nil {
@@ -132,11 +179,37 @@ func (c *Column) Scan(t interface{}) error {
 	return nil
 }
 
-// Data returns the underlying data slice
+// Data returns the underlying data slice, int and
+// bool data containing NULL values are returned as
+// columns keeping track of the NULL values. Time
+// data is always returned as a column.
 func (c *Column) Data() interface{} {
 	if c.ptr == nil {
 		return nil
 	}
+	if c.kind == reflect.Struct {
+		var valid bitmap.Bitmap
+		if len(c.nullPos) > 0 {
+			valid = c.validity(len(c.data.Times))
+		}
+		return tcolumn.NewNanos(c.data.Times, valid, c.loc)
+	}
+	if len(c.nullPos) > 0 {
+		switch c.kind {
+		case reflect.Int:
+			return icolumn.NewNullable(c.data.Ints, c.validity(len(c.data.Ints)))
+		case reflect.Bool:
+			return bcolumn.NewNullable(c.data.Bools, c.validity(len(c.data.Bools)))
+		}
+	}
 	// *[]<T> -> []<T>
 	return reflect.ValueOf(c.ptr).Elem().Interface()
 }
+
+func (c *Column) validity(size int) bitmap.Bitmap {
+	valid := bitmap.New(size, true)
+	for _, pos := range c.nullPos {
+		valid.SetNull(pos)
+	}
+	return valid
+}
@@ -3,6 +3,9 @@ package sql
 import (
 	"math"
 	"testing"
+
+	"github.com/tobgu/qframe/internal/bcolumn"
+	"github.com/tobgu/qframe/internal/icolumn"
 )
 
 func assertEqual(t *testing.T, expected, actual interface{}) {
@@ -67,3 +70,26 @@ func BenchmarkColumn(b *testing.B) {
 		col.Scan(1.0)
 	}
 }
+
+func TestColumnNullableInt(t *testing.T) {
+	col := &Column{}
+	col.Scan(nil)
+	col.Scan(int64(1))
+	col.Scan(nil)
+	col.Scan(int64(3))
+	data := col.Data().(icolumn.Column)
+	assertEqual(t, 4, data.Len())
+	for i, expected := range []string{"null", "1", "null", "3"} {
+		assertEqual(t, expected, data.StringAt(uint32(i), "null"))
+	}
+}
+
+func TestColumnNullableBoolCoercion(t *testing.T) {
+	col := &Column{}
+	col.coerce = Int64ToBool(col)
+	col.Scan(int64(1))
+	col.Scan(nil)
+	data := col.Data().(bcolumn.Column)
+	assertEqual(t, "true", data.StringAt(0, "null"))
+	assertEqual(t, "null", data.StringAt(1, "null"))
+}
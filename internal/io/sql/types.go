@@ -12,6 +12,7 @@ import (
 	"github.com/tobgu/qframe/internal/icolumn"
 	"github.com/tobgu/qframe/internal/index"
 	"github.com/tobgu/qframe/internal/scolumn"
+	"github.com/tobgu/qframe/internal/tcolumn"
 )
 
 type SQLConfig struct {
@@ -37,6 +38,9 @@ type SQLConfig struct {
 	// Precision specifies how much precision float values
 	// should have. 0 has no effect.
 	Precision int
+	// Rename is a map of column names to new column
+	// names applied to the result of a query.
+	Rename map[string]string
 }
 
 type ArgBuilder func(ix index.Int, i int) interface{}
@@ -45,11 +49,19 @@ func NewArgBuilder(col column.Column) (ArgBuilder, error) {
 	switch c := col.(type) {
 	case bcolumn.Column:
 		return func(ix index.Int, i int) interface{} {
-			return c.View(ix).ItemAt(i)
+			view := c.View(ix)
+			if view.IsNull(i) {
+				return nil
+			}
+			return view.ItemAt(i)
 		}, nil
 	case icolumn.Column:
 		return func(ix index.Int, i int) interface{} {
-			return c.View(ix).ItemAt(i)
+			view := c.View(ix)
+			if view.IsNull(i) {
+				return nil
+			}
+			return view.ItemAt(i)
 		}, nil
 	case fcolumn.Column:
 		return func(ix index.Int, i int) interface{} {
@@ -63,6 +75,14 @@ func NewArgBuilder(col column.Column) (ArgBuilder, error) {
 		return func(ix index.Int, i int) interface{} {
 			return c.View(ix).ItemAt(i)
 		}, nil
+	case tcolumn.Column:
+		return func(ix index.Int, i int) interface{} {
+			view := c.View(ix)
+			if view.IsNull(i) {
+				return nil
+			}
+			return view.ItemAt(i)
+		}, nil
 	}
 	return nil, errors.New("NewArgBuilder", fmt.Sprintf("bad column type: %s", reflect.TypeOf(col).Name()))
 }
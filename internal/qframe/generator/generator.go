@@ -71,6 +71,7 @@ func GenerateQFrame() (*bytes.Buffer, error) {
 		view("Bool", "bcolumn"),
 		view("String", "scolumn"),
 		view("Enum", "ecolumn"),
+		view("Time", "tcolumn"),
 	}, []string{
 		"github.com/tobgu/qframe/errors",
 		"github.com/tobgu/qframe/internal/icolumn",
@@ -78,5 +79,6 @@ func GenerateQFrame() (*bytes.Buffer, error) {
 		"github.com/tobgu/qframe/internal/bcolumn",
 		"github.com/tobgu/qframe/internal/scolumn",
 		"github.com/tobgu/qframe/internal/ecolumn",
+		"github.com/tobgu/qframe/internal/tcolumn",
 	})
 }
@@ -0,0 +1,74 @@
+package scolumn
+
+// Unless stated otherwise the aggregations below ignore null values.
+// Null is returned if there are no non null values to aggregate.
+
+var aggregations = map[string]interface{}{
+	"count":          count,
+	"count_non_null": countNonNull,
+	"first":          first,
+	"last":           last,
+	"max":            max,
+	"min":            min,
+	"nunique":        nunique,
+}
+
+func count(values []*string) int {
+	return len(values)
+}
+
+func countNonNull(values []*string) int {
+	result := 0
+	for _, v := range values {
+		if v != nil {
+			result++
+		}
+	}
+	return result
+}
+
+// first returns the first value, null or not.
+func first(values []*string) *string {
+	if len(values) == 0 {
+		return nil
+	}
+	return values[0]
+}
+
+// last returns the last value, null or not.
+func last(values []*string) *string {
+	if len(values) == 0 {
+		return nil
+	}
+	return values[len(values)-1]
+}
+
+func min(values []*string) *string {
+	var result *string
+	for _, v := range values {
+		if v != nil && (result == nil || *v < *result) {
+			result = v
+		}
+	}
+	return result
+}
+
+func max(values []*string) *string {
+	var result *string
+	for _, v := range values {
+		if v != nil && (result == nil || *v > *result) {
+			result = v
+		}
+	}
+	return result
+}
+
+func nunique(values []*string) int {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		if v != nil {
+			set[*v] = struct{}{}
+		}
+	}
+	return len(set)
+}
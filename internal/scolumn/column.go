@@ -280,6 +280,28 @@ func NewConst(val *string, count int) Column {
 	return NewBytes(pointers, data)
 }
 
+// Concat returns a new column holding the elements at positions ixs[i] in cols[i] for all columns, in order.
+func Concat(cols []Column, ixs []index.Int) Column {
+	size := 0
+	for _, ix := range ixs {
+		size += len(ix)
+	}
+
+	data := make([]byte, 0, size)
+	pointers := make([]qfstrings.Pointer, 0, size)
+	for i, c := range cols {
+		for _, j := range ixs[i] {
+			p := c.pointers[j]
+			pointers = append(pointers, qfstrings.NewPointer(len(data), p.Len(), p.IsNull()))
+			if !p.IsNull() {
+				data = append(data, c.data[p.Offset():p.Offset()+p.Len()]...)
+			}
+		}
+	}
+
+	return NewBytes(pointers, data)
+}
+
 func (c Column) stringAt(i uint32) (string, bool) {
 	p := c.pointers[i]
 	if p.IsNull() {
@@ -325,6 +347,29 @@ func (c Column) Subset(index index.Int) column.Column {
 	return c.subset(index)
 }
 
+// NullableSubset works like Subset with the exception that positions in
+// index equal to nullPos result in null values.
+func (c Column) NullableSubset(index index.Int, nullPos uint32) column.Column {
+	data := make([]byte, 0, len(index))
+	pointers := make([]qfstrings.Pointer, len(index))
+	offset := 0
+	for i, ix := range index {
+		if ix == nullPos {
+			pointers[i] = qfstrings.NewPointer(offset, 0, true)
+			continue
+		}
+
+		p := c.pointers[ix]
+		pointers[i] = qfstrings.NewPointer(offset, p.Len(), p.IsNull())
+		if !p.IsNull() {
+			data = append(data, c.data[p.Offset():p.Offset()+p.Len()]...)
+			offset += p.Len()
+		}
+	}
+
+	return Column{data: data, pointers: pointers}
+}
+
 func (c Column) Comparable(reverse, equalNull bool) column.Comparable {
 	result := Comparable{column: c, ltValue: column.LessThan, gtValue: column.GreaterThan, equalNullValue: column.NotEqual}
 	if reverse {
@@ -342,17 +387,47 @@ func (c Column) String() string {
 	return fmt.Sprintf("%v", c.data)
 }
 
-func (c Column) Aggregate(indices []index.Int, fn interface{}) (column.Column, error) {
+// Aggregate applies fn to the strings of each index in indices. The result
+// may be a column of a different type than the current column.
+func (c Column) Aggregate(indices []index.Int, fn interface{}) (interface{}, error) {
+	if name, ok := fn.(string); ok {
+		fn, ok = aggregations[name]
+		if !ok {
+			return nil, errors.New("string aggregate", "aggregation function %s is not defined for string column", name)
+		}
+	}
+
+	return AggregateStrings(indices, fn, c.stringSlice)
+}
+
+// AggregateStrings applies fn to the string slices produced by stringSlice for each index in indices.
+// It is also used for aggregations over enum columns.
+func AggregateStrings(indices []index.Int, fn interface{}, stringSlice func(index.Int) []*string) (interface{}, error) {
 	switch t := fn.(type) {
-	case string:
-		// There are currently no build in aggregations for strings
-		return nil, errors.New("enum aggregate", "aggregation function %c is not defined for string column", fn)
 	case func([]*string) *string:
-		data := make([]*string, 0, len(indices))
+		result := make([]*string, 0, len(indices))
 		for _, ix := range indices {
-			data = append(data, t(c.stringSlice(ix)))
+			result = append(result, t(stringSlice(ix)))
 		}
-		return New(data), nil
+		return result, nil
+	case func([]*string) int:
+		result := make([]int, 0, len(indices))
+		for _, ix := range indices {
+			result = append(result, t(stringSlice(ix)))
+		}
+		return result, nil
+	case func([]*string) float64:
+		result := make([]float64, 0, len(indices))
+		for _, ix := range indices {
+			result = append(result, t(stringSlice(ix)))
+		}
+		return result, nil
+	case func([]*string) bool:
+		result := make([]bool, 0, len(indices))
+		for _, ix := range indices {
+			result = append(result, t(stringSlice(ix)))
+		}
+		return result, nil
 	default:
 		return nil, errors.New("string aggregate", "invalid aggregation function type: %v", t)
 	}
@@ -426,6 +501,12 @@ func (c Column) View(ix index.Int) View {
 	return View{column: c, index: ix}
 }
 
+// Raw returns the pointers and the byte blob backing the column.
+// Neither may be modified.
+func (c Column) Raw() ([]qfstrings.Pointer, []byte) {
+	return c.pointers, c.data
+}
+
 func (c Column) FunctionType() types.FunctionType {
 	return types.FunctionTypeString
 }
@@ -17,5 +17,12 @@ func Doc() string {
 		"  like\n" +
 
 		"\n Built in aggregations\n" +
+		"  count\n" +
+		"  count_non_null\n" +
+		"  first\n" +
+		"  last\n" +
+		"  max\n" +
+		"  min\n" +
+		"  nunique\n" +
 		"\n"
 }
@@ -73,5 +73,5 @@ func GenerateDoc() (*bytes.Buffer, error) {
 	return template.GenerateDocs(
 		"scolumn",
 		maps.StringKeys(filterFuncs0, filterFuncs1, filterFuncs2, multiInputFilterFuncs),
-		maps.StringKeys())
+		maps.StringKeys(aggregations))
 }
@@ -0,0 +1,56 @@
+package tcolumn
+
+import "time"
+
+// The aggregations below are never passed null values. See Column.Aggregate for
+// the built in functions count, first and last which are handled separately.
+
+var aggregations = map[string]interface{}{
+	"count":          count,
+	"count_non_null": count,
+	"first":          first,
+	"last":           last,
+	"max":            max,
+	"min":            min,
+	"nunique":        nunique,
+}
+
+func count(values []time.Time) int {
+	return len(values)
+}
+
+func first(values []time.Time) time.Time {
+	return values[0]
+}
+
+func last(values []time.Time) time.Time {
+	return values[len(values)-1]
+}
+
+func min(values []time.Time) time.Time {
+	result := values[0]
+	for _, v := range values[1:] {
+		if v.Before(result) {
+			result = v
+		}
+	}
+	return result
+}
+
+func max(values []time.Time) time.Time {
+	result := values[0]
+	for _, v := range values[1:] {
+		if v.After(result) {
+			result = v
+		}
+	}
+	return result
+}
+
+func nunique(values []time.Time) int {
+	set := make(map[int64]struct{}, len(values))
+	for _, v := range values {
+		set[v.UnixNano()] = struct{}{}
+	}
+	return len(set)
+}
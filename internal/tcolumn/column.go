@@ -0,0 +1,606 @@
+package tcolumn
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+	"unsafe"
+
+	"github.com/tobgu/qframe/errors"
+	"github.com/tobgu/qframe/internal/bitmap"
+	"github.com/tobgu/qframe/internal/column"
+	"github.com/tobgu/qframe/internal/hash"
+	"github.com/tobgu/qframe/internal/index"
+	"github.com/tobgu/qframe/types"
+)
+
+// Column holds time stamps as nanoseconds since the Unix epoch. All values
+// in the column share the same location (time zone) which is used when
+// presenting the values and when passing them to functions.
+type Column struct {
+	data []int64
+
+	// valid tracks null values, nil if the column contains no nulls.
+	valid bitmap.Bitmap
+	loc   *time.Location
+}
+
+func location(loc *time.Location) *time.Location {
+	if loc == nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// New creates a new column from d. The location of the first value is used for the column.
+func New(d []time.Time) Column {
+	var loc *time.Location
+	data := make([]int64, len(d))
+	for i, t := range d {
+		if i == 0 {
+			loc = t.Location()
+		}
+		data[i] = t.UnixNano()
+	}
+
+	return Column{data: data, loc: location(loc)}
+}
+
+// NewPointers creates a new column from d where nil pointers are null. The location
+// of the first non null value is used for the column.
+func NewPointers(d []*time.Time) Column {
+	var loc *time.Location
+	var valid bitmap.Bitmap
+	data := make([]int64, len(d))
+	for i, t := range d {
+		if t != nil {
+			if loc == nil {
+				loc = t.Location()
+			}
+			data[i] = t.UnixNano()
+			continue
+		}
+
+		if valid == nil {
+			valid = bitmap.New(len(d), true)
+		}
+		valid.SetNull(uint32(i))
+	}
+
+	return Column{data: data, valid: valid, loc: location(loc)}
+}
+
+// NewNanos creates a new column from nanoseconds since the Unix epoch. Positions that are null
+// in valid are null in the column. A nil valid means that the column contains no nulls.
+func NewNanos(d []int64, valid bitmap.Bitmap, loc *time.Location) Column {
+	return Column{data: d, valid: valid, loc: location(loc)}
+}
+
+// NewNull creates a new column containing count null values.
+func NewNull(count int, loc *time.Location) Column {
+	return Column{data: make([]int64, count), valid: bitmap.New(count, false), loc: location(loc)}
+}
+
+// Concat returns a new column holding the elements at positions ixs[i] in cols[i] for all columns, in order.
+// The location of the first column is used for the result.
+func Concat(cols []Column, ixs []index.Int) Column {
+	size := 0
+	for _, ix := range ixs {
+		size += len(ix)
+	}
+
+	var valid bitmap.Bitmap
+	for _, c := range cols {
+		if c.valid != nil {
+			valid = bitmap.New(size, true)
+			break
+		}
+	}
+
+	data := make([]int64, 0, size)
+	for i, c := range cols {
+		for _, j := range ixs[i] {
+			if c.isNull(j) {
+				valid.SetNull(uint32(len(data)))
+			}
+			data = append(data, c.data[j])
+		}
+	}
+
+	var loc *time.Location
+	if len(cols) > 0 {
+		loc = cols[0].loc
+	}
+
+	return Column{data: data, valid: valid, loc: location(loc)}
+}
+
+// Location returns the location of the values in the column.
+func (c Column) Location() *time.Location {
+	return c.loc
+}
+
+// In returns a column holding the same points in time as c presented in loc.
+func (c Column) In(loc *time.Location) Column {
+	return Column{data: c.data, valid: c.valid, loc: location(loc)}
+}
+
+func (c Column) isNull(i uint32) bool {
+	return c.valid.IsNull(i)
+}
+
+func (c Column) timeAt(i uint32) time.Time {
+	return time.Unix(0, c.data[i]).In(c.loc)
+}
+
+func (c Column) newValid(size int) bitmap.Bitmap {
+	if c.valid == nil {
+		return nil
+	}
+	return bitmap.New(size, true)
+}
+
+func (c Column) DataType() types.DataType {
+	return types.Time
+}
+
+func (c Column) FunctionType() types.FunctionType {
+	return types.FunctionTypeTime
+}
+
+// StringAt returns the value at position i in ISO-8601 (RFC 3339) format.
+func (c Column) StringAt(i uint32, naRep string) string {
+	if c.isNull(i) {
+		return naRep
+	}
+	return c.timeAt(i).Format(time.RFC3339Nano)
+}
+
+func (c Column) AppendByteStringAt(buf []byte, i uint32) []byte {
+	if c.isNull(i) {
+		return append(buf, "null"...)
+	}
+
+	buf = append(buf, '"')
+	buf = c.timeAt(i).AppendFormat(buf, time.RFC3339Nano)
+	return append(buf, '"')
+}
+
+func (c Column) ByteSize() int {
+	// Slice header + data + validity + location pointer
+	return 2*8 + 8*len(c.data) + c.valid.ByteSize() + 8
+}
+
+func (c Column) Len() int {
+	return len(c.data)
+}
+
+func (c Column) String() string {
+	return fmt.Sprintf("%v", c.data)
+}
+
+func (c Column) Equals(index index.Int, other column.Column, otherIndex index.Int) bool {
+	otherC, ok := other.(Column)
+	if !ok {
+		return false
+	}
+
+	for ix, x := range index {
+		xNull, yNull := c.isNull(x), otherC.isNull(otherIndex[ix])
+		if xNull != yNull || (!xNull && c.data[x] != otherC.data[otherIndex[ix]]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c Column) subset(index index.Int) Column {
+	data, valid := make([]int64, len(index)), c.newValid(len(index))
+	for i, ix := range index {
+		data[i] = c.data[ix]
+		if c.isNull(ix) {
+			valid.SetNull(uint32(i))
+		}
+	}
+
+	return Column{data: data, valid: valid, loc: c.loc}
+}
+
+func (c Column) Subset(index index.Int) column.Column {
+	return c.subset(index)
+}
+
+// NullableSubset works like Subset with the exception that positions in
+// index equal to nullPos result in null values.
+func (c Column) NullableSubset(index index.Int, nullPos uint32) column.Column {
+	data, valid := make([]int64, len(index)), bitmap.New(len(index), true)
+	for i, ix := range index {
+		if ix == nullPos || c.isNull(ix) {
+			valid.SetNull(uint32(i))
+		} else {
+			data[i] = c.data[ix]
+		}
+	}
+
+	return Column{data: data, valid: valid, loc: c.loc}
+}
+
+func (c Column) Comparable(reverse, equalNull bool) column.Comparable {
+	result := Comparable{column: c, ltValue: column.LessThan, gtValue: column.GreaterThan, equalNullValue: column.NotEqual}
+	if reverse {
+		result.ltValue, result.gtValue = result.gtValue, result.ltValue
+	}
+
+	if equalNull {
+		result.equalNullValue = column.Equal
+	}
+
+	return result
+}
+
+type Comparable struct {
+	column         Column
+	ltValue        column.CompareResult
+	gtValue        column.CompareResult
+	equalNullValue column.CompareResult
+}
+
+func (c Comparable) Compare(i, j uint32) column.CompareResult {
+	xNull, yNull := c.column.isNull(i), c.column.isNull(j)
+	if xNull || yNull {
+		if !xNull {
+			return c.gtValue
+		}
+
+		if !yNull {
+			return c.ltValue
+		}
+
+		return c.equalNullValue
+	}
+
+	x, y := c.column.data[i], c.column.data[j]
+	if x < y {
+		return c.ltValue
+	}
+
+	if x > y {
+		return c.gtValue
+	}
+
+	return column.Equal
+}
+
+func (c Comparable) HashBytes(i uint32, buf *hash.Murm32) {
+	if c.column.isNull(i) {
+		if c.equalNullValue == column.NotEqual {
+			// Use a random value here to avoid hash collisions when
+			// we don't consider null to equal null.
+			buf.WriteRand32()
+		} else {
+			buf.WriteByte(0)
+		}
+		return
+	}
+
+	x := &c.column.data[i]
+	b := (*[8]byte)(unsafe.Pointer(x))[:]
+	buf.Write(b)
+}
+
+func timeSlice(ss []interface{}) ([]time.Time, bool) {
+	result := make([]time.Time, len(ss))
+	for i, s := range ss {
+		t, ok := s.(time.Time)
+		if !ok {
+			return nil, false
+		}
+		result[i] = t
+	}
+	return result, true
+}
+
+func (c Column) filterBuiltIn(index index.Int, comparator string, comparatee interface{}, bIndex index.Bool) error {
+	if s, ok := comparatee.([]interface{}); ok {
+		if ts, ok := timeSlice(s); ok {
+			comparatee = ts
+		}
+	}
+
+	switch t := comparatee.(type) {
+	case time.Time:
+		filterFn, ok := filterFuncs1[comparator]
+		if !ok {
+			return errors.New("filter time", "unknown filter operator %v for single value argument", comparator)
+		}
+		filterFn(index, c, t.UnixNano(), bIndex)
+	case []time.Time:
+		filterFn, ok := multiInputFilterFuncs[comparator]
+		if !ok {
+			return errors.New("filter time", "unknown filter operator %v for multi value argument", comparator)
+		}
+
+		set := make(timeSet, len(t))
+		for _, x := range t {
+			set[x.UnixNano()] = struct{}{}
+		}
+		filterFn(index, c, set, bIndex)
+	case Column:
+		filterFn, ok := filterFuncs2[comparator]
+		if !ok {
+			return errors.New("filter time", "unknown filter operator %v for column - column comparison", comparator)
+		}
+		filterFn(index, c, t, bIndex)
+	case nil:
+		filterFn, ok := filterFuncs0[comparator]
+		if !ok {
+			return errors.New("filter time", "unknown filter operator %v for zero argument", comparator)
+		}
+		filterFn(index, c, bIndex)
+	default:
+		return errors.New("filter time", "invalid comparison value type %v", reflect.TypeOf(comparatee))
+	}
+
+	return nil
+}
+
+func (c Column) filterCustom1(index index.Int, fn func(time.Time) bool, bIndex index.Bool) {
+	for i, x := range bIndex {
+		if !x && !c.isNull(index[i]) {
+			bIndex[i] = fn(c.timeAt(index[i]))
+		}
+	}
+}
+
+func (c Column) filterCustom2(index index.Int, fn func(time.Time, time.Time) bool, comparatee interface{}, bIndex index.Bool) error {
+	otherC, ok := comparatee.(Column)
+	if !ok {
+		return errors.New("filter time", "expected comparatee to be time column, was %v", reflect.TypeOf(comparatee))
+	}
+
+	for i, x := range bIndex {
+		if !x && !c.isNull(index[i]) && !otherC.isNull(index[i]) {
+			bIndex[i] = fn(c.timeAt(index[i]), otherC.timeAt(index[i]))
+		}
+	}
+
+	return nil
+}
+
+// Filter applies comparator to the values in the column. Null values never match
+// the filter, except for the != comparator for which they always match.
+func (c Column) Filter(index index.Int, comparator interface{}, comparatee interface{}, bIndex index.Bool) error {
+	var err error
+	switch t := comparator.(type) {
+	case string:
+		err = c.filterBuiltIn(index, t, comparatee, bIndex)
+	case func(time.Time) bool:
+		c.filterCustom1(index, t, bIndex)
+	case func(time.Time, time.Time) bool:
+		err = c.filterCustom2(index, t, comparatee, bIndex)
+	default:
+		err = errors.New("filter time", "invalid filter type %v", reflect.TypeOf(comparator))
+	}
+	return err
+}
+
+// Aggregate applies fn to the values of each index in indices. The result
+// may be a column of a different type than the current column.
+//
+// Null values are never passed to fn. Groups without any non null values
+// produce null, except for the built in functions count, count_non_null and
+// nunique. The built in function count counts all values, including null, and
+// first and last return null if the first or last value is null.
+func (c Column) Aggregate(indices []index.Int, fn interface{}) (interface{}, error) {
+	nullable := true
+	if name, ok := fn.(string); ok {
+		switch name {
+		case "count":
+			result := make([]int, len(indices))
+			for i, ix := range indices {
+				result[i] = len(ix)
+			}
+			return result, nil
+		case "first", "last":
+			positions := make(index.Int, len(indices))
+			for i, ix := range indices {
+				if name == "first" {
+					positions[i] = ix[0]
+				} else {
+					positions[i] = ix[len(ix)-1]
+				}
+			}
+			return c.subset(positions), nil
+		}
+
+		nullable = name != "count_non_null" && name != "nunique"
+		fn, ok = aggregations[name]
+		if !ok {
+			return nil, errors.New("time aggregate", "aggregation function %s is not defined for time column", name)
+		}
+	}
+
+	var valid bitmap.Bitmap
+	if nullable {
+		valid = bitmap.New(len(indices), true)
+	}
+
+	var buf []time.Time
+	switch t := fn.(type) {
+	case func([]time.Time) time.Time:
+		result := make([]int64, len(indices))
+		for i, ix := range indices {
+			if values := c.timesWithBuf(ix, &buf); nullable && len(values) == 0 {
+				valid.SetNull(uint32(i))
+			} else {
+				result[i] = t(values).UnixNano()
+			}
+		}
+		return Column{data: result, valid: valid, loc: c.loc}, nil
+	case func([]time.Time) int:
+		result := make([]int, len(indices))
+		for i, ix := range indices {
+			if values := c.timesWithBuf(ix, &buf); nullable && len(values) == 0 {
+				valid.SetNull(uint32(i))
+			} else {
+				result[i] = t(values)
+			}
+		}
+		return nullableData(result, valid), nil
+	case func([]time.Time) float64:
+		result := make([]float64, len(indices))
+		for i, ix := range indices {
+			if values := c.timesWithBuf(ix, &buf); nullable && len(values) == 0 {
+				result[i] = math.NaN()
+			} else {
+				result[i] = t(values)
+			}
+		}
+		return result, nil
+	case func([]time.Time) bool:
+		result := make([]bool, len(indices))
+		for i, ix := range indices {
+			if values := c.timesWithBuf(ix, &buf); nullable && len(values) == 0 {
+				valid.SetNull(uint32(i))
+			} else {
+				result[i] = t(values)
+			}
+		}
+		return nullableData(result, valid), nil
+	case func([]time.Time) *string:
+		result := make([]*string, len(indices))
+		for i, ix := range indices {
+			if values := c.timesWithBuf(ix, &buf); !nullable || len(values) > 0 {
+				result[i] = t(values)
+			}
+		}
+		return result, nil
+	default:
+		return nil, errors.New("time aggregate", "invalid aggregation function type: %v", t)
+	}
+}
+
+// timesWithBuf returns the non null values at the positions in index, reusing buf if possible.
+func (c Column) timesWithBuf(index index.Int, buf *[]time.Time) []time.Time {
+	if cap(*buf) < len(index) {
+		*buf = make([]time.Time, 0, len(index))
+	}
+
+	result := (*buf)[:0]
+	for _, ix := range index {
+		if !c.isNull(ix) {
+			result = append(result, c.timeAt(ix))
+		}
+	}
+
+	*buf = result
+	return result
+}
+
+// nullableData wraps int and bool data in a column.NullableData if valid is non nil.
+func nullableData(data interface{}, valid bitmap.Bitmap) interface{} {
+	if valid == nil {
+		return data
+	}
+	return column.NullableData{Data: data, Valid: valid}
+}
+
+// Apply1 applies a single argument function. The result may be a column
+// of a different type than the current column. fn is not applied
+// to null values, they produce null in the result.
+func (c Column) Apply1(fn interface{}, ix index.Int) (interface{}, error) {
+	switch t := fn.(type) {
+	case func(time.Time) int:
+		result, valid := make([]int, len(c.data)), c.newValid(len(c.data))
+		for _, i := range ix {
+			if c.isNull(i) {
+				valid.SetNull(i)
+			} else {
+				result[i] = t(c.timeAt(i))
+			}
+		}
+		return nullableData(result, valid), nil
+	case func(time.Time) float64:
+		result := make([]float64, len(c.data))
+		for _, i := range ix {
+			if c.isNull(i) {
+				result[i] = math.NaN()
+			} else {
+				result[i] = t(c.timeAt(i))
+			}
+		}
+		return result, nil
+	case func(time.Time) bool:
+		result, valid := make([]bool, len(c.data)), c.newValid(len(c.data))
+		for _, i := range ix {
+			if c.isNull(i) {
+				valid.SetNull(i)
+			} else {
+				result[i] = t(c.timeAt(i))
+			}
+		}
+		return nullableData(result, valid), nil
+	case func(time.Time) *string:
+		result := make([]*string, len(c.data))
+		for _, i := range ix {
+			if !c.isNull(i) {
+				result[i] = t(c.timeAt(i))
+			}
+		}
+		return result, nil
+	case func(time.Time) time.Time:
+		result, valid := make([]int64, len(c.data)), c.newValid(len(c.data))
+		for _, i := range ix {
+			if c.isNull(i) {
+				valid.SetNull(i)
+			} else {
+				result[i] = t(c.timeAt(i)).UnixNano()
+			}
+		}
+		return Column{data: result, valid: valid, loc: c.loc}, nil
+	default:
+		return nil, errors.New("time.apply1", "cannot apply type %#v to column", fn)
+	}
+}
+
+// Apply2 applies a double argument function to two time columns.
+// The result is null where any of the columns is null.
+func (c Column) Apply2(fn interface{}, s2 column.Column, ix index.Int) (column.Column, error) {
+	s2T, ok := s2.(Column)
+	if !ok {
+		return nil, errors.New("time.apply2", "invalid column type %v", reflect.TypeOf(s2))
+	}
+
+	t, ok := fn.(func(time.Time, time.Time) time.Time)
+	if !ok {
+		return nil, errors.New("time.apply2", "cannot apply type %#v to column", fn)
+	}
+
+	var valid bitmap.Bitmap
+	if c.valid != nil || s2T.valid != nil {
+		valid = bitmap.New(len(c.data), true)
+	}
+
+	result := make([]int64, len(c.data))
+	for _, i := range ix {
+		if c.isNull(i) || s2T.isNull(i) {
+			valid.SetNull(i)
+		} else {
+			result[i] = t(c.timeAt(i), s2T.timeAt(i)).UnixNano()
+		}
+	}
+
+	return Column{data: result, valid: valid, loc: c.loc}, nil
+}
+
+func (c Column) View(ix index.Int) View {
+	return View{column: c, index: ix}
+}
+
+// Raw returns the nanoseconds since the Unix epoch and the validity bitmap
+// backing the column. Neither may be modified.
+func (c Column) Raw() ([]int64, bitmap.Bitmap) {
+	return c.data, c.valid
+}
@@ -0,0 +1,26 @@
+package tcolumn
+
+// Code generated from template/... DO NOT EDIT
+
+func Doc() string {
+	return "\n Built in filters\n" +
+		"  !=\n" +
+		"  <\n" +
+		"  <=\n" +
+		"  =\n" +
+		"  >\n" +
+		"  >=\n" +
+		"  in\n" +
+		"  isnotnull\n" +
+		"  isnull\n" +
+
+		"\n Built in aggregations\n" +
+		"  count\n" +
+		"  count_non_null\n" +
+		"  first\n" +
+		"  last\n" +
+		"  max\n" +
+		"  min\n" +
+		"  nunique\n" +
+		"\n"
+}
@@ -0,0 +1,97 @@
+package tcolumn
+
+import (
+	"github.com/tobgu/qframe/internal/index"
+)
+
+// Code generated from template/... DO NOT EDIT
+
+func lt(index index.Int, c Column, comp int64, bIndex index.Bool) {
+	for i, x := range bIndex {
+		if !x {
+			pos := index[i]
+			bIndex[i] = !c.isNull(pos) && c.data[pos] < comp
+		}
+	}
+}
+
+func lte(index index.Int, c Column, comp int64, bIndex index.Bool) {
+	for i, x := range bIndex {
+		if !x {
+			pos := index[i]
+			bIndex[i] = !c.isNull(pos) && c.data[pos] <= comp
+		}
+	}
+}
+
+func gt(index index.Int, c Column, comp int64, bIndex index.Bool) {
+	for i, x := range bIndex {
+		if !x {
+			pos := index[i]
+			bIndex[i] = !c.isNull(pos) && c.data[pos] > comp
+		}
+	}
+}
+
+func gte(index index.Int, c Column, comp int64, bIndex index.Bool) {
+	for i, x := range bIndex {
+		if !x {
+			pos := index[i]
+			bIndex[i] = !c.isNull(pos) && c.data[pos] >= comp
+		}
+	}
+}
+
+func eq(index index.Int, c Column, comp int64, bIndex index.Bool) {
+	for i, x := range bIndex {
+		if !x {
+			pos := index[i]
+			bIndex[i] = !c.isNull(pos) && c.data[pos] == comp
+		}
+	}
+}
+
+func lt2(index index.Int, col, col2 Column, bIndex index.Bool) {
+	for i, x := range bIndex {
+		if !x {
+			pos := index[i]
+			bIndex[i] = !col.isNull(pos) && !col2.isNull(pos) && col.data[pos] < col2.data[pos]
+		}
+	}
+}
+
+func lte2(index index.Int, col, col2 Column, bIndex index.Bool) {
+	for i, x := range bIndex {
+		if !x {
+			pos := index[i]
+			bIndex[i] = !col.isNull(pos) && !col2.isNull(pos) && col.data[pos] <= col2.data[pos]
+		}
+	}
+}
+
+func gt2(index index.Int, col, col2 Column, bIndex index.Bool) {
+	for i, x := range bIndex {
+		if !x {
+			pos := index[i]
+			bIndex[i] = !col.isNull(pos) && !col2.isNull(pos) && col.data[pos] > col2.data[pos]
+		}
+	}
+}
+
+func gte2(index index.Int, col, col2 Column, bIndex index.Bool) {
+	for i, x := range bIndex {
+		if !x {
+			pos := index[i]
+			bIndex[i] = !col.isNull(pos) && !col2.isNull(pos) && col.data[pos] >= col2.data[pos]
+		}
+	}
+}
+
+func eq2(index index.Int, col, col2 Column, bIndex index.Bool) {
+	for i, x := range bIndex {
+		if !x {
+			pos := index[i]
+			bIndex[i] = !col.isNull(pos) && !col2.isNull(pos) && col.data[pos] == col2.data[pos]
+		}
+	}
+}
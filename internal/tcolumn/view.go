@@ -0,0 +1,43 @@
+package tcolumn
+
+import (
+	"time"
+
+	"github.com/tobgu/qframe/internal/index"
+)
+
+// View is a view into a column that allows access to individual elements by index.
+type View struct {
+	column Column
+	index  index.Int
+}
+
+// ItemAt returns the value at position i. The zero time is returned for null values.
+func (v View) ItemAt(i int) time.Time {
+	if v.column.isNull(v.index[i]) {
+		return time.Time{}
+	}
+	return v.column.timeAt(v.index[i])
+}
+
+// IsNull returns true if the value at position i is null.
+func (v View) IsNull(i int) bool {
+	return v.column.isNull(v.index[i])
+}
+
+// Len returns the column length.
+func (v View) Len() int {
+	return len(v.index)
+}
+
+// Slice returns a slice containing a copy of the column data. Null values are nil.
+func (v View) Slice() []*time.Time {
+	result := make([]*time.Time, v.Len())
+	for i := range v.index {
+		if !v.IsNull(i) {
+			t := v.ItemAt(i)
+			result[i] = &t
+		}
+	}
+	return result
+}
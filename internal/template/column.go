@@ -6,7 +6,6 @@ import (
 	"fmt"
 
 	"github.com/mauricelam/genny/generic"
-	"github.com/tobgu/qframe/errors"
 	"github.com/tobgu/qframe/internal/column"
 	"github.com/tobgu/qframe/internal/index"
 )
@@ -17,10 +16,6 @@ type genericDataType generic.Number
 //go:generate genny -in=$GOFILE -out=../fcolumn/column_gen.go -pkg=fcolumn gen "genericDataType=float64"
 //go:generate genny -in=$GOFILE -out=../bcolumn/column_gen.go -pkg=bcolumn gen "genericDataType=bool"
 
-type Column struct {
-	data []genericDataType
-}
-
 func New(d []genericDataType) Column {
 	return Column{data: d}
 }
@@ -41,86 +36,10 @@ func (c Column) fnName(name string) string {
 	return fmt.Sprintf("%s.%s", c.DataType(), name)
 }
 
-// Apply single argument function. The result may be a column
-// of a different type than the current column.
-func (c Column) Apply1(fn interface{}, ix index.Int) (interface{}, error) {
-	switch t := fn.(type) {
-	case func(genericDataType) int:
-		result := make([]int, len(c.data))
-		for _, i := range ix {
-			result[i] = t(c.data[i])
-		}
-		return result, nil
-	case func(genericDataType) float64:
-		result := make([]float64, len(c.data))
-		for _, i := range ix {
-			result[i] = t(c.data[i])
-		}
-		return result, nil
-	case func(genericDataType) bool:
-		result := make([]bool, len(c.data))
-		for _, i := range ix {
-			result[i] = t(c.data[i])
-		}
-		return result, nil
-	case func(genericDataType) *string:
-		result := make([]*string, len(c.data))
-		for _, i := range ix {
-			result[i] = t(c.data[i])
-		}
-		return result, nil
-	default:
-		return nil, errors.New(c.fnName("Apply1"), "cannot apply type %#v to column", fn)
-	}
-}
-
-// Apply double argument function to two columns. Both columns must have the
-// same type. The resulting column will have the same type as this column.
-func (c Column) Apply2(fn interface{}, s2 column.Column, ix index.Int) (column.Column, error) {
-	ss2, ok := s2.(Column)
-	if !ok {
-		return Column{}, errors.New(c.fnName("Apply2"), "invalid column type: %s", s2.DataType())
-	}
-
-	t, ok := fn.(func(genericDataType, genericDataType) genericDataType)
-	if !ok {
-		return Column{}, errors.New("Apply2", "invalid function type: %#v", fn)
-	}
-
-	result := make([]genericDataType, len(c.data))
-	for _, i := range ix {
-		result[i] = t(c.data[i], ss2.data[i])
-	}
-
-	return New(result), nil
-}
-
-func (c Column) subset(index index.Int) Column {
-	data := make([]genericDataType, len(index))
-	for i, ix := range index {
-		data[i] = c.data[ix]
-	}
-
-	return Column{data: data}
-}
-
 func (c Column) Subset(index index.Int) column.Column {
 	return c.subset(index)
 }
 
-func (c Column) Comparable(reverse, equalNull bool) column.Comparable {
-	result := Comparable{data: c.data, ltValue: column.LessThan, gtValue: column.GreaterThan, equalNullValue: column.NotEqual}
-	if reverse {
-		result.ltValue, result.gtValue = result.gtValue, result.ltValue
-	}
-
-	if equalNull {
-		result.equalNullValue = column.Equal
-	}
-
-	return result
-}
-
 func (c Column) String() string {
 	return fmt.Sprintf("%v", c.data)
 }
@@ -129,60 +48,24 @@ func (c Column) Len() int {
 	return len(c.data)
 }
 
-func (c Column) Aggregate(indices []index.Int, fn interface{}) (column.Column, error) {
-	var actualFn func([]genericDataType) genericDataType
-	var ok bool
-
-	switch t := fn.(type) {
-	case string:
-		actualFn, ok = aggregations[t]
-		if !ok {
-			return nil, errors.New(c.fnName("Aggregate"), "aggregation function %c is not defined for column", fn)
-		}
-	case func([]genericDataType) genericDataType:
-		actualFn = t
-	default:
-		return nil, errors.New(c.fnName("Aggregate"), "invalid aggregation function type: %v", t)
-	}
-
-	data := make([]genericDataType, 0, len(indices))
-	var buf []genericDataType
-	for _, ix := range indices {
-		subS := c.subsetWithBuf(ix, &buf)
-		data = append(data, actualFn(subS.data))
-	}
-
-	return Column{data: data}, nil
+func count(values []genericDataType) int {
+	return len(values)
 }
 
-func (c Column) subsetWithBuf(index index.Int, buf *[]genericDataType) Column {
-	if cap(*buf) < len(index) {
-		*buf = make([]genericDataType, 0, len(index))
+func first(values []genericDataType) genericDataType {
+	var result genericDataType
+	if len(values) > 0 {
+		result = values[0]
 	}
-
-	data := (*buf)[:0]
-	for _, ix := range index {
-		data = append(data, c.data[ix])
-	}
-
-	return Column{data: data}
-}
-
-func (c Column) View(ix index.Int) View {
-	return View{data: c.data, index: ix}
-}
-
-type Comparable struct {
-	data           []genericDataType
-	ltValue        column.CompareResult
-	gtValue        column.CompareResult
-	equalNullValue column.CompareResult
+	return result
 }
 
-// View is a view into a column that allows access to individual elements by index.
-type View struct {
-	data  []genericDataType
-	index index.Int
+func last(values []genericDataType) genericDataType {
+	var result genericDataType
+	if len(values) > 0 {
+		result = values[len(values)-1]
+	}
+	return result
 }
 
 // ItemAt returns the value at position i.
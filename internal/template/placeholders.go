@@ -12,8 +12,9 @@ import (
 // This file contains definitions for data and functions that need to be added
 // manually for each data type.
 
-// TODO: Probably need a more general aggregation pattern, int -> float (average for example)
-var aggregations = map[string]func([]genericDataType) genericDataType{}
+// Values in aggregations must be functions taking a slice of genericDataType and
+// returning one of int, float64, bool or *string.
+var aggregations = map[string]interface{}{}
 
 var filterFuncs = map[string]func(index.Int, []genericDataType, interface{}, index.Bool) error{}
 
@@ -0,0 +1,419 @@
+package qframe
+
+import (
+	"math"
+	"time"
+
+	"github.com/tobgu/qframe/config/join"
+	"github.com/tobgu/qframe/errors"
+	"github.com/tobgu/qframe/internal/bcolumn"
+	"github.com/tobgu/qframe/internal/bitmap"
+	"github.com/tobgu/qframe/internal/column"
+	"github.com/tobgu/qframe/internal/ecolumn"
+	"github.com/tobgu/qframe/internal/fcolumn"
+	"github.com/tobgu/qframe/internal/grouper"
+	"github.com/tobgu/qframe/internal/icolumn"
+	"github.com/tobgu/qframe/internal/index"
+	"github.com/tobgu/qframe/internal/scolumn"
+	qfstrings "github.com/tobgu/qframe/internal/strings"
+	"github.com/tobgu/qframe/internal/tcolumn"
+	"github.com/tobgu/qframe/types"
+)
+
+// noMatch is used in join indices to mark rows that have no counterpart in the other frame.
+const noMatch = math.MaxUint32
+
+type nullableSubsetter interface {
+	NullableSubset(ix index.Int, nullPos uint32) column.Column
+}
+
+// joinSide holds the key columns of one of the frames taking part in a join.
+type joinSide struct {
+	qf   QFrame
+	keys []grouper.Key
+}
+
+func newJoinSide(qf QFrame, columns []string) joinSide {
+	side := joinSide{qf: qf, keys: make([]grouper.Key, len(columns))}
+	for i, name := range columns {
+		side.keys[i] = grouper.NewKey(qf.columnsByName[name].Column)
+	}
+
+	return side
+}
+
+func (s joinSide) hasNullKey(i uint32) bool {
+	for _, k := range s.keys {
+		if k.IsNull(i) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func compatibleKeyTypes(t1, t2 types.DataType) bool {
+	isStringish := func(t types.DataType) bool { return t == types.String || t == types.Enum }
+	return t1 == t2 || (isStringish(t1) && isStringish(t2))
+}
+
+// checkKeyColumns verifies that the key columns exist in both frames and that they can be compared.
+func checkKeyColumns(operation string, qf, other QFrame, columns []string) error {
+	if len(columns) == 0 {
+		return errors.New(operation, "at least one column must be given as key")
+	}
+
+	if err := qf.checkColumns(operation, columns); err != nil {
+		return err
+	}
+
+	if err := other.checkColumns(operation, columns); err != nil {
+		return err
+	}
+
+	for _, name := range columns {
+		lType, rType := qf.columnsByName[name].DataType(), other.columnsByName[name].DataType()
+		if !compatibleKeyTypes(lType, rType) {
+			return errors.New(operation, `incompatible types for key column "%s": %s, %s`, name, lType, rType)
+		}
+	}
+
+	return nil
+}
+
+func containsNoMatch(ix index.Int) bool {
+	for _, i := range ix {
+		if i == noMatch {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nullableSubset returns a subset of col where all positions in ix equal to noMatch are null.
+func nullableSubset(col column.Column, ix index.Int) (column.Column, error) {
+	if !containsNoMatch(ix) {
+		return col.Subset(ix), nil
+	}
+
+	c, ok := col.(nullableSubsetter)
+	if !ok {
+		return nil, errors.New("nullableSubset", "unsupported column type: %s", col.DataType())
+	}
+
+	return c.NullableSubset(ix, noMatch), nil
+}
+
+type stringView interface {
+	ItemAt(i int) *string
+}
+
+func stringColumnView(c column.Column, ix index.Int) stringView {
+	if ec, ok := c.(ecolumn.Column); ok {
+		return ec.View(ix)
+	}
+	return c.(scolumn.Column).View(ix)
+}
+
+// keySubset returns the key column of a join result. Inner and left joins take the key
+// from the left frame, right joins from the right frame and outer joins combine both.
+func keySubset(how join.Type, left column.Column, leftIx index.Int, right column.Column, rightIx index.Int) (column.Column, error) {
+	switch how {
+	case join.Inner, join.Left:
+		return left.Subset(leftIx), nil
+	case join.Right:
+		return right.Subset(rightIx), nil
+	}
+
+	if left.DataType() == types.Enum && right.DataType() == types.Enum {
+		return coalescedEnumSubset(left.(ecolumn.Column), leftIx, right.(ecolumn.Column), rightIx)
+	}
+
+	if left.DataType() != right.DataType() {
+		// Enum and string, the result is a string column
+		return coalescedStringSubset(left, leftIx, right, rightIx), nil
+	}
+
+	return coalescedSubset(left, leftIx, right, rightIx), nil
+}
+
+// coalescedEnumSubset returns an enum column with values from left where available, otherwise
+// from right. The values of the result are those of left followed by those only found in right.
+func coalescedEnumSubset(left ecolumn.Column, leftIx index.Int, right ecolumn.Column, rightIx index.Int) (column.Column, error) {
+	values := append([]string{}, left.Values()...)
+	leftValues := qfstrings.NewStringSet(left.Values())
+	for _, v := range right.Values() {
+		if !leftValues.Contains(v) {
+			values = append(values, v)
+		}
+	}
+
+	if len(values) == len(left.Values()) && !containsNoMatch(leftIx) {
+		return left.Subset(leftIx), nil
+	}
+
+	f, err := ecolumn.NewFactory(values, len(leftIx))
+	if err != nil {
+		return nil, err
+	}
+
+	lView, rView := left.View(leftIx), right.View(rightIx)
+	for i, pos := range leftIx {
+		var s *string
+		if pos != noMatch {
+			s = lView.ItemAt(i)
+		} else {
+			s = rView.ItemAt(i)
+		}
+
+		if s == nil {
+			f.AppendNil()
+		} else if err := f.AppendString(*s); err != nil {
+			return nil, err
+		}
+	}
+
+	return f.ToColumn(), nil
+}
+
+// coalescedStringSubset returns a string column with values from left where available, otherwise
+// from right. Both columns must be string or enum columns.
+func coalescedStringSubset(left column.Column, leftIx index.Int, right column.Column, rightIx index.Int) column.Column {
+	lView, rView := stringColumnView(left, leftIx), stringColumnView(right, rightIx)
+	data := make([]*string, len(leftIx))
+	for i, pos := range leftIx {
+		if pos != noMatch {
+			data[i] = lView.ItemAt(i)
+		} else {
+			data[i] = rView.ItemAt(i)
+		}
+	}
+	return scolumn.New(data)
+}
+
+// coalescedSubset returns a column with values from left where available, otherwise from right.
+// The columns must be of the same type.
+func coalescedSubset(left column.Column, leftIx index.Int, right column.Column, rightIx index.Int) column.Column {
+	if !containsNoMatch(leftIx) {
+		return left.Subset(leftIx)
+	}
+
+	if !containsNoMatch(rightIx) {
+		return right.Subset(rightIx)
+	}
+
+	switch left.DataType() {
+	case types.Int:
+		lView, rView := left.(icolumn.Column).View(leftIx), right.(icolumn.Column).View(rightIx)
+		data, valid := make([]int, len(leftIx)), bitmap.New(len(leftIx), true)
+		for i, pos := range leftIx {
+			view := lView
+			if pos == noMatch {
+				view = rView
+			}
+
+			if view.IsNull(i) {
+				valid.SetNull(uint32(i))
+			} else {
+				data[i] = view.ItemAt(i)
+			}
+		}
+		return icolumn.NewNullable(data, valid)
+	case types.Float:
+		lView, rView := left.(fcolumn.Column).View(leftIx), right.(fcolumn.Column).View(rightIx)
+		data := make([]float64, len(leftIx))
+		for i, pos := range leftIx {
+			if pos != noMatch {
+				data[i] = lView.ItemAt(i)
+			} else {
+				data[i] = rView.ItemAt(i)
+			}
+		}
+		return fcolumn.New(data)
+	case types.Bool:
+		lView, rView := left.(bcolumn.Column).View(leftIx), right.(bcolumn.Column).View(rightIx)
+		data, valid := make([]bool, len(leftIx)), bitmap.New(len(leftIx), true)
+		for i, pos := range leftIx {
+			view := lView
+			if pos == noMatch {
+				view = rView
+			}
+
+			if view.IsNull(i) {
+				valid.SetNull(uint32(i))
+			} else {
+				data[i] = view.ItemAt(i)
+			}
+		}
+		return bcolumn.NewNullable(data, valid)
+	case types.Time:
+		lCol, rCol := left.(tcolumn.Column), right.(tcolumn.Column)
+		lView, rView := lCol.View(leftIx), rCol.View(rightIx)
+		data := make([]*time.Time, len(leftIx))
+		for i, pos := range leftIx {
+			view := lView
+			if pos == noMatch {
+				view = rView
+			}
+
+			if !view.IsNull(i) {
+				t := view.ItemAt(i)
+				data[i] = &t
+			}
+		}
+		return tcolumn.NewPointers(data).In(lCol.Location())
+	default:
+		return coalescedStringSubset(left, leftIx, right, rightIx)
+	}
+}
+
+func joinColumnName(name string, other QFrame, keys qfstrings.StringSet, suffix string) string {
+	if !keys.Contains(name) && other.Contains(name) {
+		return name + suffix
+	}
+	return name
+}
+
+// Join combines the rows of the QFrame with the rows of other based on equality
+// of the key columns given by join.On. The type of join, inner (default), left,
+// right or full outer, is given by join.How.
+//
+// The resulting frame contains all columns from the QFrame followed by the non key
+// columns of other. Non key columns present in both frames get a suffix added
+// to their names, see join.Suffixes. Null keys never match any other keys.
+//
+// Rows without a match get null values in the columns originating from the other frame.
+// The key columns of inner and left joins are taken from the QFrame, those of right joins
+// from other. Outer joins combine the key columns of both frames. Two enum columns are
+// combined into an enum with the values of the QFrame followed by the values only found in
+// other, an enum combined with a string column results in a string column.
+//
+// The rows are returned in the order of the left frame for inner, left and outer joins,
+// rows only present in the right frame are appended last for outer joins. Right joins
+// return rows in the order of the right frame.
+//
+// Time complexity O(m * (n + k)) where m = number of key columns, n = number of rows in the
+// QFrame, k = number of rows in other. Excluding the size of the result.
+func (qf QFrame) Join(other QFrame, configFns ...join.ConfigFunc) QFrame {
+	if qf.Err != nil {
+		return qf
+	}
+
+	if other.Err != nil {
+		return qf.withErr(errors.Propagate("Join", other.Err))
+	}
+
+	config := join.NewConfig(configFns)
+	if err := checkKeyColumns("Join", qf, other, config.Columns); err != nil {
+		return qf.withErr(err)
+	}
+
+	left, right := newJoinSide(qf, config.Columns), newJoinSide(other, config.Columns)
+	probe, build := left, right
+	if config.How == join.Right {
+		probe, build = right, left
+	}
+
+	// Build a hash table over the rows of one frame and look up all rows of the other frame in it
+	table := grouper.NewJoinTable(build.qf.index, build.keys)
+	keepUnmatchedProbe := config.How != join.Inner
+	keepUnmatchedBuild := config.How == join.Outer
+	var buildMatched index.Bool
+	if keepUnmatchedBuild {
+		buildMatched = index.NewBool(build.qf.columnsByName[config.Columns[0]].Len())
+	}
+
+	probeIx := make(index.Int, 0, probe.qf.Len())
+	buildIx := make(index.Int, 0, probe.qf.Len())
+	for _, i := range probe.qf.index {
+		var matches index.Int
+		if !probe.hasNullKey(i) {
+			matches = table.Lookup(i, probe.keys)
+		}
+
+		for _, j := range matches {
+			probeIx = append(probeIx, i)
+			buildIx = append(buildIx, j)
+			if keepUnmatchedBuild {
+				buildMatched[j] = true
+			}
+		}
+
+		if len(matches) == 0 && keepUnmatchedProbe {
+			probeIx = append(probeIx, i)
+			buildIx = append(buildIx, noMatch)
+		}
+	}
+
+	if keepUnmatchedBuild {
+		for _, j := range build.qf.index {
+			if !buildMatched[j] {
+				probeIx = append(probeIx, noMatch)
+				buildIx = append(buildIx, j)
+			}
+		}
+	}
+
+	leftIx, rightIx := probeIx, buildIx
+	if config.How == join.Right {
+		leftIx, rightIx = buildIx, probeIx
+	}
+
+	return joinResult(qf, leftIx, other, rightIx, config)
+}
+
+func joinResult(left QFrame, leftIx index.Int, right QFrame, rightIx index.Int, config join.Config) QFrame {
+	keys := qfstrings.NewStringSet(config.Columns)
+	newColumns := make([]namedColumn, 0, len(left.columns)+len(right.columns)-len(config.Columns))
+	newColumnsByName := make(map[string]namedColumn, cap(newColumns))
+	addColumn := func(name string, col column.Column) error {
+		if err := qfstrings.CheckName(name); err != nil {
+			return err
+		}
+
+		if _, ok := newColumnsByName[name]; ok {
+			return errors.New("Join", `duplicate column name in result: "%s"`, name)
+		}
+
+		nc := namedColumn{Column: col, name: name, pos: len(newColumns)}
+		newColumns = append(newColumns, nc)
+		newColumnsByName[name] = nc
+		return nil
+	}
+
+	for _, c := range left.columns {
+		var col column.Column
+		var err error
+		if keys.Contains(c.name) {
+			col, err = keySubset(config.How, c.Column, leftIx, right.columnsByName[c.name].Column, rightIx)
+		} else {
+			col, err = nullableSubset(c.Column, leftIx)
+		}
+
+		if err == nil {
+			err = addColumn(joinColumnName(c.name, right, keys, config.LeftSuffix), col)
+		}
+
+		if err != nil {
+			return left.withErr(errors.Propagate("Join", err))
+		}
+	}
+
+	for _, c := range right.columns {
+		if keys.Contains(c.name) {
+			continue
+		}
+
+		col, err := nullableSubset(c.Column, rightIx)
+		if err == nil {
+			err = addColumn(joinColumnName(c.name, left, keys, config.RightSuffix), col)
+		}
+
+		if err != nil {
+			return left.withErr(errors.Propagate("Join", err))
+		}
+	}
+
+	return QFrame{columns: newColumns, columnsByName: newColumnsByName, index: index.NewAscending(uint32(len(leftIx)))}
+}
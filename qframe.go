@@ -1,18 +1,22 @@
 package qframe
 
 import (
+	"bytes"
 	"database/sql"
-	stdcsv "encoding/csv"
 	"fmt"
 	"io"
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/tobgu/qframe/config/csv"
 	"github.com/tobgu/qframe/config/eval"
 	"github.com/tobgu/qframe/config/groupby"
+	"github.com/tobgu/qframe/config/json"
+	"github.com/tobgu/qframe/config/ndjson"
 	"github.com/tobgu/qframe/config/newqf"
+	"github.com/tobgu/qframe/config/parquet"
 	qsql "github.com/tobgu/qframe/config/sql"
 	"github.com/tobgu/qframe/errors"
 	"github.com/tobgu/qframe/filter"
@@ -24,11 +28,15 @@ import (
 	"github.com/tobgu/qframe/internal/icolumn"
 	"github.com/tobgu/qframe/internal/index"
 	qfio "github.com/tobgu/qframe/internal/io"
+	qfarrow "github.com/tobgu/qframe/internal/io/arrow"
+	qfnative "github.com/tobgu/qframe/internal/io/native"
+	qfparquet "github.com/tobgu/qframe/internal/io/parquet"
 	qfsqlio "github.com/tobgu/qframe/internal/io/sql"
 	"github.com/tobgu/qframe/internal/math/integer"
 	"github.com/tobgu/qframe/internal/scolumn"
 	qfsort "github.com/tobgu/qframe/internal/sort"
 	qfstrings "github.com/tobgu/qframe/internal/strings"
+	"github.com/tobgu/qframe/internal/tcolumn"
 	"github.com/tobgu/qframe/types"
 
 	// This dependency has been been added just to make sure that "go get" installs it.
@@ -52,6 +60,9 @@ type QFrame struct {
 	columnsByName map[string]namedColumn
 	index         index.Int
 
+	// mapping is set for frames returned by Open.
+	mapping *qfnative.Mapping
+
 	// Err indicates that an error has occurred while running an operation.
 	// If Err is set it will prevent any further operations from being executed
 	// on the QFrame.
@@ -111,6 +122,10 @@ func createColumn(name string, data interface{}, config *newqf.Config) (column.C
 	switch t := data.(type) {
 	case []int:
 		localS = icolumn.New(t)
+	case []*int:
+		localS = icolumn.NewPointers(t)
+	case icolumn.Column:
+		localS = t
 	case ConstInt:
 		localS = icolumn.NewConst(t.Val, t.Count)
 	case []float64:
@@ -125,6 +140,8 @@ func createColumn(name string, data interface{}, config *newqf.Config) (column.C
 			}
 			// Book keeping
 			delete(config.EnumColumns, name)
+		} else if c, ok := ecolumn.NewAuto(t, config.AutoEnum); ok {
+			localS = c
 		} else {
 			localS = scolumn.New(t)
 		}
@@ -142,10 +159,20 @@ func createColumn(name string, data interface{}, config *newqf.Config) (column.C
 
 	case []bool:
 		localS = bcolumn.New(t)
+	case []*bool:
+		localS = bcolumn.NewPointers(t)
+	case bcolumn.Column:
+		localS = t
 	case ConstBool:
 		localS = bcolumn.NewConst(t.Val, t.Count)
 	case ecolumn.Column:
 		localS = t
+	case []time.Time:
+		localS = tcolumn.New(t)
+	case []*time.Time:
+		localS = tcolumn.NewPointers(t)
+	case tcolumn.Column:
+		localS = t
 	case qfstrings.StringBlob:
 		localS = scolumn.NewBytes(t.Pointers, t.Data)
 	default:
@@ -533,6 +560,48 @@ func (qf QFrame) Select(columns ...string) QFrame {
 	return QFrame{columns: newColumns, columnsByName: newColumnsByName, index: qf.index}
 }
 
+// Rename creates a new projection of the QFrame with columns renamed according to names.
+// Columns keep their positions in the QFrame.
+//
+// names - map old column name -> new column name. Columns not listed keep their names.
+//
+// It is an error to rename a column to a name that is already taken by another
+// column after the rename. Swapping names of two columns is allowed.
+//
+// Time complexity O(m) where m = number of columns.
+func (qf QFrame) Rename(names map[string]string) QFrame {
+	if qf.Err != nil || len(names) == 0 {
+		return qf
+	}
+
+	for oldName, newName := range names {
+		if _, ok := qf.columnsByName[oldName]; !ok {
+			return qf.withErr(errors.New("Rename", unknownCol(oldName)))
+		}
+
+		if err := qfstrings.CheckName(newName); err != nil {
+			return qf.withErr(errors.Propagate("Rename", err))
+		}
+	}
+
+	newColumnsByName := make(map[string]namedColumn, len(qf.columns))
+	newColumns := make([]namedColumn, len(qf.columns))
+	for i, col := range qf.columns {
+		if newName, ok := names[col.name]; ok {
+			col.name = newName
+		}
+
+		if _, ok := newColumnsByName[col.name]; ok {
+			return qf.withErr(errors.New("Rename", "duplicate column name after rename: %s", col.name))
+		}
+
+		newColumnsByName[col.name] = col
+		newColumns[i] = col
+	}
+
+	return QFrame{columns: newColumns, columnsByName: newColumnsByName, index: qf.index}
+}
+
 // GroupBy groups rows together for which the values of specified columns are the same.
 // Aggregations on the groups can be executed on the returned Grouper object.
 // Leaving out columns to group by will make one large group over which aggregations can be done.
@@ -754,6 +823,18 @@ func (qf QFrame) apply0(fn types.DataFuncOrBuiltInId, dstCol string) QFrame {
 		data = ConstString{Val: t, Count: colLen}
 	case string:
 		data = ConstString{Val: &t, Count: colLen}
+	case func() time.Time:
+		lData := make([]time.Time, colLen)
+		for _, i := range qf.index {
+			lData[i] = t()
+		}
+		data = lData
+	case time.Time:
+		lData := make([]time.Time, colLen)
+		for i := range lData {
+			lData[i] = t
+		}
+		data = lData
 	case types.ColumnName:
 		return qf.Copy(dstCol, string(t))
 	default:
@@ -786,23 +867,38 @@ func (qf QFrame) apply1(fn types.DataFuncOrBuiltInId, dstCol, srcCol string) QFr
 		return qf.withErr(errors.Propagate("apply1", err))
 	}
 
-	var resultColumn column.Column
-	switch t := sliceResult.(type) {
+	resultColumn, err := sliceToColumn(sliceResult)
+	if err != nil {
+		return qf.withErr(errors.Propagate("apply1", err))
+	}
+
+	return qf.setColumn(dstCol, resultColumn)
+}
+
+// sliceToColumn wraps the data produced by functions such as Apply1 and Aggregate in a column.
+func sliceToColumn(data interface{}) (column.Column, error) {
+	switch t := data.(type) {
 	case []int:
-		resultColumn = icolumn.New(t)
+		return icolumn.New(t), nil
 	case []float64:
-		resultColumn = fcolumn.New(t)
+		return fcolumn.New(t), nil
 	case []bool:
-		resultColumn = bcolumn.New(t)
+		return bcolumn.New(t), nil
 	case []*string:
-		resultColumn = scolumn.New(t)
+		return scolumn.New(t), nil
+	case column.NullableData:
+		switch d := t.Data.(type) {
+		case []int:
+			return icolumn.NewNullable(d, t.Valid), nil
+		case []bool:
+			return bcolumn.NewNullable(d, t.Valid), nil
+		}
+		return nil, errors.New("sliceToColumn", "unexpected type of nullable data %#v", t.Data)
 	case column.Column:
-		resultColumn = t
+		return t, nil
 	default:
-		return qf.withErr(errors.New("apply1", "unexpected type of new columns %#v", t))
+		return nil, errors.New("sliceToColumn", "unexpected type of new columns %#v", t)
 	}
-
-	return qf.setColumn(dstCol, resultColumn)
 }
 
 // apply2 is a helper function for zero argument applies.
@@ -932,6 +1028,7 @@ func (qf QFrame) functionType(name string) (types.FunctionType, error) {
 
 // ReadCSV returns a QFrame with data, in CSV format, taken from reader.
 // Column data types are auto detected if not explicitly specified.
+// Input compressed with gzip or bzip2 is detected and decompressed automatically.
 //
 // Time complexity O(m * n) where m = number of columns, n = number of rows.
 func ReadCSV(reader io.Reader, confFuncs ...csv.ConfigFunc) QFrame {
@@ -941,10 +1038,57 @@ func ReadCSV(reader io.Reader, confFuncs ...csv.ConfigFunc) QFrame {
 		return QFrame{Err: err}
 	}
 
-	return New(data, newqf.ColumnOrder(columns...))
+	return New(data, newqf.ColumnOrder(columns...)).Rename(conf.Rename)
+}
+
+// ReadCSVChunks reads CSV data from reader in chunks of at most chunkRows rows and calls fn
+// with a QFrame holding the data of each chunk, in order. This allows processing of CSV data
+// that does not fit in memory.
+//
+// The same options as for ReadCSV can be used. Column types not given by csv.Types are
+// inferred from the first chunk and kept for all following chunks, reading fails if a value
+// in a later chunk does not match the type. Values of enum columns that are not given by
+// csv.EnumValues are collected from all chunks read so far. The internal codes, and hence
+// the order, of the values are the same in all chunks with new values added last.
+//
+// Reading stops at the first error returned from fn, that error is returned.
+//
+// Time complexity O(m * n) where m = number of columns, n = number of rows.
+func ReadCSVChunks(reader io.Reader, chunkRows int, fn func(QFrame) error, confFuncs ...csv.ConfigFunc) error {
+	if chunkRows < 1 {
+		return errors.New("ReadCSVChunks", "chunk size must be positive: %d", chunkRows)
+	}
+
+	conf := csv.NewConfig(confFuncs)
+	r, err := qfio.NewCSVReader(reader, qfio.CSVConfig(conf))
+	if err != nil {
+		return errors.Propagate("ReadCSVChunks", err)
+	}
+
+	for {
+		data, rowCount, err := r.ReadChunk(chunkRows)
+		if err != nil {
+			return errors.Propagate("ReadCSVChunks", err)
+		}
+
+		if rowCount == 0 {
+			return nil
+		}
+
+		qf := New(data, newqf.ColumnOrder(r.Headers()...)).Rename(conf.Rename)
+		if qf.Err != nil {
+			return errors.Propagate("ReadCSVChunks", qf.Err)
+		}
+
+		if err := fn(qf); err != nil {
+			return err
+		}
+	}
 }
 
 // ReadJSON returns a QFrame with data, in JSON format, taken from reader.
+// Input compressed with gzip or bzip2 is detected and decompressed automatically.
+// See newqf.AutoEnum for how to read low cardinality string columns as enums.
 //
 // Time complexity O(m * n) where m = number of columns, n = number of rows.
 func ReadJSON(reader io.Reader, fns ...newqf.ConfigFunc) QFrame {
@@ -956,6 +1100,115 @@ func ReadJSON(reader io.Reader, fns ...newqf.ConfigFunc) QFrame {
 	return New(data, fns...)
 }
 
+// ReadNDJSON returns a QFrame with data, in newline delimited JSON format with one
+// JSON object per line, taken from reader. Input compressed with gzip or bzip2 is
+// detected and decompressed automatically.
+//
+// Values are decoded directly into their columns, one at a time, rather than as
+// intermediate records. Keys missing from a line are null. Column types are inferred
+// from the data unless given using ndjson.Types.
+//
+// Time complexity O(m * n) where m = number of columns, n = number of rows.
+func ReadNDJSON(reader io.Reader, confFuncs ...ndjson.ConfigFunc) QFrame {
+	conf := ndjson.NewConfig(confFuncs)
+	data, columns, err := qfio.ReadNDJSON(reader, qfio.NDJSONConfig(conf))
+	if err != nil {
+		return QFrame{Err: err}
+	}
+
+	return New(data, newqf.ColumnOrder(columns...))
+}
+
+// ReadArrow returns a QFrame with data, in the Arrow IPC streaming format, taken from reader.
+//
+// Int, float, bool, utf8 and timestamp columns are supported. Dictionary encoded utf8
+// columns are read as enums with the values ordered as in the dictionary. Data from
+// all record batches in the stream are concatenated.
+//
+// Time complexity O(m * n) where m = number of columns, n = number of rows.
+func ReadArrow(reader io.Reader) QFrame {
+	data, columns, err := qfarrow.Read(reader)
+	if err != nil {
+		return QFrame{Err: err}
+	}
+
+	return New(data, newqf.ColumnOrder(columns...))
+}
+
+// ReadParquet returns a QFrame with data, in the Apache Parquet format, taken from reader.
+// size is the size of the file in bytes.
+//
+// Flat schemas of int, float, bool, string and timestamp columns are supported. Byte
+// array columns annotated as enums are read as enums. Plain, dictionary and RLE encoded
+// pages, uncompressed or compressed with snappy or gzip, can be read.
+//
+// Time complexity O(m * n) where m = number of columns, n = number of rows.
+func ReadParquet(reader io.ReaderAt, size int64, confFuncs ...parquet.ConfigFunc) QFrame {
+	conf := parquet.NewConfig(confFuncs)
+	data, columns, err := qfparquet.Read(reader, size, qfparquet.ReadConfig(conf))
+	if err != nil {
+		return QFrame{Err: err}
+	}
+
+	return New(data, newqf.ColumnOrder(columns...))
+}
+
+// ReadFrom returns a QFrame read from reader in the qframe binary format, as written
+// by WriteTo.
+//
+// Time complexity O(m * n) where m = number of columns, n = number of rows.
+func ReadFrom(reader io.Reader) QFrame {
+	data, columns, err := qfnative.Read(reader)
+	if err != nil {
+		return QFrame{Err: err}
+	}
+
+	return New(data, newqf.ColumnOrder(columns...))
+}
+
+// Open returns a read only QFrame backed by a memory mapping of the file at path,
+// in the qframe binary format as written by WriteTo.
+//
+// Int, float, bool, time and string data is referenced directly from the mapping
+// rather than being copied onto the heap which makes it possible to work with frames
+// larger than the available memory. All operations on the frame work as usual.
+//
+// Close must be called to unmap the file once the frame, and any frame derived from
+// it, is no longer used. Accessing the data after Close results in a crash.
+//
+// Time complexity O(m * n) where m = number of columns, n = number of rows. The data
+// is validated but not copied.
+func Open(path string) QFrame {
+	data, columns, mapping, err := qfnative.Open(path)
+	if err != nil {
+		return QFrame{Err: err}
+	}
+
+	qf := New(data, newqf.ColumnOrder(columns...))
+	if qf.Err != nil {
+		mapping.Close()
+		return qf
+	}
+
+	qf.mapping = mapping
+	return qf
+}
+
+// Close unmaps the file backing a QFrame returned by Open. Neither the frame nor
+// any frame derived from it may be used after Close. Calling Close on other frames,
+// or more than once, has no effect.
+func (qf QFrame) Close() error {
+	if qf.mapping == nil {
+		return nil
+	}
+
+	if err := qf.mapping.Close(); err != nil {
+		return errors.Propagate("Close", err)
+	}
+
+	return nil
+}
+
 // ReadSQL returns a QFrame by reading the results of a SQL query.
 func ReadSQL(tx *sql.Tx, confFuncs ...qsql.ConfigFunc) QFrame {
 	conf := qsql.NewConfig(confFuncs)
@@ -976,56 +1229,59 @@ func ReadSQL(tx *sql.Tx, confFuncs ...qsql.ConfigFunc) QFrame {
 	if err != nil {
 		return QFrame{Err: err}
 	}
-	return New(data, newqf.ColumnOrder(columns...))
+	return New(data, newqf.ColumnOrder(columns...)).Rename(conf.Rename)
 }
 
 // ToCSV writes the data in the QFrame, in CSV format, to writer.
 //
-// Time complexity O(m * n) where m = number of rows, n = number of columns.
+// The output can be configured using the csv.ToConfigFunc options, for example
+// csv.NullRep to control how null values are written or csv.Gzip to compress the output.
 //
-// This is function is currently unoptimized. It could probably be a lot speedier with
-// a custom written CSV writer that handles quoting etc. differently.
-func (qf QFrame) ToCSV(writer io.Writer) error {
+// Time complexity O(m * n) where m = number of rows, n = number of columns.
+func (qf QFrame) ToCSV(writer io.Writer, confFuncs ...csv.ToConfigFunc) error {
 	if qf.Err != nil {
 		return errors.Propagate("ToCSV", qf.Err)
 	}
 
-	row := make([]string, 0, len(qf.columns))
-	for _, s := range qf.columns {
-		row = append(row, s.name)
-	}
-
+	headers := make([]string, 0, len(qf.columns))
 	columns := make([]column.Column, 0, len(qf.columns))
-	for _, name := range row {
-		columns = append(columns, qf.columnsByName[name])
-	}
-
-	w := stdcsv.NewWriter(writer)
-	err := w.Write(row)
-	if err != nil {
-		return err
+	for _, s := range qf.columns {
+		headers = append(headers, s.name)
+		columns = append(columns, s.Column)
 	}
 
-	for i := 0; i < qf.Len(); i++ {
-		row = row[:0]
-		for _, col := range columns {
-			row = append(row, col.StringAt(qf.index[i], ""))
-		}
-		w.Write(row)
+	conf := csv.NewToConfig(confFuncs)
+	if err := qfio.WriteCSV(writer, headers, columns, qf.index, qfio.CSVWriteConfig(conf)); err != nil {
+		return errors.Propagate("ToCSV", err)
 	}
 
-	w.Flush()
 	return nil
 }
 
 // ToJSON writes the data in the QFrame, in JSON format one record per row, to writer.
 //
+// The output can be compressed using json.Gzip.
+//
 // Time complexity O(m * n) where m = number of rows, n = number of columns.
-func (qf QFrame) ToJSON(writer io.Writer) error {
+func (qf QFrame) ToJSON(writer io.Writer, confFuncs ...json.ToConfigFunc) error {
 	if qf.Err != nil {
 		return errors.Propagate("ToJSON", qf.Err)
 	}
 
+	conf := json.NewToConfig(confFuncs)
+	w, err := qfio.Compress(writer, conf.Compression, conf.CompressionLevel)
+	if err != nil {
+		return errors.Propagate("ToJSON", err)
+	}
+
+	if err := qf.writeJSON(w); err != nil {
+		return err
+	}
+
+	return w.Close()
+}
+
+func (qf QFrame) writeJSON(writer io.Writer) error {
 	colByteNames := make([][]byte, 0, len(qf.columns))
 	columns := make([]column.Column, 0, len(qf.columns))
 	for name, col := range qf.columnsByName {
@@ -1046,21 +1302,7 @@ func (qf QFrame) ToJSON(writer io.Writer) error {
 			jsonBuf = append(jsonBuf, byte(','))
 		}
 
-		jsonBuf = append(jsonBuf, byte('{'))
-
-		for j, col := range columns {
-			jsonBuf = append(jsonBuf, colByteNames[j]...)
-			jsonBuf = append(jsonBuf, byte(':'))
-			jsonBuf = col.AppendByteStringAt(jsonBuf, ix)
-			jsonBuf = append(jsonBuf, byte(','))
-		}
-
-		if jsonBuf[len(jsonBuf)-1] == ',' {
-			jsonBuf = jsonBuf[:len(jsonBuf)-1]
-		}
-
-		jsonBuf = append(jsonBuf, byte('}'))
-
+		jsonBuf = appendJSONRecord(jsonBuf, colByteNames, columns, nil, ix)
 		_, err = writer.Write(jsonBuf)
 		if err != nil {
 			return err
@@ -1071,6 +1313,171 @@ func (qf QFrame) ToJSON(writer io.Writer) error {
 	return err
 }
 
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// appendJSONRecord appends row ix of columns as a JSON object to buf. Integral values
+// of the columns marked in floatCols, if any, are written with a decimal point.
+func appendJSONRecord(buf []byte, colByteNames [][]byte, columns []column.Column, floatCols []bool, ix uint32) []byte {
+	buf = append(buf, byte('{'))
+
+	for j, col := range columns {
+		buf = append(buf, colByteNames[j]...)
+		buf = append(buf, byte(':'))
+		start := len(buf)
+		buf = col.AppendByteStringAt(buf, ix)
+		if floatCols != nil && floatCols[j] && isDigit(buf[len(buf)-1]) && bytes.IndexByte(buf[start:], '.') < 0 {
+			buf = append(buf, ".0"...)
+		}
+		buf = append(buf, byte(','))
+	}
+
+	if buf[len(buf)-1] == ',' {
+		buf = buf[:len(buf)-1]
+	}
+
+	return append(buf, byte('}'))
+}
+
+// ToNDJSON writes the data in the QFrame to writer as newline delimited JSON, one
+// JSON object per row and line. Keys are written in column order and null values
+// are written as null. Integral float values are written with a decimal point, e.g.
+// 1.0, for float columns to be read back as floats by ReadNDJSON.
+//
+// Time complexity O(m * n) where m = number of rows, n = number of columns.
+func (qf QFrame) ToNDJSON(writer io.Writer) error {
+	if qf.Err != nil {
+		return errors.Propagate("ToNDJSON", qf.Err)
+	}
+
+	colByteNames := make([][]byte, 0, len(qf.columns))
+	columns := make([]column.Column, 0, len(qf.columns))
+	floatCols := make([]bool, 0, len(qf.columns))
+	for _, col := range qf.columns {
+		columns = append(columns, col.Column)
+		colByteNames = append(colByteNames, qfstrings.AppendQuotedString(nil, col.name))
+		floatCols = append(floatCols, col.DataType() == types.Float)
+	}
+
+	var buf []byte
+	for _, ix := range qf.index {
+		buf = appendJSONRecord(buf[:0], colByteNames, columns, floatCols, ix)
+		buf = append(buf, '\n')
+		if _, err := writer.Write(buf); err != nil {
+			return errors.Propagate("ToNDJSON", err)
+		}
+	}
+
+	return nil
+}
+
+// ToArrow writes the data in the QFrame to writer in the Arrow IPC streaming format.
+//
+// Int and float columns are written as 64 bit values, time columns as timestamps
+// with nanosecond resolution and enum columns as dictionary encoded utf8.
+//
+// Time complexity O(m * n) where m = number of rows, n = number of columns.
+func (qf QFrame) ToArrow(writer io.Writer) error {
+	if qf.Err != nil {
+		return errors.Propagate("ToArrow", qf.Err)
+	}
+
+	names := make([]string, 0, len(qf.columns))
+	columns := make([]column.Column, 0, len(qf.columns))
+	for _, c := range qf.columns {
+		names = append(names, c.name)
+		columns = append(columns, c.Column)
+	}
+
+	if err := qfarrow.Write(writer, names, columns, qf.index); err != nil {
+		return errors.Propagate("ToArrow", err)
+	}
+
+	return nil
+}
+
+// ToParquet writes the data in the QFrame to writer in the Apache Parquet format.
+//
+// Int and float columns are written as 64 bit values, time columns as UTC timestamps
+// with nanosecond resolution and enum columns as dictionary encoded strings.
+// Pages are compressed using snappy by default.
+//
+// Time complexity O(m * n) where m = number of rows, n = number of columns.
+func (qf QFrame) ToParquet(writer io.Writer, confFuncs ...parquet.ToConfigFunc) error {
+	if qf.Err != nil {
+		return errors.Propagate("ToParquet", qf.Err)
+	}
+
+	names := make([]string, 0, len(qf.columns))
+	columns := make([]column.Column, 0, len(qf.columns))
+	for _, c := range qf.columns {
+		names = append(names, c.name)
+		columns = append(columns, c.Column)
+	}
+
+	conf := parquet.NewToConfig(confFuncs)
+	if err := qfparquet.Write(writer, names, columns, qf.index, qfparquet.WriteConfig(conf)); err != nil {
+		return errors.Propagate("ToParquet", err)
+	}
+
+	return nil
+}
+
+// WriteTo writes the QFrame to writer in the qframe binary format and returns the
+// number of bytes written. The format stores the columns using their in memory
+// layout, string columns as a byte blob with pointers into it and enum columns as
+// codes together with the enum values, which makes it fast to write and read.
+// It is intended for caching frames and can only be read on hosts with the same
+// byte order and int size as the one writing it. Use ReadFrom to read the frame back.
+//
+// Time complexity O(m * n) where m = number of rows, n = number of columns.
+func (qf QFrame) WriteTo(writer io.Writer) (int64, error) {
+	if qf.Err != nil {
+		return 0, errors.Propagate("WriteTo", qf.Err)
+	}
+
+	names := make([]string, 0, len(qf.columns))
+	columns := make([]column.Column, 0, len(qf.columns))
+	for _, c := range qf.columns {
+		names = append(names, c.name)
+		columns = append(columns, c.Column)
+	}
+
+	n, err := qfnative.Write(writer, names, columns, qf.index)
+	if err != nil {
+		return n, errors.Propagate("WriteTo", err)
+	}
+
+	return n, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler using the format written by WriteTo.
+func (qf QFrame) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if _, err := qf.WriteTo(buf); err != nil {
+		return nil, errors.Propagate("MarshalBinary", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler using the format read by ReadFrom.
+func (qf *QFrame) UnmarshalBinary(data []byte) error {
+	columns, names, err := qfnative.ReadBytes(data)
+	if err != nil {
+		return err
+	}
+
+	result := New(columns, newqf.ColumnOrder(names...))
+	if result.Err != nil {
+		return errors.Propagate("UnmarshalBinary", result.Err)
+	}
+
+	*qf = result
+	return nil
+}
+
 // ToSQL writes a QFrame into a SQL database.
 func (qf QFrame) ToSQL(tx *sql.Tx, confFuncs ...qsql.ConfigFunc) error {
 	if qf.Err != nil {
@@ -1130,7 +1537,8 @@ func Doc() string {
 		types.Enum:   ecolumn.Doc(),
 		types.Float:  fcolumn.Doc(),
 		types.Int:    icolumn.Doc(),
-		types.String: scolumn.Doc()} {
+		types.String: scolumn.Doc(),
+		types.Time:   tcolumn.Doc()} {
 		result += fmt.Sprintf("%s\n%s\n%s\n", strings.Title(string(typeName)), strings.Repeat("-", len(typeName)), docString)
 	}
 
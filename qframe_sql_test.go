@@ -5,6 +5,7 @@ import (
 	"database/sql/driver"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/tobgu/qframe"
 	qsql "github.com/tobgu/qframe/config/sql"
@@ -196,3 +197,95 @@ func TestQFrame_ReadSQLCoercion(t *testing.T) {
 	})
 	assertEquals(t, expected, qf)
 }
+
+func TestQFrame_ReadSQLRename(t *testing.T) {
+	dvr := MockDriver{t: t}
+	dvr.results.columns = []string{"COL1", "COL2"}
+	dvr.results.values = [][]driver.Value{
+		{int64(1), int64(0)},
+		{int64(0), int64(1)},
+	}
+	sql.Register("TestReadSQLRename", dvr)
+	db, _ := sql.Open("TestReadSQLRename", "")
+	tx, _ := db.Begin()
+	qf := qframe.ReadSQL(tx,
+		qsql.Coerce(qsql.CoercePair{Column: "COL1", Type: qsql.Int64ToBool}),
+		qsql.Rename(map[string]string{"COL1": "active", "COL2": "count"}))
+	assertNotErr(t, qf.Err)
+	expected := qframe.New(map[string]interface{}{
+		"active": []bool{true, false},
+		"count":  []int{0, 1},
+	})
+	assertEquals(t, expected, qf)
+}
+
+func TestQFrame_ReadSQLNullIntBool(t *testing.T) {
+	dvr := MockDriver{t: t}
+	dvr.results.columns = []string{"COL1", "COL2"}
+	dvr.results.values = [][]driver.Value{
+		{nil, true},
+		{int64(2), nil},
+		{int64(3), false},
+	}
+	sql.Register("TestReadSQLNullIntBool", dvr)
+	db, _ := sql.Open("TestReadSQLNullIntBool", "")
+	tx, _ := db.Begin()
+	qf := qframe.ReadSQL(tx)
+	assertNotErr(t, qf.Err)
+	expected := qframe.New(map[string]interface{}{
+		"COL1": []*int{nil, intPtr(2), intPtr(3)},
+		"COL2": []*bool{boolPtr(true), nil, boolPtr(false)},
+	})
+	assertEquals(t, expected, qf)
+}
+
+func TestQFrame_ToSQLNullIntBool(t *testing.T) {
+	dvr := MockDriver{t: t}
+	dvr.query = "INSERT INTO test (COL1,COL2) VALUES (?,?);"
+	dvr.args.values = [][]driver.Value{
+		{nil, true},
+		{int64(2), nil},
+	}
+	sql.Register("TestToSQLNullIntBool", dvr)
+	db, _ := sql.Open("TestToSQLNullIntBool", "")
+	tx, _ := db.Begin()
+	qf := qframe.New(map[string]interface{}{
+		"COL1": []*int{nil, intPtr(2)},
+		"COL2": []*bool{boolPtr(true), nil},
+	})
+	assertNotErr(t, qf.ToSQL(tx, qsql.Table("test")))
+}
+
+func TestQFrame_ReadSQLTime(t *testing.T) {
+	dvr := MockDriver{t: t}
+	dvr.results.columns = []string{"COL1"}
+	dvr.results.values = [][]driver.Value{
+		{nil},
+		{date(2018, 1, 2, 3)},
+	}
+	sql.Register("TestReadSQLTime", dvr)
+	db, _ := sql.Open("TestReadSQLTime", "")
+	tx, _ := db.Begin()
+	qf := qframe.ReadSQL(tx)
+	assertNotErr(t, qf.Err)
+	expected := qframe.New(map[string]interface{}{
+		"COL1": []*time.Time{nil, timePtr(date(2018, 1, 2, 3))},
+	})
+	assertEquals(t, expected, qf)
+}
+
+func TestQFrame_ToSQLTime(t *testing.T) {
+	dvr := MockDriver{t: t}
+	dvr.query = "INSERT INTO test (COL1) VALUES (?);"
+	dvr.args.values = [][]driver.Value{
+		{date(2018, 1, 2, 3)},
+		{nil},
+	}
+	sql.Register("TestToSQLTime", dvr)
+	db, _ := sql.Open("TestToSQLTime", "")
+	tx, _ := db.Begin()
+	qf := qframe.New(map[string]interface{}{
+		"COL1": []*time.Time{timePtr(date(2018, 1, 2, 3)), nil},
+	})
+	assertNotErr(t, qf.ToSQL(tx, qsql.Table("test")))
+}
@@ -2,20 +2,33 @@ package qframe_test
 
 import (
 	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"math"
+	"os"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/tobgu/qframe"
 	"github.com/tobgu/qframe/aggregation"
+	"github.com/tobgu/qframe/config/cast"
 	"github.com/tobgu/qframe/config/csv"
 	"github.com/tobgu/qframe/config/eval"
 	"github.com/tobgu/qframe/config/groupby"
+	"github.com/tobgu/qframe/config/join"
+	qfjson "github.com/tobgu/qframe/config/json"
+	"github.com/tobgu/qframe/config/ndjson"
 	"github.com/tobgu/qframe/config/newqf"
+	"github.com/tobgu/qframe/config/parquet"
+	"github.com/tobgu/qframe/config/rolling"
 	"github.com/tobgu/qframe/types"
 	"io"
 	"log"
@@ -48,6 +61,14 @@ func assertErr(t *testing.T, err error, expectedErr string) {
 	}
 }
 
+func intPtr(i int) *int {
+	return &i
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
 func assertTrue(t *testing.T, b bool) {
 	t.Helper()
 	if !b {
@@ -244,6 +265,12 @@ func TestQFrame_FilterIsNull(t *testing.T) {
 		{operation: "isnotnull", input: []*string{&a, nil, nil, &b}, expected: []*string{&a, &b}, isEnum: true},
 		{operation: "isnull", input: []float64{1, math.NaN(), 2}, expected: []float64{math.NaN()}},
 		{operation: "isnotnull", input: []float64{1, math.NaN(), 2}, expected: []float64{1, 2}},
+		{operation: "isnull", input: []*int{intPtr(1), nil, intPtr(2)}, expected: []*int{nil}},
+		{operation: "isnotnull", input: []*int{intPtr(1), nil, intPtr(2)}, expected: []int{1, 2}},
+		{operation: "isnull", input: []*int{intPtr(1), nil, intPtr(2)}, expected: []int{1, 2}, inverse: true},
+		{operation: "isnull", input: []int{1, 2}, expected: []int{}},
+		{operation: "isnull", input: []*bool{boolPtr(true), nil, boolPtr(false)}, expected: []*bool{nil}},
+		{operation: "isnotnull", input: []*bool{boolPtr(true), nil, boolPtr(false)}, expected: []bool{true, false}},
 	}
 
 	for _, tc := range table {
@@ -425,6 +452,14 @@ func TestQFrame_SortNull(t *testing.T) {
 		"COL1": []float64{1.0, math.NaN(), -1.0, math.NaN()},
 	}
 
+	intIn := map[string]interface{}{
+		"COL1": []*int{intPtr(1), nil, intPtr(-1), nil},
+	}
+
+	boolIn := map[string]interface{}{
+		"COL1": []*bool{boolPtr(true), nil, boolPtr(false)},
+	}
+
 	table := []struct {
 		in       map[string]interface{}
 		orders   []qframe.Order
@@ -458,6 +493,27 @@ func TestQFrame_SortNull(t *testing.T) {
 				"COL1": []float64{1.0, -1.0, math.NaN(), math.NaN()},
 			},
 		},
+		{
+			intIn,
+			[]qframe.Order{{Column: "COL1"}},
+			map[string]interface{}{
+				"COL1": []*int{nil, nil, intPtr(-1), intPtr(1)},
+			},
+		},
+		{
+			intIn,
+			[]qframe.Order{{Column: "COL1", Reverse: true}},
+			map[string]interface{}{
+				"COL1": []*int{intPtr(1), intPtr(-1), nil, nil},
+			},
+		},
+		{
+			boolIn,
+			[]qframe.Order{{Column: "COL1"}},
+			map[string]interface{}{
+				"COL1": []*bool{nil, boolPtr(false), boolPtr(true)},
+			},
+		},
 	}
 
 	for i, tc := range table {
@@ -609,6 +665,25 @@ func TestQFrame_GroupByAggregate(t *testing.T) {
 			groupColumns: []string{},
 			aggregations: []qframe.Aggregation{{Fn: "majority", Column: "COL1"}},
 		},
+		{
+			name: "multiple aggregations on the same column",
+			input: map[string]interface{}{
+				"COL1": []int{0, 0, 1, 1, 1},
+				"COL2": []int{1, 2, 5, 7, 9}},
+			expected: map[string]interface{}{
+				"COL1":  []int{0, 1},
+				"COUNT": []int{2, 3},
+				"MAX":   []int{2, 9},
+				"MEAN":  []float64{1.5, 7},
+				"MIN":   []int{1, 5}},
+			groupColumns: []string{"COL1"},
+			aggregations: []qframe.Aggregation{
+				{Fn: "count", Column: "COL1", As: "COUNT"},
+				{Fn: "max", Column: "COL2", As: "MAX"},
+				{Fn: "mean", Column: "COL2", As: "MEAN"},
+				{Fn: "min", Column: "COL2", As: "MIN"},
+			},
+		},
 		{
 			name:         "group by booleans",
 			input:        map[string]interface{}{"COL1": []bool{true, false, true}, "COL2": []int{1, 2, 3}},
@@ -628,6 +703,110 @@ func TestQFrame_GroupByAggregate(t *testing.T) {
 	}
 }
 
+func TestQFrame_BuiltInAggregations(t *testing.T) {
+	a, b, c := "a", "b", "c"
+	nan := math.NaN()
+	groups := []int{1, 1, 1, 2, 2}
+	table := []struct {
+		input    interface{}
+		fn       string
+		expected interface{}
+		enums    []string
+	}{
+		{input: []int{3, 1, 2, 5, 4}, fn: "count", expected: []int{3, 2}},
+		{input: []int{3, 1, 2, 5, 4}, fn: "count_non_null", expected: []int{3, 2}},
+		{input: []int{3, 1, 2, 5, 4}, fn: "sum", expected: []int{6, 9}},
+		{input: []int{3, 1, 2, 5, 4}, fn: "min", expected: []int{1, 4}},
+		{input: []int{3, 1, 2, 5, 4}, fn: "max", expected: []int{3, 5}},
+		{input: []int{3, 1, 2, 5, 4}, fn: "mean", expected: []float64{2, 4.5}},
+		{input: []int{3, 1, 2, 5, 4}, fn: "median", expected: []float64{2, 4.5}},
+		{input: []int{3, 1, 2, 5, 4}, fn: "var", expected: []float64{1, 0.5}},
+		{input: []int{3, 1, 5, 5, 4}, fn: "std", expected: []float64{2, math.Sqrt(0.5)}},
+		{input: []int{3, 1, 2, 5, 4}, fn: "first", expected: []int{3, 5}},
+		{input: []int{3, 1, 2, 5, 4}, fn: "last", expected: []int{2, 4}},
+		{input: []int{3, 3, 2, 5, 5}, fn: "nunique", expected: []int{2, 1}},
+		{input: []*int{intPtr(3), nil, intPtr(2), nil, nil}, fn: "count", expected: []int{3, 2}},
+		{input: []*int{intPtr(3), nil, intPtr(2), nil, nil}, fn: "count_non_null", expected: []int{2, 0}},
+		{input: []*int{intPtr(3), nil, intPtr(2), nil, nil}, fn: "sum", expected: []*int{intPtr(5), nil}},
+		{input: []*int{intPtr(3), nil, intPtr(2), nil, nil}, fn: "mean", expected: []float64{2.5, nan}},
+		{input: []*int{intPtr(3), nil, intPtr(3), nil, nil}, fn: "nunique", expected: []int{1, 0}},
+		{input: []*int{nil, intPtr(1), intPtr(2), intPtr(5), nil}, fn: "first", expected: []*int{nil, intPtr(5)}},
+		{input: []*int{nil, intPtr(1), intPtr(2), intPtr(5), nil}, fn: "last", expected: []*int{intPtr(2), nil}},
+		{input: []float64{3, nan, 1, nan, nan}, fn: "count", expected: []int{3, 2}},
+		{input: []float64{3, nan, 1, nan, nan}, fn: "count_non_null", expected: []int{2, 0}},
+		{input: []float64{3, nan, 1, nan, 4}, fn: "min", expected: []float64{1, 4}},
+		{input: []float64{3, nan, 1, nan, nan}, fn: "max", expected: []float64{3, nan}},
+		{input: []float64{3, nan, 1, 2, nan}, fn: "mean", expected: []float64{2, 2}},
+		{input: []float64{3, nan, 1, 2, 4}, fn: "median", expected: []float64{2, 3}},
+		{input: []float64{3, nan, 1, 2, nan}, fn: "var", expected: []float64{2, nan}},
+		{input: []float64{3, nan, 1, 2, nan}, fn: "std", expected: []float64{math.Sqrt(2), nan}},
+		{input: []float64{nan, 1, 1, 2, 3}, fn: "first", expected: []float64{nan, 2}},
+		{input: []float64{3, 1, nan, 2, 3}, fn: "last", expected: []float64{nan, 3}},
+		{input: []float64{1, nan, 1, 2, 3}, fn: "nunique", expected: []int{1, 2}},
+		{input: []bool{true, false, true, false, false}, fn: "count", expected: []int{3, 2}},
+		{input: []bool{true, false, true, false, false}, fn: "any", expected: []bool{true, false}},
+		{input: []bool{true, false, true, true, true}, fn: "all", expected: []bool{false, true}},
+		{input: []bool{true, false, true, true, true}, fn: "first", expected: []bool{true, true}},
+		{input: []bool{true, false, false, true, true}, fn: "last", expected: []bool{false, true}},
+		{input: []bool{true, false, true, true, true}, fn: "nunique", expected: []int{2, 1}},
+		{input: []*bool{boolPtr(true), nil, boolPtr(false), nil, nil}, fn: "any", expected: []*bool{boolPtr(true), nil}},
+		{input: []*bool{boolPtr(true), nil, boolPtr(false), nil, nil}, fn: "count_non_null", expected: []int{2, 0}},
+		{input: []*string{&b, nil, &a, nil, nil}, fn: "count", expected: []int{3, 2}},
+		{input: []*string{&b, nil, &a, nil, nil}, fn: "count_non_null", expected: []int{2, 0}},
+		{input: []*string{&b, nil, &a, nil, &c}, fn: "min", expected: []*string{&a, &c}},
+		{input: []*string{&b, nil, &a, nil, &c}, fn: "max", expected: []*string{&b, &c}},
+		{input: []*string{nil, &b, &a, &c, nil}, fn: "first", expected: []*string{nil, &c}},
+		{input: []*string{nil, &b, &a, &c, nil}, fn: "last", expected: []*string{&a, nil}},
+		{input: []*string{&a, &b, &a, nil, nil}, fn: "nunique", expected: []int{2, 0}},
+		{input: []*string{&b, nil, &a, nil, nil}, fn: "count", expected: []int{3, 2}, enums: []string{"c", "b", "a"}},
+		{input: []*string{&b, nil, &a, nil, nil}, fn: "count_non_null", expected: []int{2, 0}, enums: []string{"c", "b", "a"}},
+		{input: []*string{&b, nil, &a, nil, &c}, fn: "min", expected: []*string{&b, &c}, enums: []string{"c", "b", "a"}},
+		{input: []*string{&b, nil, &a, nil, &c}, fn: "max", expected: []*string{&a, &c}, enums: []string{"c", "b", "a"}},
+		{input: []*string{nil, &b, &a, &c, nil}, fn: "first", expected: []*string{nil, &c}, enums: []string{"c", "b", "a"}},
+		{input: []*string{nil, &b, &a, &c, nil}, fn: "last", expected: []*string{&a, nil}, enums: []string{"c", "b", "a"}},
+		{input: []*string{&a, &b, &a, nil, nil}, fn: "nunique", expected: []int{2, 0}, enums: []string{"c", "b", "a"}},
+	}
+
+	for _, tc := range table {
+		t.Run(fmt.Sprintf("%T %s enum=%v", tc.input, tc.fn, tc.enums != nil), func(t *testing.T) {
+			var enums, expectedEnums map[string][]string
+			if tc.enums != nil {
+				enums = map[string][]string{"COL2": tc.enums}
+				if _, ok := tc.expected.([]*string); ok {
+					expectedEnums = enums
+				}
+			}
+
+			in := qframe.New(map[string]interface{}{"COL1": groups, "COL2": tc.input}, newqf.Enums(enums))
+			out := in.GroupBy(groupby.Columns("COL1")).Aggregate(qframe.Aggregation{Fn: tc.fn, Column: "COL2"})
+			expected := qframe.New(map[string]interface{}{"COL1": []int{1, 2}, "COL2": tc.expected}, newqf.Enums(expectedEnums))
+			assertEquals(t, expected, out.Sort(qframe.Order{Column: "COL1"}))
+		})
+	}
+
+	t.Run("Unknown built in aggregation", func(t *testing.T) {
+		in := qframe.New(map[string]interface{}{"COL1": groups, "COL2": []*string{&a, &a, &a, &a, &a}})
+		out := in.GroupBy(groupby.Columns("COL1")).Aggregate(qframe.Aggregation{Fn: "mean", Column: "COL2"})
+		assertErr(t, out.Err, "mean is not defined")
+	})
+
+	t.Run("User defined aggregation with other result type", func(t *testing.T) {
+		in := qframe.New(map[string]interface{}{"COL1": groups, "COL2": []*string{&a, &b, &c, &a, nil}})
+		totalLen := func(ss []*string) int {
+			result := 0
+			for _, s := range ss {
+				if s != nil {
+					result += len(*s)
+				}
+			}
+			return result
+		}
+		out := in.GroupBy(groupby.Columns("COL1")).Aggregate(qframe.Aggregation{Fn: totalLen, Column: "COL2"})
+		expected := qframe.New(map[string]interface{}{"COL1": []int{1, 2}, "COL2": []int{3, 1}})
+		assertEquals(t, expected, out.Sort(qframe.Order{Column: "COL1"}))
+	})
+}
+
 func colNamesToOrders(colNames ...string) []qframe.Order {
 	result := make([]qframe.Order, len(colNames))
 	for i, name := range colNames {
@@ -787,7 +966,7 @@ func TestQFrame_ReadCSV(t *testing.T) {
 			inputData:        "1\n\n3\n",
 			ignoreEmptyLines: false,
 			expected: map[string]interface{}{
-				"foo": []float64{1, math.NaN(), 3}},
+				"foo": []*int{intPtr(1), nil, intPtr(3)}},
 		},
 		{
 			name:         "mixed",
@@ -825,6 +1004,23 @@ func TestQFrame_ReadCSV(t *testing.T) {
 				"foo": []float64{1.5, math.NaN()},
 				"bar": []float64{3.0, 2.0}},
 		},
+		{
+			name:         "null int and bool",
+			inputHeaders: []string{"foo", "bar"},
+			inputData:    "1,true\n,\n3,false",
+			expected: map[string]interface{}{
+				"foo": []*int{intPtr(1), nil, intPtr(3)},
+				"bar": []*bool{boolPtr(true), nil, boolPtr(false)}},
+		},
+		{
+			name:         "null int and bool types",
+			inputHeaders: []string{"foo", "bar"},
+			inputData:    ",\n,\n",
+			expected: map[string]interface{}{
+				"foo": []*int{nil, nil},
+				"bar": []*bool{nil, nil}},
+			types: map[string]string{"foo": "int", "bar": "bool"},
+		},
 		{
 			name:         "Int to float type success",
 			inputHeaders: []string{"foo"},
@@ -1006,17 +1202,17 @@ foo
 		assertErr(t, out.Err, "unknown enum value")
 	})
 
-	t.Run("Fails with too high cardinality column", func(t *testing.T) {
-		input := make([]string, 0)
-		for i := 0; i < 256; i++ {
-			input = append(input, strconv.Itoa(i))
+	t.Run("Supports high cardinality column", func(t *testing.T) {
+		input := "foo\n"
+		for i := 0; i < 1000; i++ {
+			input += strconv.Itoa(i) + "\n"
 		}
 
-		out := qframe.New(
-			map[string]interface{}{"foo": input},
-			newqf.Enums(map[string][]string{"foo": nil}))
-
-		assertErr(t, out.Err, "max cardinality")
+		out := qframe.ReadCSV(strings.NewReader(input), csv.Types(map[string]string{"foo": "enum"}))
+		assertNotErr(t, out.Err)
+		assertTrue(t, out.ColumnTypeMap()["foo"] == types.Enum)
+		assertTrue(t, out.Len() == 1000)
+		assertTrue(t, *out.MustEnumView("foo").ItemAt(999) == "999")
 	})
 
 	t.Run("Fails when enum values specified for non enum column", func(t *testing.T) {
@@ -1099,6 +1295,14 @@ func TestQFrame_ToCSV(t *testing.T) {
 			expected: `BOOL1,FLOAT1,INT1,STRING1
 true,1.5,1,a
 false,2.5,2,"b,c"
+`,
+		},
+		{
+			input: map[string]interface{}{
+				"INT1": []*int{nil, intPtr(2)}, "BOOL1": []*bool{boolPtr(true), nil}},
+			expected: `BOOL1,INT1
+true,
+,2
 `,
 		},
 	}
@@ -1120,140 +1324,1562 @@ false,2.5,2,"b,c"
 	}
 }
 
-func TestQFrame_ToFromJSON(t *testing.T) {
-	config := []newqf.ConfigFunc{newqf.Enums(map[string][]string{"ENUM": {"aa", "bb"}})}
-	data := map[string]interface{}{
-		"STRING1": []string{"añ", "bö☺	"}, "FLOAT1": []float64{1.5, 2.5}, "BOOL1": []bool{true, false}, "ENUM": []string{"aa", "bb"}}
-	originalDf := qframe.New(data, config...)
-	assertNotErr(t, originalDf.Err)
+func TestQFrame_ToCSVOptions(t *testing.T) {
+	a, quoted, empty := "a", `say "hi"`, ""
+	input := qframe.New(map[string]interface{}{
+		"INT":    []*int{intPtr(1), nil},
+		"FLOAT":  []float64{1.25, math.NaN()},
+		"STRING": []*string{&a, nil},
+	}, newqf.ColumnOrder("INT", "FLOAT", "STRING"))
 
-	buf := new(bytes.Buffer)
-	err := originalDf.ToJSON(buf)
-	assertNotErr(t, err)
+	table := []struct {
+		name     string
+		input    qframe.QFrame
+		configs  []csv.ToConfigFunc
+		expected string
+	}{
+		{
+			name:     "defaults",
+			input:    input,
+			expected: "INT,FLOAT,STRING\n1,1.25,a\n,,\n",
+		},
+		{
+			name:     "delimiter",
+			input:    input,
+			configs:  []csv.ToConfigFunc{csv.WriteDelimiter(';')},
+			expected: "INT;FLOAT;STRING\n1;1.25;a\n;;\n",
+		},
+		{
+			name:     "null representation",
+			input:    input,
+			configs:  []csv.ToConfigFunc{csv.NullRep("NA")},
+			expected: "INT,FLOAT,STRING\n1,1.25,a\nNA,NA,NA\n",
+		},
+		{
+			name:     "no header",
+			input:    input,
+			configs:  []csv.ToConfigFunc{csv.Header(false)},
+			expected: "1,1.25,a\n,,\n",
+		},
+		{
+			name:     "quote all",
+			input:    input,
+			configs:  []csv.ToConfigFunc{csv.QuoteAll(true)},
+			expected: "\"INT\",\"FLOAT\",\"STRING\"\n\"1\",\"1.25\",\"a\"\n,,\n",
+		},
+		{
+			name:     "float format",
+			input:    input,
+			configs:  []csv.ToConfigFunc{csv.FloatFormat('e', 2)},
+			expected: "INT,FLOAT,STRING\n1,1.25e+00,a\n,,\n",
+		},
+		{
+			name:     "quoting when needed",
+			input:    qframe.New(map[string]interface{}{"COL;1": []*string{&quoted, &empty, &a}}),
+			configs:  []csv.ToConfigFunc{csv.WriteDelimiter(';')},
+			expected: "\"COL;1\"\n\"say \"\"hi\"\"\"\n\na\n",
+		},
+		{
+			name:     "quoting leading white space",
+			input:    qframe.New(map[string]interface{}{"COL": []string{" a", "\va", "\u00a0a", "a b"}}),
+			expected: "COL\n\" a\"\n\"\va\"\n\"\u00a0a\"\na b\n",
+		},
+	}
 
-	jsonDf := qframe.ReadJSON(buf, config...)
-	assertNotErr(t, jsonDf.Err)
-	assertEquals(t, originalDf, jsonDf)
+	for _, tc := range table {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			assertNotErr(t, tc.input.ToCSV(buf, tc.configs...))
+			if buf.String() != tc.expected {
+				t.Errorf("Not equal: %q ||| %q", buf.String(), tc.expected)
+			}
+		})
+	}
 }
 
-func TestQFrame_ToJSONNaN(t *testing.T) {
+func TestQFrame_ToCSVErrors(t *testing.T) {
+	in := qframe.New(map[string]interface{}{"COL1": []int{1}})
+	assertErr(t, in.ToCSV(new(bytes.Buffer), csv.WriteDelimiter('"')), "invalid delimiter")
+	assertErr(t, in.ToCSV(new(bytes.Buffer), csv.FloatFormat('z', 2)), "invalid float format")
+}
+
+func TestQFrame_ToCSVRoundTrip(t *testing.T) {
+	size := 10000
+	ints, strs := make([]*int, size), make([]*string, size)
+	for i := 0; i < size; i++ {
+		if i%3 != 0 {
+			s := fmt.Sprintf("row, %d", i)
+			ints[i], strs[i] = intPtr(i), &s
+		}
+	}
+
+	in := qframe.New(map[string]interface{}{"INT": ints, "STRING": strs})
 	buf := new(bytes.Buffer)
+	assertNotErr(t, in.ToCSV(buf, csv.NullRep("NA")))
 
-	// Test the special case NaN, this can currently be encoded but not
-	// decoded by the JSON parsers.
-	data := map[string]interface{}{"FLOAT1": []float64{1.5, math.NaN()}}
-	originalDf := qframe.New(data)
-	assertNotErr(t, originalDf.Err)
+	out := qframe.ReadCSV(buf, csv.NullValues([]string{"NA"}))
+	assertEquals(t, in, out)
+}
 
-	err := originalDf.ToJSON(buf)
+func TestQFrame_CompressedCSV(t *testing.T) {
+	in := qframe.New(map[string]interface{}{"COL1": []int{1, 2}, "COL2": []string{"a", "b"}})
+
+	buf := new(bytes.Buffer)
+	assertNotErr(t, in.ToCSV(buf, csv.Gzip(gzip.BestSpeed)))
+	assertTrue(t, bytes.HasPrefix(buf.Bytes(), []byte{0x1f, 0x8b}))
+	assertEquals(t, in, qframe.ReadCSV(bytes.NewReader(buf.Bytes())))
+
+	rows := 0
+	err := qframe.ReadCSVChunks(bytes.NewReader(buf.Bytes()), 1, func(qf qframe.QFrame) error {
+		rows += qf.Len()
+		return nil
+	})
 	assertNotErr(t, err)
-	expected := `[{"FLOAT1":1.5},{"FLOAT1":null}]`
-	if buf.String() != expected {
-		t.Errorf("Not equal: %s ||| %s", buf.String(), expected)
-	}
+	assertTrue(t, rows == 2)
+
+	// "COL1,COL2\n1,a\n2,b\n" compressed with bzip2
+	bzipped := []byte{
+		0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x79, 0x52,
+		0x17, 0xee, 0x00, 0x00, 0x05, 0x5d, 0x00, 0x00, 0x10, 0x00, 0x04, 0x30,
+		0x00, 0x08, 0x04, 0xb0, 0x00, 0x20, 0x00, 0x31, 0x06, 0x4c, 0x40, 0x94,
+		0x0d, 0x1a, 0x5f, 0x34, 0x5f, 0x29, 0x93, 0x58, 0xca, 0x3c, 0x5d, 0xc9,
+		0x14, 0xe1, 0x42, 0x41, 0xe5, 0x48, 0x5f, 0xb8}
+	assertEquals(t, in, qframe.ReadCSV(bytes.NewReader(bzipped)))
+
+	// Uncompressed input starting with the bzip2 magic bytes
+	out := qframe.ReadCSV(strings.NewReader("BZhours,x\n1,2\n"))
+	assertEquals(t, qframe.New(map[string]interface{}{"BZhours": []int{1}, "x": []int{2}}, newqf.ColumnOrder("BZhours", "x")), out)
+	out = qframe.ReadCSV(strings.NewReader("BZh9\n1\n"))
+	assertEquals(t, qframe.New(map[string]interface{}{"BZh9": []int{1}}), out)
+
+	err = in.ToCSV(new(bytes.Buffer), csv.Gzip(42))
+	assertErr(t, err, "gzip")
 }
 
-func TestQFrame_ToJSONInt(t *testing.T) {
-	// The ints should not have decimals when turned into JSON
-	data := map[string]interface{}{"INT": []int{1, 2}}
-	originalDf := qframe.New(data)
-	assertNotErr(t, originalDf.Err)
+func TestQFrame_CompressedJSON(t *testing.T) {
+	in := qframe.New(map[string]interface{}{"COL1": []float64{1.5, 2}, "COL2": []string{"a", "b"}})
 
 	buf := new(bytes.Buffer)
-	err := originalDf.ToJSON(buf)
+	assertNotErr(t, in.ToJSON(buf, qfjson.Gzip(gzip.DefaultCompression)))
+	assertTrue(t, bytes.HasPrefix(buf.Bytes(), []byte{0x1f, 0x8b}))
+	assertEquals(t, in, qframe.ReadJSON(buf))
+}
+
+func TestQFrame_ReadArrow(t *testing.T) {
+	foo, bar := "foo", "bar"
+	nan := math.NaN()
+	table := []struct {
+		file     string
+		expected map[string]interface{}
+	}{
+		{file: "bool.bin", expected: map[string]interface{}{"f0": []bool{true, false, true}}},
+		{file: "float.bin", expected: map[string]interface{}{"f0": []float64{1.5, 2.5, nan}}},
+		{file: "int.bin", expected: map[string]interface{}{"f0": []int{1, 2, 3}}},
+		{file: "string.bin", expected: map[string]interface{}{"f0": []*string{&foo, &bar, nil}}},
+		{file: "mixed.bin", expected: map[string]interface{}{
+			"f0": []int{1, 2, 3},
+			"f1": []float64{1.5, 2.5, nan},
+			"f2": []bool{true, false, true},
+			"f3": []*string{&foo, &bar, nil}}},
+	}
+
+	for _, tc := range table {
+		t.Run(tc.file, func(t *testing.T) {
+			f, err := os.Open("arrow/" + tc.file)
+			assertNotErr(t, err)
+			defer f.Close()
+
+			out := qframe.ReadArrow(f)
+			assertNotErr(t, out.Err)
+			assertEquals(t, qframe.New(tc.expected), out)
+		})
+	}
+}
+
+func TestQFrame_ToArrow(t *testing.T) {
+	a, b, c := "a", "b", "c"
+	loc := time.FixedZone("", 2*60*60)
+	in := qframe.New(map[string]interface{}{
+		"INT":    []*int{intPtr(1), nil, intPtr(-3), intPtr(4)},
+		"FLOAT":  []float64{1.5, math.NaN(), -3.25, 4},
+		"BOOL":   []*bool{boolPtr(true), boolPtr(false), nil, boolPtr(true)},
+		"STRING": []*string{&a, nil, new(string), &c},
+		"ENUM":   []*string{&c, &a, nil, &b},
+		"TIME":   []*time.Time{timePtr(date(2018, 1, 2, 3).In(loc)), nil, timePtr(date(2018, 1, 2, 4).In(loc)), nil},
+	}, newqf.Enums(map[string][]string{"ENUM": {"c", "b", "a"}}),
+		newqf.ColumnOrder("STRING", "INT", "FLOAT", "BOOL", "ENUM", "TIME"))
+
+	for _, qf := range []qframe.QFrame{in, in.Sort(qframe.Order{Column: "INT"}), in.Filter(qframe.Filter{Column: "INT", Comparator: ">", Arg: 1}), in.Slice(0, 0)} {
+		buf := new(bytes.Buffer)
+		assertNotErr(t, qf.ToArrow(buf))
+		assertTrue(t, buf.Len()%8 == 0)
+
+		out := qframe.ReadArrow(buf)
+		assertNotErr(t, out.Err)
+		assertEquals(t, qf, out)
+		assertTrue(t, reflect.DeepEqual(out.ColumnNames(), qf.ColumnNames()))
+		view := out.MustTimeView("TIME")
+		for i := 0; i < view.Len(); i++ {
+			if !view.IsNull(i) {
+				_, offset := view.ItemAt(i).Zone()
+				assertTrue(t, offset == 2*60*60)
+			}
+		}
+	}
+}
+
+func TestQFrame_ReadArrowErrors(t *testing.T) {
+	data, err := ioutil.ReadFile("arrow/mixed.bin")
 	assertNotErr(t, err)
-	if buf.String() != `[{"INT":1},{"INT":2}]` {
-		t.Errorf("Unexpected JSON string: %s", buf.String())
+
+	out := qframe.ReadArrow(bytes.NewReader(data[:100]))
+	assertErr(t, out.Err, "ReadArrow")
+
+	out = qframe.ReadArrow(bytes.NewReader(nil))
+	assertErr(t, out.Err, "no schema")
+
+	corrupt := make([]byte, len(data))
+	copy(corrupt, data)
+	corrupt[4] = 0xFF
+	out = qframe.ReadArrow(bytes.NewReader(corrupt))
+	assertErr(t, out.Err, "invalid arrow data")
+
+	// Lengths read from the stream are not trusted to allocate memory up front
+	copy(corrupt, data)
+	binary.LittleEndian.PutUint64(corrupt[308:], 1<<40)
+	out = qframe.ReadArrow(bytes.NewReader(corrupt))
+	assertErr(t, out.Err, "unexpected EOF")
+
+	out = qframe.ReadArrow(bytes.NewReader([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x7F}))
+	assertErr(t, out.Err, "unexpected EOF")
+
+	// Field node and buffer lengths of the record batch must match the number of rows
+	copy(corrupt, data)
+	binary.LittleEndian.PutUint64(corrupt[508:], 0)
+	out = qframe.ReadArrow(bytes.NewReader(corrupt))
+	assertErr(t, out.Err, "field node length 0 does not match record batch length 3")
+
+	copy(corrupt, data)
+	binary.LittleEndian.PutUint64(corrupt[412:], 8)
+	out = qframe.ReadArrow(bytes.NewReader(corrupt))
+	assertErr(t, out.Err, "buffer too small: 8 bytes, expected 24")
+
+	copy(corrupt, data)
+	binary.LittleEndian.PutUint64(corrupt[476:], 8)
+	out = qframe.ReadArrow(bytes.NewReader(corrupt))
+	assertErr(t, out.Err, "buffer too small: 8 bytes, expected 16")
+}
+
+func TestQFrame_ToParquet(t *testing.T) {
+	a, b, c := "a", "b", "c"
+	in := qframe.New(map[string]interface{}{
+		"INT":    []*int{intPtr(1), nil, intPtr(-3), intPtr(4)},
+		"FLOAT":  []float64{1.5, math.NaN(), -3.25, 4},
+		"BOOL":   []*bool{boolPtr(true), boolPtr(false), nil, boolPtr(true)},
+		"STRING": []*string{&a, nil, new(string), &c},
+		"ENUM":   []*string{&c, &a, nil, &b},
+		"TIME":   []*time.Time{timePtr(date(2018, 1, 2, 3)), nil, timePtr(date(2018, 1, 2, 4)), nil},
+	}, newqf.Enums(map[string][]string{"ENUM": {"c", "b", "d", "a"}}),
+		newqf.ColumnOrder("STRING", "INT", "FLOAT", "BOOL", "ENUM", "TIME"))
+
+	frames := []qframe.QFrame{
+		in,
+		in.Sort(qframe.Order{Column: "INT"}),
+		in.Filter(qframe.Filter{Column: "INT", Comparator: ">", Arg: 1}),
+		in.Slice(0, 0)}
+	configs := [][]parquet.ToConfigFunc{
+		nil,
+		{parquet.Uncompressed()},
+		{parquet.Gzip(gzip.BestCompression)},
+		{parquet.RowGroupSize(3)},
+		{parquet.RowGroupSize(1), parquet.Uncompressed()}}
+	for _, qf := range frames {
+		for i, conf := range configs {
+			t.Run(fmt.Sprintf("%d/%d", qf.Len(), i), func(t *testing.T) {
+				buf := new(bytes.Buffer)
+				assertNotErr(t, qf.ToParquet(buf, conf...))
+
+				out := qframe.ReadParquet(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+				assertNotErr(t, out.Err)
+				assertEquals(t, qf, out)
+				assertTrue(t, reflect.DeepEqual(out.ColumnNames(), qf.ColumnNames()))
+
+				// Enum values keep their order, unused values included
+				filtered := out.Filter(qframe.Filter{Column: "ENUM", Comparator: "<", Arg: "d"})
+				assertEquals(t, qf.Filter(qframe.Filter{Column: "ENUM", Comparator: "<", Arg: "d"}), filtered)
+			})
+		}
 	}
 }
 
-func TestQFrame_FilterEnum(t *testing.T) {
-	a, b, c, d, e := "a", "b", "c", "d", "e"
-	enums := newqf.Enums(map[string][]string{"COL1": {"a", "b", "c", "d", "e"}})
+func TestQFrame_ReadParquetOptions(t *testing.T) {
 	in := qframe.New(map[string]interface{}{
-		"COL1": []*string{&b, &c, &a, nil, &e, &d, nil}}, enums)
+		"A": []int{1, 2, 3, 4, 5},
+		"B": []string{"a", "b", "c", "d", "e"},
+		"C": []float64{1.5, 2.5, 3.5, 4.5, 5.5},
+	}, newqf.ColumnOrder("A", "B", "C"))
+
+	buf := new(bytes.Buffer)
+	assertNotErr(t, in.ToParquet(buf, parquet.RowGroupSize(2)))
+	reader := bytes.NewReader(buf.Bytes())
+	size := int64(buf.Len())
 
 	table := []struct {
-		clause   qframe.FilterClause
-		expected map[string]interface{}
+		name     string
+		configs  []parquet.ConfigFunc
+		expected qframe.QFrame
 	}{
 		{
-			qframe.Filter{Column: "COL1", Comparator: ">", Arg: "b"},
-			map[string]interface{}{"COL1": []*string{&c, &e, &d}},
-		},
+			name:     "columns",
+			configs:  []parquet.ConfigFunc{parquet.Columns("C", "A")},
+			expected: in.Select("C", "A")},
 		{
-			qframe.Filter{Column: "COL1", Comparator: "in", Arg: []string{"a", "b"}},
-			map[string]interface{}{"COL1": []*string{&b, &a}},
-		},
+			name:     "row groups",
+			configs:  []parquet.ConfigFunc{parquet.RowGroups(2, 0)},
+			expected: qframe.New(map[string]interface{}{"A": []int{5, 1, 2}, "B": []string{"e", "a", "b"}, "C": []float64{5.5, 1.5, 2.5}}, newqf.ColumnOrder("A", "B", "C"))},
+		{
+			name:     "columns and row groups",
+			configs:  []parquet.ConfigFunc{parquet.Columns("B"), parquet.RowGroups(1)},
+			expected: qframe.New(map[string]interface{}{"B": []string{"c", "d"}})},
 	}
 
-	for i, tc := range table {
-		t.Run(fmt.Sprintf("Filter enum %d", i), func(t *testing.T) {
-			expected := qframe.New(tc.expected, enums)
-			out := in.Filter(tc.clause)
-			assertEquals(t, expected, out)
+	for _, tc := range table {
+		t.Run(tc.name, func(t *testing.T) {
+			out := qframe.ReadParquet(reader, size, tc.configs...)
+			assertNotErr(t, out.Err)
+			assertEquals(t, tc.expected, out)
+			assertTrue(t, reflect.DeepEqual(out.ColumnNames(), tc.expected.ColumnNames()))
 		})
 	}
 }
 
-func TestQFrame_FilterString(t *testing.T) {
-	a, b, c, d, e := "a", "b", "c", "d", "e"
-	withNil := map[string]interface{}{"COL1": []*string{&b, &c, &a, nil, &e, &d, nil}}
+func TestQFrame_ReadParquetErrors(t *testing.T) {
+	in := qframe.New(map[string]interface{}{"A": []int{1, 2, 3}, "B": []string{"a", "b", "c"}})
+	buf := new(bytes.Buffer)
+	assertNotErr(t, in.ToParquet(buf))
+	data := buf.Bytes()
 
 	table := []struct {
-		input    map[string]interface{}
-		clause   qframe.FilterClause
-		expected map[string]interface{}
+		name    string
+		data    []byte
+		configs []parquet.ConfigFunc
+		err     string
 	}{
-		{
-			withNil,
-			qframe.Filter{Column: "COL1", Comparator: ">", Arg: "b"},
-			map[string]interface{}{"COL1": []*string{&c, &e, &d}},
-		},
-		{
-			withNil,
-			qframe.Filter{Column: "COL1", Comparator: "<", Arg: "b"},
-			map[string]interface{}{"COL1": []*string{&a}},
-		},
-		{
-			withNil,
-			qframe.Filter{Column: "COL1", Comparator: "!=", Arg: "a"},
-			map[string]interface{}{"COL1": []*string{&b, &c, nil, &e, &d, nil}},
-		},
-		{
-			withNil,
-			qframe.Filter{Column: "COL1", Comparator: "like", Arg: "b"},
-			map[string]interface{}{"COL1": []*string{&b}},
-		},
-		{
-			withNil,
-			qframe.Filter{Column: "COL1", Comparator: "in", Arg: []string{"a", "b"}},
-			map[string]interface{}{"COL1": []*string{&b, &a}},
-		},
+		{name: "empty", data: nil, err: "file too small"},
+		{name: "magic", data: append([]byte("PAR2"), data[4:]...), err: "not a parquet file"},
+		{name: "truncated", data: data[len(data)-20:], err: "ReadParquet"},
+		{name: "unknown column", data: data, configs: []parquet.ConfigFunc{parquet.Columns("A", "X")}, err: "unknown column: X"},
+		{name: "row group", data: data, configs: []parquet.ConfigFunc{parquet.RowGroups(1)}, err: "row group out of range: 1"},
 	}
 
-	for i, tc := range table {
-		t.Run(fmt.Sprintf("Filter string %d", i), func(t *testing.T) {
-			in := qframe.New(tc.input)
-			expected := qframe.New(tc.expected)
-			out := in.Filter(tc.clause)
-			assertEquals(t, expected, out)
+	for _, tc := range table {
+		t.Run(tc.name, func(t *testing.T) {
+			out := qframe.ReadParquet(bytes.NewReader(tc.data), int64(len(tc.data)), tc.configs...)
+			assertErr(t, out.Err, tc.err)
 		})
 	}
 }
 
-func TestQFrame_LikeFilterString(t *testing.T) {
-	col1 := []string{"ABC", "AbC", "DEF", "ABCDEF", "abcdef", "FFF", "abc$def", "défåäöΦ"}
+func TestQFrame_ToParquetErrors(t *testing.T) {
+	in := qframe.New(map[string]interface{}{"A": []int{1, 2, 3}})
+	err := in.ToParquet(new(bytes.Buffer), parquet.Gzip(42))
+	assertErr(t, err, "invalid compression level")
 
-	// Add a couple of fields to be able to verify functionality for high cardinality enums
-	for i := 0; i < 200; i++ {
-		col1 = append(col1, fmt.Sprintf("foo%dbar", i))
+	err = in.ToParquet(new(bytes.Buffer), parquet.RowGroupSize(-1))
+	assertErr(t, err, "invalid row group size")
+}
+
+func TestQFrame_WriteToReadFrom(t *testing.T) {
+	a, b, c := "a", "b", "c"
+	loc := time.FixedZone("", 2*60*60)
+	in := qframe.New(map[string]interface{}{
+		"INT":    []*int{intPtr(1), nil, intPtr(-3), intPtr(4)},
+		"FLOAT":  []float64{1.5, math.NaN(), -3.25, 4},
+		"BOOL":   []*bool{boolPtr(true), boolPtr(false), nil, boolPtr(true)},
+		"STRING": []*string{&a, nil, new(string), &c},
+		"ENUM":   []*string{&c, &a, nil, &b},
+		"TIME":   []*time.Time{timePtr(date(2018, 1, 2, 3).In(loc)), nil, timePtr(date(2018, 1, 2, 4).In(loc)), nil},
+	}, newqf.Enums(map[string][]string{"ENUM": {"c", "b", "a"}}),
+		newqf.ColumnOrder("STRING", "INT", "FLOAT", "BOOL", "ENUM", "TIME"))
+
+	frames := []qframe.QFrame{
+		in,
+		in.Sort(qframe.Order{Column: "INT"}),
+		in.Filter(qframe.Filter{Column: "INT", Comparator: ">", Arg: 1}),
+		in.Slice(0, 0),
+		qframe.New(map[string]interface{}{}),
+		// Larger than the chunks in which streams are read
+		qframe.New(map[string]interface{}{"FLOAT": make([]float64, 300001)})}
+	for i, qf := range frames {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			n, err := qf.WriteTo(buf)
+			assertNotErr(t, err)
+			assertTrue(t, n == int64(buf.Len()))
+
+			out := qframe.ReadFrom(buf)
+			assertNotErr(t, out.Err)
+			assertEquals(t, qf, out)
+			assertTrue(t, reflect.DeepEqual(out.ColumnNames(), qf.ColumnNames()))
+			if out.Contains("TIME") {
+				view := out.MustTimeView("TIME")
+				for i := 0; i < view.Len(); i++ {
+					if !view.IsNull(i) {
+						_, offset := view.ItemAt(i).Zone()
+						assertTrue(t, offset == 2*60*60)
+					}
+				}
+			}
+		})
 	}
+}
 
-	data := map[string]interface{}{"COL1": col1}
-	for _, enums := range []map[string][]string{{}, {"COL1": nil}} {
-		table := []struct {
-			comparator string
+func TestQFrame_MarshalBinary(t *testing.T) {
+	in := qframe.New(map[string]interface{}{
+		"INT":    []int{1, 2, 3},
+		"STRING": []string{"a", "bb", "ccc"},
+		"TIME":   []time.Time{date(2018, 1, 2, 3), date(2018, 1, 2, 4), date(2018, 1, 2, 5)},
+	})
+
+	data, err := in.MarshalBinary()
+	assertNotErr(t, err)
+
+	var out qframe.QFrame
+	assertNotErr(t, out.UnmarshalBinary(data))
+	assertEquals(t, in, out)
+
+	type cached struct {
+		Name  string
+		Frame qframe.QFrame
+	}
+
+	buf := new(bytes.Buffer)
+	assertNotErr(t, gob.NewEncoder(buf).Encode(cached{Name: "foo", Frame: in.Sort(qframe.Order{Column: "INT", Reverse: true})}))
+
+	var decoded cached
+	assertNotErr(t, gob.NewDecoder(buf).Decode(&decoded))
+	assertTrue(t, decoded.Name == "foo")
+	assertEquals(t, in.Sort(qframe.Order{Column: "INT", Reverse: true}), decoded.Frame)
+}
+
+func TestQFrame_ReadFromErrors(t *testing.T) {
+	in := qframe.New(map[string]interface{}{"INT": []int{1, 2, 3}, "ENUM": []string{"a", "b", "a"}},
+		newqf.Enums(map[string][]string{"ENUM": nil}))
+	data, err := in.MarshalBinary()
+	assertNotErr(t, err)
+
+	out := qframe.ReadFrom(bytes.NewReader(data[:len(data)-10]))
+	assertErr(t, out.Err, "unexpected EOF")
+
+	out = qframe.ReadFrom(bytes.NewReader([]byte("not a frame at all, really not")))
+	assertErr(t, out.Err, "not a qframe binary file")
+
+	corrupt := make([]byte, len(data))
+	copy(corrupt, data)
+	corrupt[4] = 42
+	out = qframe.ReadFrom(bytes.NewReader(corrupt))
+	assertErr(t, out.Err, "unsupported version")
+
+	// Huge buffer lengths must not be trusted for allocation
+	copy(corrupt, data)
+	copy(corrupt[24:32], bytes.Repeat([]byte{1}, 8))
+	out = qframe.ReadFrom(bytes.NewReader(corrupt))
+	assertErr(t, out.Err, "unexpected EOF")
+
+	var qf qframe.QFrame
+	assertErr(t, qf.UnmarshalBinary(corrupt), "unexpected EOF")
+	assertErr(t, qf.UnmarshalBinary(nil), "UnmarshalBinary")
+
+	// The row count is written in host byte order, 2^32 rows do not fit the index
+	copy(corrupt, data)
+	if data[8] == 3 {
+		binary.LittleEndian.PutUint64(corrupt[8:], 1<<32)
+	} else {
+		binary.BigEndian.PutUint64(corrupt[8:], 1<<32)
+	}
+	out = qframe.ReadFrom(bytes.NewReader(corrupt))
+	assertErr(t, out.Err, "invalid number of rows: 4294967296")
+
+	copy(corrupt, data)
+	copy(corrupt[16:24], bytes.Repeat([]byte{0xFF}, 8))
+	out = qframe.ReadFrom(bytes.NewReader(corrupt))
+	assertErr(t, out.Err, "invalid number of columns")
+
+	copy(corrupt[16:24], bytes.Repeat([]byte{1}, 8))
+	assertErr(t, qf.UnmarshalBinary(corrupt), "invalid number of columns")
+
+	_, err = qframe.QFrame{Err: fmt.Errorf("foo")}.MarshalBinary()
+	assertErr(t, err, "foo")
+}
+
+func TestQFrame_Open(t *testing.T) {
+	a, b, c := "a", "b", "c"
+	in := qframe.New(map[string]interface{}{
+		"INT":    []*int{intPtr(1), nil, intPtr(-3), intPtr(4)},
+		"FLOAT":  []float64{1.5, math.NaN(), -3.25, 4},
+		"BOOL":   []*bool{boolPtr(true), boolPtr(false), nil, boolPtr(true)},
+		"STRING": []*string{&a, nil, new(string), &c},
+		"ENUM":   []*string{&c, &a, nil, &b},
+		"TIME":   []*time.Time{timePtr(date(2018, 1, 2, 3)), nil, timePtr(date(2018, 1, 2, 4)), nil},
+	}, newqf.Enums(map[string][]string{"ENUM": {"c", "b", "a"}}),
+		newqf.ColumnOrder("STRING", "INT", "FLOAT", "BOOL", "ENUM", "TIME"))
+
+	dir, err := ioutil.TempDir("", "qframe")
+	assertNotErr(t, err)
+	defer os.RemoveAll(dir)
+
+	path := dir + "/frame.bin"
+	f, err := os.Create(path)
+	assertNotErr(t, err)
+	_, err = in.WriteTo(f)
+	assertNotErr(t, err)
+	assertNotErr(t, f.Close())
+
+	out := qframe.Open(path)
+	assertNotErr(t, out.Err)
+	assertEquals(t, in, out)
+
+	// Operations build new indices and columns without modifying the mapped data
+	sort := []qframe.Order{{Column: "STRING"}, {Column: "INT", Reverse: true}}
+	assertEquals(t, in.Sort(sort...), out.Sort(sort...))
+	filter := qframe.Filter{Column: "FLOAT", Comparator: ">", Arg: 0.0}
+	assertEquals(t, in.Filter(filter), out.Filter(filter))
+	assertEquals(t, in.Distinct().Sort(sort...), out.Distinct().Sort(sort...))
+	assertEquals(t,
+		in.GroupBy(groupby.Columns("ENUM")).Aggregate(qframe.Aggregation{Fn: "sum", Column: "INT"}),
+		out.GroupBy(groupby.Columns("ENUM")).Aggregate(qframe.Aggregation{Fn: "sum", Column: "INT"}))
+	assertEquals(t,
+		in.Apply(qframe.Instruction{Fn: strings.ToUpper, DstCol: "STRING", SrcCol1: "STRING"}),
+		out.Apply(qframe.Instruction{Fn: strings.ToUpper, DstCol: "STRING", SrcCol1: "STRING"}))
+
+	inCSV, outCSV := new(bytes.Buffer), new(bytes.Buffer)
+	assertNotErr(t, in.ToCSV(inCSV))
+	assertNotErr(t, out.ToCSV(outCSV))
+	assertTrue(t, inCSV.String() == outCSV.String())
+
+	assertNotErr(t, out.Close())
+	assertNotErr(t, out.Close())
+	assertNotErr(t, in.Close())
+}
+
+func TestQFrame_OpenErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "qframe")
+	assertNotErr(t, err)
+	defer os.RemoveAll(dir)
+
+	out := qframe.Open(dir + "/missing.bin")
+	assertErr(t, out.Err, "Open")
+
+	path := dir + "/small.bin"
+	assertNotErr(t, ioutil.WriteFile(path, []byte("QFRB"), 0644))
+	out = qframe.Open(path)
+	assertErr(t, out.Err, "file too small")
+
+	data, err := qframe.New(map[string]interface{}{"INT": []int{1, 2, 3}}).MarshalBinary()
+	assertNotErr(t, err)
+	assertNotErr(t, ioutil.WriteFile(path, data[:len(data)-8], 0644))
+	out = qframe.Open(path)
+	assertErr(t, out.Err, "unexpected EOF")
+}
+
+func TestQFrame_ReadNDJSON(t *testing.T) {
+	a, b, obj := "a", "b", `{"x":[1,2]}`
+	input := `{"INT": 1, "FLOAT": 1, "BOOL": true, "STRING": "a", "NESTED": {"x":[1,2]}}
+{"INT": 2, "FLOAT": 2.5, "BOOL": null, "STRING": "b\u00e5", "NULL": null}
+
+{"FLOAT": -3e2, "BOOL": false, "STRING": null, "LATE": "b"}
+`
+	out := qframe.ReadNDJSON(strings.NewReader(input))
+	assertNotErr(t, out.Err)
+
+	aa := "bå"
+	expected := qframe.New(map[string]interface{}{
+		"INT":    []*int{intPtr(1), intPtr(2), nil},
+		"FLOAT":  []float64{1, 2.5, -300},
+		"BOOL":   []*bool{boolPtr(true), nil, boolPtr(false)},
+		"STRING": []*string{&a, &aa, nil},
+		"NESTED": []*string{&obj, nil, nil},
+		"NULL":   []*string{nil, nil, nil},
+		"LATE":   []*string{nil, nil, &b},
+	}, newqf.ColumnOrder("INT", "FLOAT", "BOOL", "STRING", "NESTED", "NULL", "LATE"))
+	assertEquals(t, expected, out)
+	assertTrue(t, reflect.DeepEqual(expected.ColumnNames(), out.ColumnNames()))
+}
+
+func TestQFrame_ReadNDJSONTypes(t *testing.T) {
+	input := `{"A": 1, "B": "x", "C": "2018-01-02 03", "D": 12, "E": "y"}
+{"A": 2, "C": null, "D": true, "E": "y"}
+`
+	out := qframe.ReadNDJSON(strings.NewReader(input),
+		ndjson.Types(map[string]string{"A": "float", "B": "enum", "C": "time", "D": "string", "F": "int"}),
+		ndjson.EnumValues(map[string][]string{"B": {"z", "x"}}),
+		ndjson.TimeLayout("2006-01-02 15"),
+		ndjson.AutoEnum(5))
+	assertNotErr(t, out.Err)
+
+	x, y, twelve, true_ := "x", "y", "12", "true"
+	expected := qframe.New(map[string]interface{}{
+		"A": []float64{1, 2},
+		"B": []*string{&x, nil},
+		"C": []*time.Time{timePtr(date(2018, 1, 2, 3)), nil},
+		"D": []*string{&twelve, &true_},
+		"E": []*string{&y, &y},
+		"F": []*int{nil, nil},
+	}, newqf.ColumnOrder("A", "B", "C", "D", "E", "F"),
+		newqf.Enums(map[string][]string{"B": {"z", "x"}, "E": nil}))
+	assertEquals(t, expected, out)
+	assertTrue(t, reflect.DeepEqual(expected.ColumnNames(), out.ColumnNames()))
+	assertTrue(t, out.ColumnTypeMap()["E"] == types.Enum)
+}
+
+func TestQFrame_ReadNDJSONErrors(t *testing.T) {
+	table := []struct {
+		input   string
+		configs []ndjson.ConfigFunc
+		err     string
+	}{
+		{input: `[1, 2]`, err: "expected JSON object on line 1"},
+		{input: "{\"A\": 1}\n{\"A\": \"x\"}", err: "wrong type for column A, line 2"},
+		{input: `{"A": "x"}`, configs: []ndjson.ConfigFunc{ndjson.Types(map[string]string{"A": "int"})}, err: "wrong type for column A, line 1"},
+		{input: `{"A": 1.5}`, configs: []ndjson.ConfigFunc{ndjson.Types(map[string]string{"A": "int"})}, err: "wrong type for column A, line 1"},
+		{input: `{"A": 1, "A": 2}`, err: "duplicate key A on line 1"},
+		{input: "{\"A\": 1}\n\n{\"B\": 2}\n{\"A\": \"x\"}", err: "wrong type for column A, line 4"},
+		{input: "{\"A\": 1}\n{\"A\": 2,\n\"B\": 3, \"A\": 4}", err: "duplicate key A on line 3"},
+		{input: "{\"A\": 1} {\"A\": 2}\n", err: "expected one JSON object per line, line 1"},
+		{input: "{\"A\": 1}\n\n[1]", err: "expected JSON object on line 3"},
+		{input: `{"A": 1`, err: "ReadNDJSON"},
+		{input: `{"A": "2018"}`, configs: []ndjson.ConfigFunc{ndjson.Types(map[string]string{"A": "time"})}, err: "ReadNDJSON"},
+		{input: `{"A": "x"}`, configs: []ndjson.ConfigFunc{ndjson.Types(map[string]string{"A": "foo"})}, err: "unsupported type for column A"},
+		{input: `{"A": "x"}`, configs: []ndjson.ConfigFunc{ndjson.Types(map[string]string{"A": "enum"}), ndjson.EnumValues(map[string][]string{"A": {"y"}})}, err: "unknown enum value"},
+	}
+
+	for _, tc := range table {
+		t.Run(tc.input, func(t *testing.T) {
+			out := qframe.ReadNDJSON(strings.NewReader(tc.input), tc.configs...)
+			assertErr(t, out.Err, tc.err)
+		})
+	}
+}
+
+func TestQFrame_ToNDJSON(t *testing.T) {
+	a, b, c := "a", "b\"\n", "c"
+	in := qframe.New(map[string]interface{}{
+		"INT":    []*int{intPtr(1), nil, intPtr(-3)},
+		"FLOAT":  []float64{1, math.NaN(), -3.25},
+		"BOOL":   []*bool{boolPtr(true), boolPtr(false), nil},
+		"STRING": []*string{&a, nil, &b},
+		"ENUM":   []*string{&c, &a, nil},
+		"TIME":   []*time.Time{timePtr(date(2018, 1, 2, 3)), nil, timePtr(date(2018, 1, 2, 4))},
+	}, newqf.Enums(map[string][]string{"ENUM": {"c", "a"}}),
+		newqf.ColumnOrder("STRING", "INT", "FLOAT", "BOOL", "ENUM", "TIME"))
+
+	buf := new(bytes.Buffer)
+	assertNotErr(t, in.ToNDJSON(buf))
+	lines := strings.Split(buf.String(), "\n")
+	assertTrue(t, len(lines) == 4 && lines[3] == "")
+	assertTrue(t, strings.Contains(lines[0], `"INT":1,"FLOAT":1.0,`))
+	assertTrue(t, lines[1] == `{"STRING":null,"INT":null,"FLOAT":null,"BOOL":false,"ENUM":"a","TIME":null}`)
+
+	// Int and float columns are inferred
+	out := qframe.ReadNDJSON(buf,
+		ndjson.Types(map[string]string{"ENUM": "enum", "TIME": "time"}),
+		ndjson.EnumValues(map[string][]string{"ENUM": {"c", "a"}}))
+	assertNotErr(t, out.Err)
+	assertEquals(t, in, out)
+
+	buf.Reset()
+	assertNotErr(t, in.Filter(qframe.Filter{Column: "INT", Comparator: ">", Arg: 100}).ToNDJSON(buf))
+	assertTrue(t, buf.Len() == 0)
+}
+
+func TestQFrame_ReadNDJSONCompressed(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := gzip.NewWriter(buf)
+	_, err := w.Write([]byte("{\"A\": 1}\n{\"A\": 2}\n"))
+	assertNotErr(t, err)
+	assertNotErr(t, w.Close())
+
+	out := qframe.ReadNDJSON(buf)
+	assertNotErr(t, out.Err)
+	assertEquals(t, qframe.New(map[string]interface{}{"A": []int{1, 2}}), out)
+}
+
+func TestQFrame_ToFromJSON(t *testing.T) {
+	config := []newqf.ConfigFunc{newqf.Enums(map[string][]string{"ENUM": {"aa", "bb"}})}
+	data := map[string]interface{}{
+		"STRING1": []string{"añ", "bö☺	"}, "FLOAT1": []float64{1.5, 2.5}, "BOOL1": []bool{true, false}, "ENUM": []string{"aa", "bb"}}
+	originalDf := qframe.New(data, config...)
+	assertNotErr(t, originalDf.Err)
+
+	buf := new(bytes.Buffer)
+	err := originalDf.ToJSON(buf)
+	assertNotErr(t, err)
+
+	jsonDf := qframe.ReadJSON(buf, config...)
+	assertNotErr(t, jsonDf.Err)
+	assertEquals(t, originalDf, jsonDf)
+}
+
+func TestQFrame_ToJSONNaN(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	// Test the special case NaN, this can currently be encoded but not
+	// decoded by the JSON parsers.
+	data := map[string]interface{}{"FLOAT1": []float64{1.5, math.NaN()}}
+	originalDf := qframe.New(data)
+	assertNotErr(t, originalDf.Err)
+
+	err := originalDf.ToJSON(buf)
+	assertNotErr(t, err)
+	expected := `[{"FLOAT1":1.5},{"FLOAT1":null}]`
+	if buf.String() != expected {
+		t.Errorf("Not equal: %s ||| %s", buf.String(), expected)
+	}
+}
+
+func TestQFrame_ToJSONInt(t *testing.T) {
+	// The ints should not have decimals when turned into JSON
+	data := map[string]interface{}{"INT": []int{1, 2}}
+	originalDf := qframe.New(data)
+	assertNotErr(t, originalDf.Err)
+
+	buf := new(bytes.Buffer)
+	err := originalDf.ToJSON(buf)
+	assertNotErr(t, err)
+	if buf.String() != `[{"INT":1},{"INT":2}]` {
+		t.Errorf("Unexpected JSON string: %s", buf.String())
+	}
+}
+
+func TestQFrame_ToJSONNullIntBool(t *testing.T) {
+	data := map[string]interface{}{"INT": []*int{intPtr(1), nil}, "BOOL": []*bool{nil, boolPtr(false)}}
+	originalDf := qframe.New(data)
+	assertNotErr(t, originalDf.Err)
+
+	buf := new(bytes.Buffer)
+	err := originalDf.ToJSON(buf)
+	assertNotErr(t, err)
+
+	var result []map[string]interface{}
+	assertNotErr(t, json.Unmarshal(buf.Bytes(), &result))
+	expected := []map[string]interface{}{{"INT": 1.0, "BOOL": nil}, {"INT": nil, "BOOL": false}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Not equal: %s ||| %v", buf.String(), expected)
+	}
+}
+
+func date(year, month, day, hour int) time.Time {
+	return time.Date(year, time.Month(month), day, hour, 0, 0, 0, time.UTC)
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestQFrame_FilterTime(t *testing.T) {
+	in := qframe.New(map[string]interface{}{
+		"COL1": []*time.Time{timePtr(date(2018, 1, 1, 0)), nil, timePtr(date(2018, 1, 3, 0)), timePtr(date(2018, 1, 2, 0))},
+		"COL2": []time.Time{date(2018, 1, 2, 0), date(2018, 1, 2, 0), date(2018, 1, 2, 0), date(2018, 1, 2, 0)}})
+
+	table := []struct {
+		clause   qframe.FilterClause
+		expected []*time.Time
+	}{
+		{
+			qframe.Filter{Column: "COL1", Comparator: ">", Arg: date(2018, 1, 1, 12)},
+			[]*time.Time{timePtr(date(2018, 1, 3, 0)), timePtr(date(2018, 1, 2, 0))},
+		},
+		{
+			qframe.Filter{Column: "COL1", Comparator: "<=", Arg: types.ColumnName("COL2")},
+			[]*time.Time{timePtr(date(2018, 1, 1, 0)), timePtr(date(2018, 1, 2, 0))},
+		},
+		{
+			qframe.Filter{Column: "COL1", Comparator: "in", Arg: []time.Time{date(2018, 1, 3, 0), date(2018, 1, 4, 0)}},
+			[]*time.Time{timePtr(date(2018, 1, 3, 0))},
+		},
+		{
+			qframe.Filter{Column: "COL1", Comparator: "isnull"},
+			[]*time.Time{nil},
+		},
+		{
+			qframe.Filter{Column: "COL1", Comparator: "!=", Arg: date(2018, 1, 1, 0)},
+			[]*time.Time{nil, timePtr(date(2018, 1, 3, 0)), timePtr(date(2018, 1, 2, 0))},
+		},
+	}
+
+	for i, tc := range table {
+		t.Run(fmt.Sprintf("Filter time %d", i), func(t *testing.T) {
+			expected := qframe.New(map[string]interface{}{"COL1": tc.expected})
+			out := in.Filter(tc.clause).Select("COL1")
+			assertEquals(t, expected, out)
+		})
+	}
+}
+
+func TestQFrame_SortTime(t *testing.T) {
+	in := qframe.New(map[string]interface{}{
+		"COL1": []*time.Time{timePtr(date(2018, 1, 3, 0)), nil, timePtr(date(2018, 1, 1, 0))}})
+	expected := qframe.New(map[string]interface{}{
+		"COL1": []*time.Time{nil, timePtr(date(2018, 1, 1, 0)), timePtr(date(2018, 1, 3, 0))}})
+
+	assertEquals(t, expected, in.Sort(qframe.Order{Column: "COL1"}))
+}
+
+func TestQFrame_AggregateGroupByTime(t *testing.T) {
+	in := qframe.New(map[string]interface{}{
+		"COL1": []time.Time{date(2018, 1, 1, 0), date(2018, 1, 2, 0), date(2018, 1, 1, 0)},
+		"COL2": []time.Time{date(2018, 2, 1, 0), date(2018, 2, 2, 0), date(2018, 2, 3, 0)}})
+	expected := qframe.New(map[string]interface{}{
+		"COL1": []time.Time{date(2018, 1, 1, 0), date(2018, 1, 2, 0)},
+		"COL2": []time.Time{date(2018, 2, 3, 0), date(2018, 2, 2, 0)}})
+
+	out := in.GroupBy(groupby.Columns("COL1")).Aggregate(qframe.Aggregation{Fn: "max", Column: "COL2"})
+	assertEquals(t, expected, out.Sort(qframe.Order{Column: "COL1"}))
+}
+
+func TestQFrame_ApplyTime(t *testing.T) {
+	in := qframe.New(map[string]interface{}{"COL1": []*time.Time{timePtr(date(2018, 3, 1, 0)), nil}})
+	expected := qframe.New(map[string]interface{}{"COL1": []*int{intPtr(3), nil}})
+
+	out := in.Apply(qframe.Instruction{Fn: func(t time.Time) int { return int(t.Month()) }, DstCol: "COL1", SrcCol1: "COL1"})
+	assertEquals(t, expected, out)
+}
+
+func TestQFrame_TimeLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	in := qframe.New(map[string]interface{}{"COL1": []time.Time{date(2018, 1, 1, 23).In(loc)}})
+
+	assertEquals(t, qframe.New(map[string]interface{}{"COL1": []int{2}}), in.Eval("COL1", qframe.Expr("day", col("COL1"))))
+}
+
+func TestQFrame_ToCSVTime(t *testing.T) {
+	in := qframe.New(map[string]interface{}{"COL1": []*time.Time{timePtr(time.Date(2018, 1, 2, 3, 4, 5, 6, time.UTC)), nil}})
+	buf := new(bytes.Buffer)
+	assertNotErr(t, in.ToCSV(buf))
+
+	expected := "COL1\n2018-01-02T03:04:05.000000006Z\n\n"
+	if buf.String() != expected {
+		t.Errorf("Not equal: %q ||| %q", buf.String(), expected)
+	}
+}
+
+func TestQFrame_ToJSONTime(t *testing.T) {
+	loc := time.FixedZone("", -5*60*60)
+	in := qframe.New(map[string]interface{}{"COL1": []*time.Time{timePtr(date(2018, 1, 2, 3).In(loc)), nil}})
+	buf := new(bytes.Buffer)
+	assertNotErr(t, in.ToJSON(buf))
+
+	expected := `[{"COL1":"2018-01-01T22:00:00-05:00"},{"COL1":null}]`
+	if buf.String() != expected {
+		t.Errorf("Not equal: %s ||| %s", buf.String(), expected)
+	}
+}
+
+func TestQFrame_ReadCSVTime(t *testing.T) {
+	loc := time.FixedZone("", 60*60)
+	table := []struct {
+		name     string
+		input    string
+		conf     []csv.ConfigFunc
+		expected []*time.Time
+		err      string
+	}{
+		{
+			name:     "default layout",
+			input:    "COL1\n2018-01-02T03:00:00Z\n\n2018-01-02T03:00:00+01:00\n",
+			expected: []*time.Time{timePtr(date(2018, 1, 2, 3)), nil, timePtr(date(2018, 1, 2, 2))},
+		},
+		{
+			name:     "custom layout and location",
+			input:    "COL1\n2018-01-02 03:00\n",
+			conf:     []csv.ConfigFunc{csv.TimeLayout("2006-01-02 15:04"), csv.TimeLocation(loc)},
+			expected: []*time.Time{timePtr(date(2018, 1, 2, 2))},
+		},
+		{
+			name:  "invalid time",
+			input: "COL1\nfoo\n",
+			err:   "Create time column",
+		},
+	}
+
+	for _, tc := range table {
+		t.Run(tc.name, func(t *testing.T) {
+			conf := append(tc.conf, csv.Types(map[string]string{"COL1": "time"}))
+			out := qframe.ReadCSV(strings.NewReader(tc.input), conf...)
+			if tc.err != "" {
+				assertErr(t, out.Err, tc.err)
+				return
+			}
+
+			assertNotErr(t, out.Err)
+			assertTrue(t, out.ColumnTypeMap()["COL1"] == types.Time)
+			assertEquals(t, qframe.New(map[string]interface{}{"COL1": tc.expected}), out)
+		})
+	}
+}
+
+func TestQFrame_ConcatTimeFillMissing(t *testing.T) {
+	a := qframe.New(map[string]interface{}{"COL1": []int{1}, "COL2": []time.Time{date(2018, 1, 1, 0)}})
+	b := qframe.New(map[string]interface{}{"COL1": []int{2}})
+	expected := qframe.New(map[string]interface{}{
+		"COL1": []int{1, 2}, "COL2": []*time.Time{timePtr(date(2018, 1, 1, 0)), nil}})
+
+	assertEquals(t, expected, qframe.ConcatFillMissing(a, b))
+}
+
+func minute(hour, min int) time.Time {
+	return time.Date(2018, 1, 1, hour, min, 0, 0, time.UTC)
+}
+
+func TestQFrame_Resample(t *testing.T) {
+	in := qframe.New(map[string]interface{}{
+		"TS":    []*time.Time{timePtr(minute(10, 7)), timePtr(minute(10, 1)), nil, timePtr(minute(10, 16)), timePtr(minute(10, 3))},
+		"PRICE": []float64{3, 1, 10, 4, 2}})
+
+	table := []struct {
+		name     string
+		interval time.Duration
+		aggs     []qframe.Aggregation
+		expected map[string]interface{}
+		order    []string
+		fill     bool
+	}{
+		{
+			name:     "five minute buckets",
+			interval: 5 * time.Minute,
+			aggs:     []qframe.Aggregation{{Fn: "sum", Column: "PRICE"}, {Fn: "count", Column: "PRICE", As: "COUNT"}},
+			expected: map[string]interface{}{
+				"TS":    []time.Time{minute(10, 0), minute(10, 5), minute(10, 15)},
+				"PRICE": []float64{3, 3, 4},
+				"COUNT": []int{2, 1, 1}},
+			order: []string{"TS", "PRICE", "COUNT"},
+		},
+		{
+			name:     "hourly open high low close",
+			interval: time.Hour,
+			aggs: []qframe.Aggregation{
+				{Fn: "first", Column: "PRICE", As: "OPEN"},
+				{Fn: "max", Column: "PRICE", As: "HIGH"},
+				{Fn: "min", Column: "PRICE", As: "LOW"},
+				{Fn: "last", Column: "PRICE", As: "CLOSE"}},
+			expected: map[string]interface{}{
+				"TS":   []time.Time{minute(10, 0)},
+				"OPEN": []float64{3}, "HIGH": []float64{4}, "LOW": []float64{1}, "CLOSE": []float64{2}},
+			order: []string{"TS", "OPEN", "HIGH", "LOW", "CLOSE"},
+		},
+		{
+			name:     "fill gaps",
+			interval: 5 * time.Minute,
+			aggs:     []qframe.Aggregation{{Fn: "sum", Column: "PRICE"}},
+			expected: map[string]interface{}{
+				"TS":    []time.Time{minute(10, 0), minute(10, 5), minute(10, 10), minute(10, 15)},
+				"PRICE": []float64{3, 3, math.NaN(), 4}},
+			order: []string{"TS", "PRICE"},
+			fill:  true,
+		},
+	}
+
+	for _, tc := range table {
+		t.Run(tc.name, func(t *testing.T) {
+			out := in.Resample("TS", tc.interval, tc.aggs...)
+			if tc.fill {
+				out = out.FillGaps("TS", tc.interval)
+			}
+
+			assertNotErr(t, out.Err)
+			assertEquals(t, qframe.New(tc.expected, newqf.ColumnOrder(tc.order...)), out)
+		})
+	}
+}
+
+func TestQFrame_ResampleLocation(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	in := qframe.New(map[string]interface{}{
+		"TS":  []time.Time{time.Date(2018, 1, 1, 23, 0, 0, 0, loc), time.Date(2018, 1, 2, 1, 0, 0, 0, loc)},
+		"VAL": []int{1, 2}})
+	expected := qframe.New(map[string]interface{}{
+		"TS":  []time.Time{time.Date(2018, 1, 1, 0, 0, 0, 0, loc), time.Date(2018, 1, 2, 0, 0, 0, 0, loc)},
+		"VAL": []int{1, 2}})
+
+	out := in.Resample("TS", 24*time.Hour, qframe.Aggregation{Fn: "sum", Column: "VAL"})
+	assertEquals(t, expected, out)
+}
+
+func TestQFrame_ResampleErrors(t *testing.T) {
+	in := qframe.New(map[string]interface{}{"TS": []time.Time{minute(10, 0)}, "VAL": []int{1}})
+	assertErr(t, in.Resample("VAL", time.Minute).Err, "must be a time column")
+	assertErr(t, in.Resample("TS", 0).Err, "interval must be positive")
+	assertErr(t, in.FillGaps("FOO", time.Minute).Err, "unknown column")
+}
+
+func TestQFrame_HighCardinalityEnum(t *testing.T) {
+	for _, size := range []int{255, 256, 65535, 65536, 70000} {
+		t.Run(fmt.Sprintf("Cardinality %d", size), func(t *testing.T) {
+			// Values in reverse order to verify that comparisons follow the order of the values
+			values := make([]string, size)
+			for i := range values {
+				values[i] = strconv.Itoa(size - i)
+			}
+
+			last, first, nine := values[size-1], values[0], "9"
+			in := qframe.New(map[string]interface{}{"COL1": []*string{&last, nil, &nine, &first, &nine}},
+				newqf.Enums(map[string][]string{"COL1": values}))
+			assertNotErr(t, in.Err)
+
+			out := in.Sort(qframe.Order{Column: "COL1"})
+			assertEquals(t, qframe.New(map[string]interface{}{"COL1": []*string{nil, &first, &nine, &nine, &last}},
+				newqf.Enums(map[string][]string{"COL1": values})), out)
+
+			out = in.Filter(qframe.Filter{Column: "COL1", Comparator: "in", Arg: []string{first, last}})
+			assertEquals(t, qframe.New(map[string]interface{}{"COL1": []*string{&last, &first}},
+				newqf.Enums(map[string][]string{"COL1": values})), out)
+
+			out = in.Filter(qframe.Filter{Column: "COL1", Comparator: "like", Arg: "9%"})
+			assertTrue(t, out.Len() == 2)
+
+			out = in.Filter(qframe.Filter{Column: "COL1", Comparator: ">", Arg: nine})
+			assertEquals(t, qframe.New(map[string]interface{}{"COL1": []*string{&last}},
+				newqf.Enums(map[string][]string{"COL1": values})), out)
+
+			out = in.Filter(qframe.Filter{Column: "COL1", Comparator: "!=", Arg: nine})
+			assertTrue(t, out.Len() == 3)
+
+			out = in.Filter(qframe.Filter{Column: "COL1", Comparator: "isnull"})
+			assertTrue(t, out.Len() == 1)
+
+			out = in.Copy("COL2", "COL1").Filter(qframe.Filter{Column: "COL1", Comparator: "=", Arg: types.ColumnName("COL2")})
+			assertTrue(t, out.Len() == 4)
+
+			out = in.GroupBy(groupby.Columns("COL1"), groupby.Null(true)).Aggregate(
+				qframe.Aggregation{Fn: "count", Column: "COL1", As: "COUNT"})
+			assertTrue(t, out.Len() == 4)
+
+			out = in.GroupBy().Aggregate(qframe.Aggregation{Fn: "nunique", Column: "COL1"})
+			assertEquals(t, qframe.New(map[string]interface{}{"COL1": []int{3}}), out)
+		})
+	}
+}
+
+func TestQFrame_ConcatEnumWidening(t *testing.T) {
+	a, b := make([]string, 200), make([]string, 200)
+	for i := range a {
+		a[i], b[i] = fmt.Sprintf("a%d", i), fmt.Sprintf("b%d", i)
+	}
+
+	enums := newqf.Enums(map[string][]string{"COL1": nil})
+	out := qframe.Concat(
+		qframe.New(map[string]interface{}{"COL1": a}, enums),
+		qframe.New(map[string]interface{}{"COL1": b}, enums))
+	assertNotErr(t, out.Err)
+
+	expected := qframe.New(map[string]interface{}{"COL1": append(a, b...)}, enums)
+	assertEquals(t, expected, out)
+}
+
+func TestQFrame_ReadCSVAutoEnum(t *testing.T) {
+	input := `LOW,HIGH,TYPED,INT
+a,a,a,1
+b,b,a,2
+a,c,a,3
+,d,a,4
+`
+	out := qframe.ReadCSV(strings.NewReader(input),
+		csv.AutoEnum(2),
+		csv.EmptyNull(true),
+		csv.Types(map[string]string{"TYPED": "string"}))
+	assertNotErr(t, out.Err)
+
+	colTypes := out.ColumnTypeMap()
+	assertTrue(t, colTypes["LOW"] == types.Enum)
+	assertTrue(t, colTypes["HIGH"] == types.String)
+	assertTrue(t, colTypes["TYPED"] == types.String)
+	assertTrue(t, colTypes["INT"] == types.Int)
+
+	a, b := "a", "b"
+	assertEquals(t,
+		qframe.New(map[string]interface{}{"LOW": []*string{&a, &b, &a, nil}}, newqf.Enums(map[string][]string{"LOW": {"a", "b"}})),
+		out.Select("LOW"))
+}
+
+func TestQFrame_ReadJSONAutoEnum(t *testing.T) {
+	input := `[{"LOW": "a", "HIGH": "a"}, {"LOW": "b", "HIGH": "b"}, {"LOW": "a", "HIGH": "c"}]`
+
+	out := qframe.ReadJSON(strings.NewReader(input), newqf.AutoEnum(2))
+	assertNotErr(t, out.Err)
+	assertTrue(t, out.ColumnTypeMap()["LOW"] == types.Enum)
+	assertTrue(t, out.ColumnTypeMap()["HIGH"] == types.String)
+
+	out = qframe.ReadJSON(strings.NewReader(input))
+	assertNotErr(t, out.Err)
+	assertTrue(t, out.ColumnTypeMap()["LOW"] == types.String)
+}
+
+func TestQFrame_Cast(t *testing.T) {
+	a, b, one, two, x := "a", "b", "1", "2", "x"
+	table := []struct {
+		name     string
+		input    interface{}
+		enums    map[string][]string
+		dataType types.DataType
+		configs  []cast.ConfigFunc
+		expected interface{}
+		expEnums map[string][]string
+		err      string
+	}{
+		{
+			name:     "int to float",
+			input:    []*int{intPtr(1), nil},
+			dataType: types.Float,
+			expected: []float64{1, math.NaN()}},
+		{
+			name:     "float to int",
+			input:    []float64{1.7, math.NaN()},
+			dataType: types.Int,
+			expected: []*int{intPtr(1), nil}},
+		{
+			name:     "bool to int",
+			input:    []*bool{boolPtr(true), boolPtr(false), nil},
+			dataType: types.Int,
+			expected: []*int{intPtr(1), intPtr(0), nil}},
+		{
+			name:     "int to bool",
+			input:    []int{0, 2},
+			dataType: types.Bool,
+			expected: []bool{false, true}},
+		{
+			name:     "int to string",
+			input:    []*int{intPtr(1), nil},
+			dataType: types.String,
+			expected: []*string{&one, nil}},
+		{
+			name:     "string to int",
+			input:    []*string{&one, nil, &two},
+			dataType: types.Int,
+			expected: []*int{intPtr(1), nil, intPtr(2)}},
+		{
+			name:     "string to int strict",
+			input:    []*string{&one, &x},
+			dataType: types.Int,
+			err:      `cannot cast "x" at row 1 to int`},
+		{
+			name:     "string to int lenient",
+			input:    []*string{&one, &x},
+			dataType: types.Int,
+			configs:  []cast.ConfigFunc{cast.Lenient(true)},
+			expected: []*int{intPtr(1), nil}},
+		{
+			name:     "string to float lenient",
+			input:    []*string{&one, &x},
+			dataType: types.Float,
+			configs:  []cast.ConfigFunc{cast.Lenient(true)},
+			expected: []float64{1, math.NaN()}},
+		{
+			name:     "string to bool",
+			input:    []string{"true", "false"},
+			dataType: types.Bool,
+			expected: []bool{true, false}},
+		{
+			name:     "string to enum",
+			input:    []*string{&a, nil, &b},
+			dataType: types.Enum,
+			configs:  []cast.ConfigFunc{cast.EnumValues([]string{"b", "a"})},
+			expected: []*string{&a, nil, &b},
+			expEnums: map[string][]string{"COL1": {"b", "a"}}},
+		{
+			name:     "string to enum unknown value strict",
+			input:    []*string{&a, &x},
+			dataType: types.Enum,
+			configs:  []cast.ConfigFunc{cast.EnumValues([]string{"a"})},
+			err:      `cannot cast "x" at row 1 to enum`},
+		{
+			name:     "string to enum unknown value lenient",
+			input:    []*string{&a, &x},
+			dataType: types.Enum,
+			configs:  []cast.ConfigFunc{cast.EnumValues([]string{"a"}), cast.Lenient(true)},
+			expected: []*string{&a, nil},
+			expEnums: map[string][]string{"COL1": {"a"}}},
+		{
+			name:     "enum to string",
+			input:    []*string{&a, nil},
+			enums:    map[string][]string{"COL1": {"a"}},
+			dataType: types.String,
+			expected: []*string{&a, nil}},
+		{
+			name:     "enum to int",
+			input:    []*string{&one, &two},
+			enums:    map[string][]string{"COL1": nil},
+			dataType: types.Int,
+			expected: []int{1, 2}},
+		{
+			name:     "string to time",
+			input:    []*string{nil, &x, timeString(date(2018, 1, 2, 3))},
+			dataType: types.Time,
+			configs:  []cast.ConfigFunc{cast.Lenient(true)},
+			expected: []*time.Time{nil, nil, timePtr(date(2018, 1, 2, 3))}},
+		{
+			name:     "time to string with layout",
+			input:    []*time.Time{timePtr(date(2018, 1, 2, 3)), nil},
+			dataType: types.String,
+			configs:  []cast.ConfigFunc{cast.TimeLayout("2006-01-02")},
+			expected: []*string{timeString(date(2018, 1, 2, 3), "2006-01-02"), nil}},
+		{
+			name:     "time to int",
+			input:    []time.Time{time.Unix(0, 17)},
+			dataType: types.Int,
+			expected: []int{17}},
+		{
+			name:     "int to time",
+			input:    []int{17},
+			dataType: types.Time,
+			expected: []time.Time{time.Unix(0, 17)}},
+		{
+			name:     "bool to time",
+			input:    []bool{true},
+			dataType: types.Time,
+			err:      "cannot cast bool to time"},
+		{
+			name:     "same type",
+			input:    []int{1},
+			dataType: types.Int,
+			expected: []int{1}},
+		{
+			name:     "unknown type",
+			input:    []int{1},
+			dataType: "foo",
+			err:      "unknown data type"},
+	}
+
+	for _, tc := range table {
+		t.Run(tc.name, func(t *testing.T) {
+			in := qframe.New(map[string]interface{}{"COL1": tc.input, "COL2": []int{0, 1, 2}[:reflect.ValueOf(tc.input).Len()]},
+				newqf.Enums(tc.enums))
+			out := in.Cast("COL1", tc.dataType, tc.configs...)
+			if tc.err != "" {
+				assertErr(t, out.Err, tc.err)
+				return
+			}
+
+			expected := qframe.New(map[string]interface{}{"COL1": tc.expected, "COL2": []int{0, 1, 2}[:reflect.ValueOf(tc.input).Len()]},
+				newqf.Enums(tc.expEnums))
+			assertEquals(t, expected, out)
+		})
+	}
+}
+
+func TestQFrame_CastFilteredAndSorted(t *testing.T) {
+	in := qframe.New(map[string]interface{}{"COL1": []string{"3", "x", "1"}})
+	out := in.Filter(qframe.Filter{Column: "COL1", Comparator: "!=", Arg: "x"}).
+		Sort(qframe.Order{Column: "COL1"}).
+		Cast("COL1", types.Int)
+	assertEquals(t, qframe.New(map[string]interface{}{"COL1": []int{1, 3}}), out)
+}
+
+func timeString(t time.Time, layout ...string) *string {
+	l := time.RFC3339Nano
+	if len(layout) > 0 {
+		l = layout[0]
+	}
+	s := t.Format(l)
+	return &s
+}
+
+func TestQFrame_ReadCSVChunks(t *testing.T) {
+	input := `INT,FLOAT,ENUM
+1,1.5,b
+2,2,a
+3,3,c
+4,4,a
+5,5,b
+`
+	var chunks []qframe.QFrame
+	err := qframe.ReadCSVChunks(strings.NewReader(input), 2, func(qf qframe.QFrame) error {
+		chunks = append(chunks, qf)
+		return nil
+	}, csv.Types(map[string]string{"ENUM": "enum"}))
+	assertNotErr(t, err)
+
+	if len(chunks) != 3 {
+		t.Fatalf("Unexpected number of chunks: %d", len(chunks))
+	}
+
+	assertTrue(t, chunks[0].Len() == 2 && chunks[1].Len() == 2 && chunks[2].Len() == 1)
+	for _, c := range chunks {
+		assertTrue(t, reflect.DeepEqual(c.ColumnTypes(), []types.DataType{types.Int, types.Float, types.Enum}))
+	}
+
+	// Enum values keep the order of first appearance across chunks
+	out := chunks[1].Sort(qframe.Order{Column: "ENUM"})
+	view := out.MustEnumView("ENUM")
+	assertTrue(t, *view.ItemAt(0) == "a" && *view.ItemAt(1) == "c")
+
+	all := qframe.Concat(chunks...)
+	assertEquals(t, qframe.ReadCSV(strings.NewReader(input), csv.Types(map[string]string{"ENUM": "enum"})), all)
+}
+
+func TestQFrame_ReadCSVChunksErrors(t *testing.T) {
+	noop := func(qf qframe.QFrame) error { return nil }
+
+	err := qframe.ReadCSVChunks(strings.NewReader("COL1\n1\n2\nx\n"), 2, noop)
+	assertErr(t, err, "Create int column")
+
+	err = qframe.ReadCSVChunks(strings.NewReader("COL1\n1\n"), 0, noop)
+	assertErr(t, err, "chunk size must be positive")
+
+	calls := 0
+	err = qframe.ReadCSVChunks(strings.NewReader("COL1\n1\n2\n3\n"), 1, func(qf qframe.QFrame) error {
+		calls++
+		return fmt.Errorf("stop")
+	})
+	assertErr(t, err, "stop")
+	assertTrue(t, calls == 1)
+
+	err = qframe.ReadCSVChunks(strings.NewReader("COL1\n"), 1, func(qf qframe.QFrame) error {
+		t.Errorf("Unexpected chunk")
+		return nil
+	})
+	assertNotErr(t, err)
+}
+
+func TestQFrame_ReadCSVOptions(t *testing.T) {
+	table := []struct {
+		name     string
+		input    string
+		configs  []csv.ConfigFunc
+		expected map[string]interface{}
+		order    []string
+	}{
+		{
+			name:     "skip rows",
+			input:    "preamble\nmore preamble\nA,B\n1,2\n",
+			configs:  []csv.ConfigFunc{csv.SkipRows(2)},
+			expected: map[string]interface{}{"A": []int{1}, "B": []int{2}},
+			order:    []string{"A", "B"},
+		},
+		{
+			name:     "comments",
+			input:    "# comment\nA,B\n1,2\n# another comment\n3,4\n",
+			configs:  []csv.ConfigFunc{csv.CommentChar('#')},
+			expected: map[string]interface{}{"A": []int{1, 3}, "B": []int{2, 4}},
+			order:    []string{"A", "B"},
+		},
+		{
+			name:     "headers for header-less input",
+			input:    "1,2\n3,4\n",
+			configs:  []csv.ConfigFunc{csv.Headers([]string{"A", "B"})},
+			expected: map[string]interface{}{"A": []int{1, 3}, "B": []int{2, 4}},
+			order:    []string{"A", "B"},
+		},
+		{
+			name:  "column subset",
+			input: "A,B,C\n1,x,a\n2,y,b\n",
+			// B is not parsed, hence the invalid type is never detected
+			configs:  []csv.ConfigFunc{csv.Columns("C", "A"), csv.Types(map[string]string{"B": "int"})},
+			expected: map[string]interface{}{"A": []int{1, 2}, "C": []string{"a", "b"}},
+			order:    []string{"C", "A"},
+		},
+		{
+			name:     "max rows",
+			input:    "A\n1\n2\n3\nx\n",
+			configs:  []csv.ConfigFunc{csv.MaxRows(2)},
+			expected: map[string]interface{}{"A": []int{1, 2}},
+			order:    []string{"A"},
+		},
+		{
+			name:  "all combined",
+			input: "preamble\n1,a,2\n#3,b,4\n5,c,6\n7,d,8\n",
+			configs: []csv.ConfigFunc{
+				csv.SkipRows(1), csv.CommentChar('#'), csv.Headers([]string{"A", "B", "C"}),
+				csv.Columns("C", "B"), csv.MaxRows(2)},
+			expected: map[string]interface{}{"B": []string{"a", "c"}, "C": []int{2, 6}},
+			order:    []string{"C", "B"},
+		},
+	}
+
+	for _, tc := range table {
+		t.Run(tc.name, func(t *testing.T) {
+			out := qframe.ReadCSV(strings.NewReader(tc.input), tc.configs...)
+			assertNotErr(t, out.Err)
+			assertEquals(t, qframe.New(tc.expected, newqf.ColumnOrder(tc.order...)), out)
+		})
+	}
+}
+
+func TestQFrame_ReadCSVOptionsErrors(t *testing.T) {
+	out := qframe.ReadCSV(strings.NewReader("A,B\n1,2\n"), csv.Columns("A", "C"))
+	assertErr(t, out.Err, "unknown column in Columns")
+
+	out = qframe.ReadCSV(strings.NewReader("A,B\n1,2\n"), csv.SkipRows(3))
+	assertErr(t, out.Err, "skip rows")
+
+	out = qframe.ReadCSV(strings.NewReader("1,2,3\n"), csv.Headers([]string{"A", "B"}))
+	assertErr(t, out.Err, "Wrong number of columns")
+}
+
+func TestQFrame_ReadCSVChunksMaxRows(t *testing.T) {
+	rows := 0
+	err := qframe.ReadCSVChunks(strings.NewReader("A\n1\n2\n3\n4\n5\n"), 2, func(qf qframe.QFrame) error {
+		rows += qf.Len()
+		return nil
+	}, csv.MaxRows(3))
+	assertNotErr(t, err)
+	assertTrue(t, rows == 3)
+}
+
+func TestQFrame_ReadCSVNullValues(t *testing.T) {
+	na, a, yes, maybe := "NA", "a", "yes", "maybe"
+	nan := math.NaN()
+	table := []struct {
+		name     string
+		input    string
+		configs  []csv.ConfigFunc
+		expected map[string]interface{}
+		enums    map[string][]string
+		order    []string
+	}{
+		{
+			name:    "all types",
+			input:   "INT,FLOAT,BOOL,STRING\n1,1.5,true,a\nNA,\\N,-,NA\n,3,false,\n",
+			configs: []csv.ConfigFunc{csv.NullValues([]string{"NA", "\\N", "-"})},
+			expected: map[string]interface{}{
+				"INT":    []*int{intPtr(1), nil, nil},
+				"FLOAT":  []float64{1.5, nan, 3},
+				"BOOL":   []*bool{boolPtr(true), nil, boolPtr(false)},
+				"STRING": []*string{&a, nil, new(string)}},
+			order: []string{"INT", "FLOAT", "BOOL", "STRING"},
+		},
+		{
+			name:     "only null values",
+			input:    "COL\nNA\nNA\n",
+			configs:  []csv.ConfigFunc{csv.NullValues([]string{"NA"})},
+			expected: map[string]interface{}{"COL": []float64{nan, nan}},
+		},
+		{
+			name:     "typed columns",
+			input:    "INT,ENUM\n1,a\nNULL,NULL\n",
+			configs:  []csv.ConfigFunc{csv.NullValues([]string{"NULL"}), csv.Types(map[string]string{"INT": "int", "ENUM": "enum"})},
+			expected: map[string]interface{}{"INT": []*int{intPtr(1), nil}, "ENUM": []*string{&a, nil}},
+			enums:    map[string][]string{"ENUM": nil},
+			order:    []string{"INT", "ENUM"},
+		},
+		{
+			name:  "column override",
+			input: "INT,STRING\n1,NA\nNA,-\n",
+			configs: []csv.ConfigFunc{
+				csv.NullValues([]string{"NA"}),
+				csv.ColumnNullValues(map[string][]string{"STRING": {"-"}})},
+			expected: map[string]interface{}{"INT": []*int{intPtr(1), nil}, "STRING": []*string{&na, nil}},
+			order:    []string{"INT", "STRING"},
+		},
+		{
+			name:     "bool spellings",
+			input:    "COL\nyes\nno\nTRUE\n\n",
+			configs:  []csv.ConfigFunc{csv.BoolValues([]string{"yes"}, []string{"no"})},
+			expected: map[string]interface{}{"COL": []*bool{boolPtr(true), boolPtr(false), boolPtr(true), nil}},
+		},
+		{
+			name:     "unknown spelling gives string column",
+			input:    "COL\nyes\nmaybe\n",
+			configs:  []csv.ConfigFunc{csv.BoolValues([]string{"yes"}, []string{"no"})},
+			expected: map[string]interface{}{"COL": []*string{&yes, &maybe}},
+		},
+	}
+
+	for _, tc := range table {
+		t.Run(tc.name, func(t *testing.T) {
+			out := qframe.ReadCSV(strings.NewReader(tc.input), tc.configs...)
+			assertNotErr(t, out.Err)
+			assertEquals(t, qframe.New(tc.expected, newqf.Enums(tc.enums), newqf.ColumnOrder(tc.order...)), out)
+		})
+	}
+}
+
+func TestQFrame_FilterEnum(t *testing.T) {
+	a, b, c, d, e := "a", "b", "c", "d", "e"
+	enums := newqf.Enums(map[string][]string{"COL1": {"a", "b", "c", "d", "e"}})
+	in := qframe.New(map[string]interface{}{
+		"COL1": []*string{&b, &c, &a, nil, &e, &d, nil}}, enums)
+
+	table := []struct {
+		clause   qframe.FilterClause
+		expected map[string]interface{}
+	}{
+		{
+			qframe.Filter{Column: "COL1", Comparator: ">", Arg: "b"},
+			map[string]interface{}{"COL1": []*string{&c, &e, &d}},
+		},
+		{
+			qframe.Filter{Column: "COL1", Comparator: "in", Arg: []string{"a", "b"}},
+			map[string]interface{}{"COL1": []*string{&b, &a}},
+		},
+	}
+
+	for i, tc := range table {
+		t.Run(fmt.Sprintf("Filter enum %d", i), func(t *testing.T) {
+			expected := qframe.New(tc.expected, enums)
+			out := in.Filter(tc.clause)
+			assertEquals(t, expected, out)
+		})
+	}
+}
+
+func TestQFrame_FilterString(t *testing.T) {
+	a, b, c, d, e := "a", "b", "c", "d", "e"
+	withNil := map[string]interface{}{"COL1": []*string{&b, &c, &a, nil, &e, &d, nil}}
+
+	table := []struct {
+		input    map[string]interface{}
+		clause   qframe.FilterClause
+		expected map[string]interface{}
+	}{
+		{
+			withNil,
+			qframe.Filter{Column: "COL1", Comparator: ">", Arg: "b"},
+			map[string]interface{}{"COL1": []*string{&c, &e, &d}},
+		},
+		{
+			withNil,
+			qframe.Filter{Column: "COL1", Comparator: "<", Arg: "b"},
+			map[string]interface{}{"COL1": []*string{&a}},
+		},
+		{
+			withNil,
+			qframe.Filter{Column: "COL1", Comparator: "!=", Arg: "a"},
+			map[string]interface{}{"COL1": []*string{&b, &c, nil, &e, &d, nil}},
+		},
+		{
+			withNil,
+			qframe.Filter{Column: "COL1", Comparator: "like", Arg: "b"},
+			map[string]interface{}{"COL1": []*string{&b}},
+		},
+		{
+			withNil,
+			qframe.Filter{Column: "COL1", Comparator: "in", Arg: []string{"a", "b"}},
+			map[string]interface{}{"COL1": []*string{&b, &a}},
+		},
+	}
+
+	for i, tc := range table {
+		t.Run(fmt.Sprintf("Filter string %d", i), func(t *testing.T) {
+			in := qframe.New(tc.input)
+			expected := qframe.New(tc.expected)
+			out := in.Filter(tc.clause)
+			assertEquals(t, expected, out)
+		})
+	}
+}
+
+func TestQFrame_LikeFilterString(t *testing.T) {
+	col1 := []string{"ABC", "AbC", "DEF", "ABCDEF", "abcdef", "FFF", "abc$def", "défåäöΦ"}
+
+	// Add a couple of fields to be able to verify functionality for high cardinality enums
+	for i := 0; i < 200; i++ {
+		col1 = append(col1, fmt.Sprintf("foo%dbar", i))
+	}
+
+	data := map[string]interface{}{"COL1": col1}
+	for _, enums := range []map[string][]string{{}, {"COL1": nil}} {
+		table := []struct {
+			comparator string
 			arg        string
 			expected   []string
 		}{
@@ -1363,6 +2989,65 @@ func TestQFrame_CopyColumn(t *testing.T) {
 	assertEquals(t, expectedReplace, input.Copy("COL1", "COL2"))
 }
 
+func TestQFrame_Rename(t *testing.T) {
+	input := qframe.New(map[string]interface{}{
+		"COL1": []string{"a", "b"},
+		"COL2": []int{3, 2},
+		"COL3": []float64{1.5, 2.5},
+	}, newqf.ColumnOrder("COL1", "COL2", "COL3"))
+
+	table := []struct {
+		name     string
+		names    map[string]string
+		expected qframe.QFrame
+	}{
+		{
+			name:  "keeps position",
+			names: map[string]string{"COL1": "FOO"},
+			expected: qframe.New(map[string]interface{}{
+				"FOO":  []string{"a", "b"},
+				"COL2": []int{3, 2},
+				"COL3": []float64{1.5, 2.5},
+			}, newqf.ColumnOrder("FOO", "COL2", "COL3")),
+		},
+		{
+			name:  "swap",
+			names: map[string]string{"COL2": "COL3", "COL3": "COL2"},
+			expected: qframe.New(map[string]interface{}{
+				"COL1": []string{"a", "b"},
+				"COL3": []int{3, 2},
+				"COL2": []float64{1.5, 2.5},
+			}, newqf.ColumnOrder("COL1", "COL3", "COL2")),
+		},
+		{
+			name:     "no names",
+			names:    map[string]string{},
+			expected: input,
+		},
+	}
+
+	for _, tc := range table {
+		t.Run(fmt.Sprintf("Rename %s", tc.name), func(t *testing.T) {
+			out := input.Rename(tc.names)
+			assertNotErr(t, out.Err)
+			assertEquals(t, tc.expected, out)
+		})
+	}
+
+	t.Run("Rename errors", func(t *testing.T) {
+		assertErr(t, input.Rename(map[string]string{"FOO": "BAR"}).Err, "unknown column")
+		assertErr(t, input.Rename(map[string]string{"COL1": "COL2"}).Err, "duplicate column")
+		assertErr(t, input.Rename(map[string]string{"COL1": "BAR", "COL2": "BAR"}).Err, "duplicate column")
+		assertErr(t, input.Rename(map[string]string{"COL1": "$BAR"}).Err, "must not start with $")
+	})
+
+	t.Run("Rename when reading CSV", func(t *testing.T) {
+		out := qframe.ReadCSV(strings.NewReader("Foo Bar,baz\n1,2\n"), csv.Rename(map[string]string{"Foo Bar": "foo_bar"}))
+		expected := qframe.New(map[string]interface{}{"foo_bar": []int{1}, "baz": []int{2}}, newqf.ColumnOrder("foo_bar", "baz"))
+		assertEquals(t, expected, out)
+	})
+}
+
 func TestQFrame_ApplyZeroArg(t *testing.T) {
 	a, b := "a", "b"
 	table := []struct {
@@ -1581,13 +3266,15 @@ func sum(c []int) int {
 func TestQFrame_AggregateGroupByNull(t *testing.T) {
 	a, b := "a", "b"
 	for _, groupByNull := range []bool{false, true} {
-		for _, column := range []string{"COL1", "COL2", "COL3"} {
+		for _, column := range []string{"COL1", "COL2", "COL3", "COL5", "COL6"} {
 			t.Run(fmt.Sprintf("%s %v", column, groupByNull), func(t *testing.T) {
 				input := qframe.New(map[string]interface{}{
 					"COL1": []*string{&a, &b, nil, &a, &b, nil},
 					"COL2": []*string{&a, &b, nil, &a, &b, nil},
 					"COL3": []float64{1, 2, math.NaN(), 1, 2, math.NaN()},
 					"COL4": []int{1, 2, 3, 10, 20, 30},
+					"COL5": []*int{intPtr(1), intPtr(2), nil, intPtr(1), intPtr(2), nil},
+					"COL6": []*bool{boolPtr(false), boolPtr(true), nil, boolPtr(false), boolPtr(true), nil},
 				}, newqf.Enums(map[string][]string{"COL2": nil}))
 
 				col4 := []int{3, 30, 11, 22}
@@ -1676,14 +3363,6 @@ func TestQFrame_NewErrors(t *testing.T) {
 		{
 			input: map[string]interface{}{"$foo": []int{1}},
 			err:   "must not start with $"},
-		{
-			input:   map[string]interface{}{"COL1": longCol},
-			configs: []newqf.ConfigFunc{newqf.Enums(map[string][]string{"COL1": nil})},
-			err:     "enum max cardinality"},
-		{
-			input:   map[string]interface{}{"COL1": longCol},
-			configs: []newqf.ConfigFunc{newqf.Enums(map[string][]string{"COL1": nil})},
-			err:     "enum max cardinality"},
 		{
 			input:   map[string]interface{}{"COL1": longCol},
 			configs: []newqf.ConfigFunc{newqf.Enums(map[string][]string{"COL2": nil})},
@@ -1786,6 +3465,20 @@ func TestQFrame_OperationErrors(t *testing.T) {
 				return f.GroupBy(groupby.Columns("COL1")).Aggregate(qframe.Aggregation{Fn: "sum", Column: "COL1"}).Err
 			},
 			err: "cannot aggregate on column that is part of group by"},
+		{
+			name: "Aggregate into the same column twice is not allowed",
+			fn: func(f qframe.QFrame) error {
+				return f.GroupBy(groupby.Columns("COL1")).Aggregate(
+					qframe.Aggregation{Fn: "sum", Column: "COL2", As: "FOO"},
+					qframe.Aggregation{Fn: "max", Column: "COL2", As: "FOO"}).Err
+			},
+			err: "is already an aggregate: FOO"},
+		{
+			name: "Aggregate into column with invalid name",
+			fn: func(f qframe.QFrame) error {
+				return f.GroupBy(groupby.Columns("COL1")).Aggregate(qframe.Aggregation{Fn: "sum", Column: "COL2", As: "$FOO"}).Err
+			},
+			err: "must not start with $"},
 		{
 			name:    "Filter using unknown operation, enum",
 			input:   map[string]interface{}{"COL1": []string{"a", "b"}},
@@ -2092,6 +3785,28 @@ func TestQFrame_EvalSuccess(t *testing.T) {
 			input:    map[string]interface{}{"COL1": []float64{18}, "COL2": []float64{2}, "COL3": []float64{3}},
 			dstCol:   "COL4",
 			expected: []float64{1}},
+		{
+			name:     "time year",
+			expr:     qframe.Expr("year", col("COL1")),
+			input:    map[string]interface{}{"COL1": []time.Time{date(2018, 3, 4, 5), date(2019, 1, 1, 0)}},
+			expected: []int{2018, 2019}},
+		{
+			name:     "time truncate to day",
+			expr:     qframe.Expr("trunc_day", col("COL1")),
+			input:    map[string]interface{}{"COL1": []time.Time{date(2018, 3, 4, 5), date(2019, 1, 1, 0)}},
+			expected: []time.Time{date(2018, 3, 4, 0), date(2019, 1, 1, 0)}},
+		{
+			name:     "time max of col and const",
+			expr:     qframe.Expr("max", col("COL1"), date(2018, 6, 1, 0)),
+			input:    map[string]interface{}{"COL1": []time.Time{date(2018, 3, 4, 5), date(2019, 1, 1, 0)}},
+			expected: []time.Time{date(2018, 6, 1, 0), date(2019, 1, 1, 0)}},
+		{
+			name:         "time custom function",
+			expr:         qframe.Expr("days", col("COL1")),
+			input:        map[string]interface{}{"COL1": []time.Time{date(1970, 1, 3, 0), date(1970, 1, 2, 12)}},
+			expected:     []float64{2, 1.5},
+			customFn:     func(t time.Time) float64 { return float64(t.Unix()) / 86400 },
+			customFnName: "days"},
 	}
 
 	for _, tc := range table {
@@ -2163,3 +3878,519 @@ func TestDoc(t *testing.T) {
 	assertContains(t, doc, "filters")
 	assertContains(t, doc, "aggregations")
 }
+
+func TestQFrame_Join(t *testing.T) {
+	a, b, c, d := "a", "b", "c", "d"
+	l1, l2, l3, l4 := "l1", "l2", "l3", "l4"
+	left := qframe.New(map[string]interface{}{
+		"KEY": []int{1, 2, 3, 2},
+		"VAL": []string{"l1", "l2", "l3", "l4"},
+		"LFT": []bool{true, false, true, false},
+	}, newqf.ColumnOrder("KEY", "VAL", "LFT"))
+
+	right := qframe.New(map[string]interface{}{
+		"KEY": []int{2, 4, 1},
+		"VAL": []float64{20, 40, 10},
+		"RGT": []int{200, 400, 100},
+	}, newqf.ColumnOrder("KEY", "VAL", "RGT"))
+
+	table := []struct {
+		name     string
+		configs  []join.ConfigFunc
+		expected qframe.QFrame
+	}{
+		{
+			name:    "inner",
+			configs: []join.ConfigFunc{join.On("KEY")},
+			expected: qframe.New(map[string]interface{}{
+				"KEY":   []int{1, 2, 2},
+				"VAL_x": []string{"l1", "l2", "l4"},
+				"LFT":   []bool{true, false, false},
+				"VAL_y": []float64{10, 20, 20},
+				"RGT":   []int{100, 200, 200},
+			}, newqf.ColumnOrder("KEY", "VAL_x", "LFT", "VAL_y", "RGT")),
+		},
+		{
+			name:    "left",
+			configs: []join.ConfigFunc{join.On("KEY"), join.How(join.Left), join.Suffixes("_l", "_r")},
+			expected: qframe.New(map[string]interface{}{
+				"KEY":   []int{1, 2, 3, 2},
+				"VAL_l": []string{"l1", "l2", "l3", "l4"},
+				"LFT":   []bool{true, false, true, false},
+				"VAL_r": []float64{10, 20, math.NaN(), 20},
+				"RGT":   []*int{intPtr(100), intPtr(200), nil, intPtr(200)},
+			}, newqf.ColumnOrder("KEY", "VAL_l", "LFT", "VAL_r", "RGT")),
+		},
+		{
+			name:    "right",
+			configs: []join.ConfigFunc{join.On("KEY"), join.How(join.Right)},
+			expected: qframe.New(map[string]interface{}{
+				"KEY":   []int{2, 2, 4, 1},
+				"VAL_x": []*string{&l2, &l4, nil, &l1},
+				"LFT":   []*bool{boolPtr(false), boolPtr(false), nil, boolPtr(true)},
+				"VAL_y": []float64{20, 20, 40, 10},
+				"RGT":   []int{200, 200, 400, 100},
+			}, newqf.ColumnOrder("KEY", "VAL_x", "LFT", "VAL_y", "RGT")),
+		},
+		{
+			name:    "outer",
+			configs: []join.ConfigFunc{join.On("KEY"), join.How(join.Outer)},
+			expected: qframe.New(map[string]interface{}{
+				"KEY":   []int{1, 2, 3, 2, 4},
+				"VAL_x": []*string{&l1, &l2, &l3, &l4, nil},
+				"LFT":   []*bool{boolPtr(true), boolPtr(false), boolPtr(true), boolPtr(false), nil},
+				"VAL_y": []float64{10, 20, math.NaN(), 20, 40},
+				"RGT":   []*int{intPtr(100), intPtr(200), nil, intPtr(200), intPtr(400)},
+			}, newqf.ColumnOrder("KEY", "VAL_x", "LFT", "VAL_y", "RGT")),
+		},
+	}
+
+	for _, tc := range table {
+		t.Run(fmt.Sprintf("Join %s", tc.name), func(t *testing.T) {
+			out := left.Join(right, tc.configs...)
+			assertNotErr(t, out.Err)
+			assertEquals(t, tc.expected, out)
+		})
+	}
+
+	t.Run("Join multiple keys, enum and string", func(t *testing.T) {
+		l := qframe.New(map[string]interface{}{
+			"K1": []*string{&a, &a, &b, nil},
+			"K2": []int{1, 2, 1, 1},
+			"X":  []int{10, 20, 30, 40},
+		}, newqf.ColumnOrder("K1", "K2", "X"), newqf.Enums(map[string][]string{"K1": {"b", "a"}}))
+		r := qframe.New(map[string]interface{}{
+			"K1": []*string{&c, &a, &b, nil, &d},
+			"K2": []int{1, 2, 1, 1, 1},
+			"Y":  []int{1, 2, 3, 4, 5},
+		}, newqf.ColumnOrder("K1", "K2", "Y"))
+
+		out := l.Join(r, join.On("K1", "K2"))
+		expected := qframe.New(map[string]interface{}{
+			"K1": []*string{&a, &b},
+			"K2": []int{2, 1},
+			"X":  []int{20, 30},
+			"Y":  []int{2, 3},
+		}, newqf.ColumnOrder("K1", "K2", "X", "Y"), newqf.Enums(map[string][]string{"K1": {"b", "a"}}))
+		assertEquals(t, expected, out)
+	})
+
+	t.Run("Join outer, enum keys", func(t *testing.T) {
+		l := qframe.New(map[string]interface{}{
+			"K": []*string{&a, &b},
+			"X": []int{1, 2},
+		}, newqf.ColumnOrder("K", "X"), newqf.Enums(map[string][]string{"K": {"b", "a"}}))
+		r := qframe.New(map[string]interface{}{
+			"K": []*string{&a, &c},
+			"Y": []int{3, 4},
+		}, newqf.ColumnOrder("K", "Y"), newqf.Enums(map[string][]string{"K": {"c", "a", "d"}}))
+
+		out := l.Join(r, join.On("K"), join.How(join.Outer))
+		expected := qframe.New(map[string]interface{}{
+			"K": []*string{&a, &b, &c},
+			"X": []*int{intPtr(1), intPtr(2), nil},
+			"Y": []*int{intPtr(3), nil, intPtr(4)},
+		}, newqf.ColumnOrder("K", "X", "Y"), newqf.Enums(map[string][]string{"K": {"b", "a", "c", "d"}}))
+		assertEquals(t, expected, out)
+	})
+
+	t.Run("Join outer, enum and string key", func(t *testing.T) {
+		// The key is a string column even though all rows match
+		l := qframe.New(map[string]interface{}{"K": []string{"a"}}, newqf.Enums(map[string][]string{"K": nil}))
+		r := qframe.New(map[string]interface{}{"K": []string{"a"}})
+		out := l.Join(r, join.On("K"), join.How(join.Outer))
+		assertEquals(t, qframe.New(map[string]interface{}{"K": []string{"a"}}), out)
+	})
+
+	t.Run("Join errors", func(t *testing.T) {
+		assertErr(t, left.Join(right).Err, "at least one column")
+		assertErr(t, left.Join(right, join.On("LFT")).Err, "unknown column")
+		assertErr(t, left.Join(right, join.On("VAL")).Err, "incompatible types")
+		assertErr(t, left.Join(right, join.On("KEY"), join.Suffixes("", "")).Err, "duplicate column")
+	})
+}
+
+func TestQFrame_Concat(t *testing.T) {
+	a, b, c := "a", "b", "c"
+	table := []struct {
+		name     string
+		frames   []qframe.QFrame
+		fill     bool
+		expected qframe.QFrame
+	}{
+		{
+			name: "basic",
+			frames: []qframe.QFrame{
+				qframe.New(map[string]interface{}{"I": []int{1, 2}, "S": []*string{&a, nil}}, newqf.ColumnOrder("I", "S")),
+				qframe.New(map[string]interface{}{"S": []*string{&b}, "I": []int{3}}),
+			},
+			expected: qframe.New(map[string]interface{}{"I": []int{1, 2, 3}, "S": []*string{&a, nil, &b}}, newqf.ColumnOrder("I", "S")),
+		},
+		{
+			name: "filtered and sorted",
+			frames: []qframe.QFrame{
+				qframe.New(map[string]interface{}{"F": []float64{3, 1, 2}}).Sort(qframe.Order{Column: "F"}),
+				qframe.New(map[string]interface{}{"F": []float64{4, 5, 6}}).Filter(qframe.Filter{Column: "F", Comparator: ">", Arg: 4.0}),
+			},
+			expected: qframe.New(map[string]interface{}{"F": []float64{1, 2, 3, 5, 6}}),
+		},
+		{
+			name: "bools",
+			frames: []qframe.QFrame{
+				qframe.New(map[string]interface{}{"B": []bool{true}}),
+				qframe.New(map[string]interface{}{"B": []bool{false, true}}),
+			},
+			expected: qframe.New(map[string]interface{}{"B": []bool{true, false, true}}),
+		},
+		{
+			name: "enums with different values",
+			frames: []qframe.QFrame{
+				qframe.New(map[string]interface{}{"E": []*string{&b, &a}}, newqf.Enums(map[string][]string{"E": {"b", "a"}})),
+				qframe.New(map[string]interface{}{"E": []*string{&c, nil, &a}}, newqf.Enums(map[string][]string{"E": nil})),
+			},
+			expected: qframe.New(map[string]interface{}{"E": []*string{&b, &a, &c, nil, &a}}, newqf.Enums(map[string][]string{"E": {"b", "a", "c"}})),
+		},
+		{
+			name: "fill missing",
+			frames: []qframe.QFrame{
+				qframe.New(map[string]interface{}{"I": []int{1}, "B": []bool{true}}, newqf.ColumnOrder("I", "B")),
+				qframe.New(map[string]interface{}{"F": []float64{1.5}, "E": []*string{&a}}, newqf.ColumnOrder("F", "E"), newqf.Enums(map[string][]string{"E": nil})),
+			},
+			fill: true,
+			expected: qframe.New(map[string]interface{}{
+				"I": []*int{intPtr(1), nil},
+				"B": []*bool{boolPtr(true), nil},
+				"F": []float64{math.NaN(), 1.5},
+				"E": []*string{nil, &a},
+			}, newqf.ColumnOrder("I", "B", "F", "E"), newqf.Enums(map[string][]string{"E": {"a"}})),
+		},
+		{
+			name:     "no frames",
+			frames:   []qframe.QFrame{},
+			expected: qframe.New(map[string]interface{}{}),
+		},
+	}
+
+	for _, tc := range table {
+		t.Run(fmt.Sprintf("Concat %s", tc.name), func(t *testing.T) {
+			out := qframe.Concat(tc.frames...)
+			if tc.fill {
+				out = qframe.ConcatFillMissing(tc.frames...)
+			}
+			assertNotErr(t, out.Err)
+			assertEquals(t, tc.expected, out)
+		})
+	}
+
+	t.Run("Concat errors", func(t *testing.T) {
+		f1 := qframe.New(map[string]interface{}{"X": []int{1}})
+		f2 := qframe.New(map[string]interface{}{"X": []float64{1}})
+		f3 := qframe.New(map[string]interface{}{"Y": []int{1}})
+		assertErr(t, qframe.Concat(f1, f2).Err, "type mismatch")
+		assertErr(t, qframe.Concat(f1, f3).Err, "missing")
+		assertErr(t, qframe.Concat(f1, f1.Select("Z")).Err, "unknown column")
+	})
+}
+
+func TestQFrame_Window(t *testing.T) {
+	a, b := "a", "b"
+	nan := math.NaN()
+	input := qframe.New(map[string]interface{}{
+		"CUST": []*string{&a, &b, &a, &b, &a, nil},
+		"DAY":  []int{3, 1, 1, 2, 2, 1},
+		"VAL":  []int{5, 1, 2, 3, 2, 7},
+		"FVAL": []float64{1.5, nan, 2.5, 4.0, 0.5, 1.0},
+		"FLAG": []bool{true, false, false, true, true, false},
+	}, newqf.ColumnOrder("CUST", "DAY", "VAL", "FVAL", "FLAG"))
+
+	table := []struct {
+		name        string
+		partitionBy []string
+		orderBy     []qframe.Order
+		fn          qframe.WindowFunc
+		expected    interface{}
+	}{
+		{
+			name:        "cumsum int",
+			partitionBy: []string{"CUST"},
+			orderBy:     []qframe.Order{{Column: "DAY"}},
+			fn:          qframe.WindowFunc{Fn: "cumsum", Column: "VAL", As: "RES"},
+			expected:    []int{9, 1, 2, 4, 4, 7},
+		},
+		{
+			name:        "cumsum float skips null",
+			partitionBy: []string{"CUST"},
+			orderBy:     []qframe.Order{{Column: "DAY"}},
+			fn:          qframe.WindowFunc{Fn: "cumsum", Column: "FVAL", As: "RES"},
+			expected:    []float64{4.5, nan, 2.5, 4.0, 3.0, 1.0},
+		},
+		{
+			name:        "cummax int",
+			partitionBy: []string{"CUST"},
+			orderBy:     []qframe.Order{{Column: "DAY"}},
+			fn:          qframe.WindowFunc{Fn: "cummax", Column: "VAL", As: "RES"},
+			expected:    []int{5, 1, 2, 3, 2, 7},
+		},
+		{
+			name:        "cummax float",
+			partitionBy: []string{"CUST"},
+			orderBy:     []qframe.Order{{Column: "DAY", Reverse: true}},
+			fn:          qframe.WindowFunc{Fn: "cummax", Column: "FVAL", As: "RES"},
+			expected:    []float64{1.5, nan, 2.5, 4.0, 1.5, 1.0},
+		},
+		{
+			name:        "lag int",
+			partitionBy: []string{"CUST"},
+			orderBy:     []qframe.Order{{Column: "DAY"}},
+			fn:          qframe.WindowFunc{Fn: "lag", Column: "VAL", As: "RES"},
+			expected:    []*int{intPtr(2), nil, nil, intPtr(1), intPtr(2), nil},
+		},
+		{
+			name:        "lead string",
+			partitionBy: []string{"CUST"},
+			orderBy:     []qframe.Order{{Column: "DAY"}},
+			fn:          qframe.WindowFunc{Fn: "lead", Column: "CUST", As: "RES", Offset: 2},
+			expected:    []*string{nil, nil, &a, nil, nil, nil},
+		},
+		{
+			name:     "lag bool without partitions",
+			orderBy:  []qframe.Order{{Column: "DAY"}, {Column: "VAL"}},
+			fn:       qframe.WindowFunc{Fn: "lag", Column: "FLAG", As: "RES"},
+			expected: []*bool{boolPtr(true), nil, boolPtr(false), boolPtr(true), boolPtr(false), boolPtr(false)},
+		},
+		{
+			name:     "row_number",
+			orderBy:  []qframe.Order{{Column: "DAY"}, {Column: "VAL"}},
+			fn:       qframe.WindowFunc{Fn: "row_number", As: "RES"},
+			expected: []int{6, 1, 2, 5, 4, 3},
+		},
+		{
+			name:     "rank",
+			orderBy:  []qframe.Order{{Column: "DAY"}},
+			fn:       qframe.WindowFunc{Fn: "rank", As: "RES"},
+			expected: []int{6, 1, 1, 4, 4, 1},
+		},
+		{
+			name:     "dense_rank",
+			orderBy:  []qframe.Order{{Column: "DAY"}},
+			fn:       qframe.WindowFunc{Fn: "dense_rank", As: "RES"},
+			expected: []int{3, 1, 1, 2, 2, 1},
+		},
+		{
+			name:        "percent_rank",
+			partitionBy: []string{"CUST"},
+			orderBy:     []qframe.Order{{Column: "DAY", Reverse: true}},
+			fn:          qframe.WindowFunc{Fn: "percent_rank", As: "RES"},
+			expected:    []float64{0, 1, 1, 0, 0.5, 0},
+		},
+		{
+			name:     "row_number without order",
+			fn:       qframe.WindowFunc{Fn: "row_number", As: "RES"},
+			expected: []int{1, 2, 3, 4, 5, 6},
+		},
+	}
+
+	for _, tc := range table {
+		t.Run(fmt.Sprintf("Window %s", tc.name), func(t *testing.T) {
+			out := input.Window(tc.partitionBy, tc.orderBy, tc.fn)
+			assertNotErr(t, out.Err)
+			assertEquals(t, input, out.Drop("RES"))
+			assertEquals(t, qframe.New(map[string]interface{}{"RES": tc.expected}), out.Select("RES"))
+		})
+	}
+
+	t.Run("Window keeps row order of filtered and sorted frame", func(t *testing.T) {
+		in := input.Filter(qframe.Filter{Column: "CUST", Comparator: "isnotnull"}).Sort(qframe.Order{Column: "VAL", Reverse: true}, qframe.Order{Column: "DAY"})
+		out := in.Window([]string{"CUST"}, []qframe.Order{{Column: "DAY"}},
+			qframe.WindowFunc{Fn: "row_number", As: "RN"},
+			qframe.WindowFunc{Fn: "cumsum", Column: "VAL", As: "CS"})
+		expected := qframe.New(map[string]interface{}{
+			"VAL": []int{5, 3, 2, 2, 1},
+			"RN":  []int{3, 2, 1, 2, 1},
+			"CS":  []int{9, 4, 2, 4, 1},
+		}, newqf.ColumnOrder("VAL", "RN", "CS"))
+		assertEquals(t, expected, out.Select("VAL", "RN", "CS"))
+	})
+
+	t.Run("Window errors", func(t *testing.T) {
+		assertErr(t, input.Window([]string{"FOO"}, nil, qframe.WindowFunc{Fn: "row_number", As: "RES"}).Err, "unknown column")
+		assertErr(t, input.Window(nil, []qframe.Order{{Column: "FOO"}}, qframe.WindowFunc{Fn: "row_number", As: "RES"}).Err, "unknown column")
+		assertErr(t, input.Window(nil, nil, qframe.WindowFunc{Fn: "cumsum", Column: "FOO", As: "RES"}).Err, "unknown column")
+		assertErr(t, input.Window(nil, nil, qframe.WindowFunc{Fn: "cumsum", Column: "CUST", As: "RES"}).Err, "not supported")
+		assertErr(t, input.Window(nil, nil, qframe.WindowFunc{Fn: "foo", Column: "VAL", As: "RES"}).Err, "unknown window function")
+		assertErr(t, input.Window(nil, nil, qframe.WindowFunc{Fn: "lag", Column: "VAL", As: "RES", Offset: -1}).Err, "must not be negative")
+		assertErr(t, input.Window(nil, nil, qframe.WindowFunc{Fn: "row_number"}).Err, "must not be empty")
+	})
+}
+
+func TestQFrame_Rolling(t *testing.T) {
+	nan := math.NaN()
+	input := qframe.New(map[string]interface{}{
+		"I": []int{1, 2, 3, 4, 5},
+		"F": []float64{1, nan, 3, 4, 5},
+	})
+
+	maxFn := func(xs []float64) float64 {
+		result := xs[0]
+		for _, x := range xs[1:] {
+			result = math.Max(result, x)
+		}
+		return result
+	}
+
+	table := []struct {
+		name     string
+		src      string
+		window   int
+		fn       interface{}
+		configs  []rolling.ConfigFunc
+		expected []float64
+	}{
+		{name: "sum", src: "I", window: 2, fn: "sum", expected: []float64{nan, 3, 5, 7, 9}},
+		{name: "mean", src: "I", window: 3, fn: "mean", expected: []float64{nan, nan, 2, 3, 4}},
+		{name: "count", src: "F", window: 2, fn: "count", configs: []rolling.ConfigFunc{rolling.MinPeriods(1)}, expected: []float64{1, 1, 1, 2, 2}},
+		{name: "sum with nulls", src: "F", window: 2, fn: "sum", expected: []float64{nan, nan, nan, 7, 9}},
+		{name: "min periods", src: "F", window: 3, fn: "sum", configs: []rolling.ConfigFunc{rolling.MinPeriods(1)}, expected: []float64{1, 1, 4, 7, 12}},
+		{name: "centered odd", src: "I", window: 3, fn: "sum", configs: []rolling.ConfigFunc{rolling.Center(true)}, expected: []float64{nan, 6, 9, 12, nan}},
+		{name: "centered even", src: "I", window: 4, fn: "mean", configs: []rolling.ConfigFunc{rolling.Center(true), rolling.MinPeriods(3)}, expected: []float64{nan, 2, 2.5, 3.5, 4}},
+		{name: "custom function", src: "F", window: 2, fn: maxFn, configs: []rolling.ConfigFunc{rolling.MinPeriods(1)}, expected: []float64{1, 1, 3, 4, 5}},
+		{name: "window larger than frame", src: "I", window: 10, fn: "sum", configs: []rolling.ConfigFunc{rolling.MinPeriods(5)}, expected: []float64{nan, nan, nan, nan, 15}},
+	}
+
+	for _, tc := range table {
+		t.Run(fmt.Sprintf("Rolling %s", tc.name), func(t *testing.T) {
+			out := input.Rolling("RES", tc.src, tc.window, tc.fn, tc.configs...)
+			assertNotErr(t, out.Err)
+			assertEquals(t, qframe.New(map[string]interface{}{"RES": tc.expected}), out.Select("RES"))
+		})
+	}
+
+	t.Run("Rolling follows row order", func(t *testing.T) {
+		out := input.Sort(qframe.Order{Column: "I", Reverse: true}).Rolling("RES", "I", 2, "sum", rolling.MinPeriods(1))
+		expected := qframe.New(map[string]interface{}{"I": []int{5, 4, 3, 2, 1}, "RES": []float64{5, 9, 7, 5, 3}}, newqf.ColumnOrder("I", "RES"))
+		assertEquals(t, expected, out.Select("I", "RES"))
+	})
+
+	t.Run("Rolling errors", func(t *testing.T) {
+		assertErr(t, input.Rolling("RES", "FOO", 2, "sum").Err, "unknown column")
+		assertErr(t, input.Rolling("RES", "I", 0, "sum").Err, "window must be positive")
+		assertErr(t, input.Rolling("RES", "I", 2, "sum", rolling.MinPeriods(3)).Err, "min periods")
+		assertErr(t, input.Rolling("RES", "I", 2, "foo").Err, "unknown rolling function")
+		assertErr(t, input.Rolling("RES", "I", 2, func(x []int) int { return 0 }).Err, "invalid rolling function type")
+		s := qframe.New(map[string]interface{}{"S": []string{"a"}})
+		assertErr(t, s.Rolling("RES", "S", 2, "sum").Err, "unsupported column type")
+	})
+}
+
+func TestQFrame_Pivot(t *testing.T) {
+	a, b, x, y := "a", "b", "x", "y"
+	nan := math.NaN()
+	input := qframe.New(map[string]interface{}{
+		"ID":   []int{2, 1, 1, 2, 1, 3},
+		"KEY":  []*string{&b, &a, &b, &b, &a, nil},
+		"VAL":  []int{1, 2, 3, 4, 5, 6},
+		"NAME": []*string{&x, &y, &x, &y, &x, &x},
+	})
+
+	table := []struct {
+		name     string
+		configs  []newqf.ConfigFunc
+		values   string
+		agg      interface{}
+		expected qframe.QFrame
+	}{
+		{
+			name:   "missing combinations are null",
+			values: "VAL",
+			agg:    "sum",
+			expected: qframe.New(map[string]interface{}{
+				"ID": []int{1, 2},
+				"a":  []*int{intPtr(7), nil},
+				"b":  []int{3, 5},
+			}, newqf.ColumnOrder("ID", "a", "b")),
+		},
+		{
+			name:    "enum columns ordered by enum values",
+			configs: []newqf.ConfigFunc{newqf.Enums(map[string][]string{"KEY": {"b", "a"}})},
+			values:  "NAME",
+			agg:     "first",
+			expected: qframe.New(map[string]interface{}{
+				"ID": []int{1, 2},
+				"b":  []*string{&x, &x},
+				"a":  []*string{&y, nil},
+			}, newqf.ColumnOrder("ID", "b", "a")),
+		},
+		{
+			name:   "user defined aggregation",
+			values: "VAL",
+			agg: func(xs []int) float64 {
+				return float64(len(xs)) / 2
+			},
+			expected: qframe.New(map[string]interface{}{
+				"ID": []int{1, 2},
+				"a":  []float64{1, nan},
+				"b":  []float64{0.5, 1},
+			}, newqf.ColumnOrder("ID", "a", "b")),
+		},
+	}
+
+	for _, tc := range table {
+		t.Run(fmt.Sprintf("Pivot %s", tc.name), func(t *testing.T) {
+			in := qframe.New(map[string]interface{}{
+				"ID":   []int{2, 1, 1, 2, 1, 3},
+				"KEY":  []*string{&b, &a, &b, &b, &a, nil},
+				"VAL":  []int{1, 2, 3, 4, 5, 6},
+				"NAME": []*string{&x, &y, &x, &y, &x, &x},
+			}, tc.configs...)
+			out := in.Pivot("ID", "KEY", tc.values, tc.agg)
+			assertNotErr(t, out.Err)
+			assertEquals(t, tc.expected, out)
+		})
+	}
+
+	t.Run("Pivot errors", func(t *testing.T) {
+		assertErr(t, input.Pivot("ID", "FOO", "VAL", "sum").Err, "unknown column")
+		assertErr(t, input.Pivot("ID", "VAL", "VAL", "sum").Err, "must be a string or enum column")
+		assertErr(t, input.Pivot("ID", "KEY", "NAME", "sum").Err, "sum is not defined")
+		dollar, id := "$a", "ID"
+		in := qframe.New(map[string]interface{}{"ID": []int{1}, "KEY": []*string{&dollar}, "VAL": []int{1}})
+		assertErr(t, in.Pivot("ID", "KEY", "VAL", "sum").Err, "must not start with $")
+		in = qframe.New(map[string]interface{}{"ID": []int{1}, "KEY": []*string{&id}, "VAL": []int{1}})
+		assertErr(t, in.Pivot("ID", "KEY", "VAL", "sum").Err, "duplicate column name")
+	})
+}
+
+func TestQFrame_Melt(t *testing.T) {
+	a, b, c := "A", "B", "C"
+	input := qframe.New(map[string]interface{}{
+		"ID": []int{1, 2},
+		"A":  []int{1, 2},
+		"B":  []int{3, 4},
+		"C":  []float64{1.5, 2.5},
+	}, newqf.ColumnOrder("ID", "A", "B", "C"))
+
+	expected := qframe.New(map[string]interface{}{
+		"ID":       []int{1, 2, 1, 2},
+		"variable": []*string{&a, &a, &b, &b},
+		"value":    []int{1, 2, 3, 4},
+	}, newqf.ColumnOrder("ID", "variable", "value"))
+	assertEquals(t, expected, input.Melt([]string{"ID"}, []string{"A", "B"}))
+	assertEquals(t, expected, input.Drop("C").Melt([]string{"ID"}, nil))
+
+	t.Run("Melt sorted frame", func(t *testing.T) {
+		out := input.Sort(qframe.Order{Column: "ID", Reverse: true}).Melt(nil, []string{"C"})
+		expected := qframe.New(map[string]interface{}{
+			"variable": []*string{&c, &c},
+			"value":    []float64{2.5, 1.5},
+		}, newqf.ColumnOrder("variable", "value"))
+		assertEquals(t, expected, out)
+	})
+
+	t.Run("Melt errors", func(t *testing.T) {
+		assertErr(t, input.Melt([]string{"FOO"}, nil).Err, "unknown column")
+		assertErr(t, input.Melt(nil, []string{"FOO"}).Err, "unknown column")
+		assertErr(t, input.Melt([]string{"ID"}, nil).Err, "type mismatch")
+		assertErr(t, input.Select("ID").Melt([]string{"ID"}, nil).Err, "no value columns")
+		assertErr(t, input.Rename(map[string]string{"ID": "value"}).Melt([]string{"value"}, []string{"A"}).Err, "duplicate column name")
+	})
+}
@@ -0,0 +1,146 @@
+package qframe
+
+import (
+	"time"
+
+	"github.com/tobgu/qframe/config/groupby"
+	"github.com/tobgu/qframe/errors"
+	"github.com/tobgu/qframe/filter"
+	"github.com/tobgu/qframe/internal/tcolumn"
+)
+
+// Resample groups the rows of the QFrame into buckets of equal length in time and
+// aggregates each bucket, for example to compute daily or five minute aggregates.
+//
+// timeCol - Name of the time column to bucket rows by. In the result this column holds
+// the start of each bucket. Rows where this column is null are ignored.
+//
+// interval - The length of each bucket. Buckets are aligned to the wall clock of the
+// location of timeCol, daily buckets start at midnight for example.
+//
+// aggs - Aggregations to apply to each bucket, see Grouper.Aggregate.
+//
+// The result contains one row per non empty bucket, sorted by time. Use FillGaps to
+// add rows for empty buckets.
+//
+// Time complexity O(m * n) where m = number of aggregations, n = number of rows.
+func (qf QFrame) Resample(timeCol string, interval time.Duration, aggs ...Aggregation) QFrame {
+	if qf.Err != nil {
+		return qf
+	}
+
+	if _, err := qf.timeColumn("Resample", timeCol, interval); err != nil {
+		return qf.withErr(err)
+	}
+
+	bucket := func(t time.Time) time.Time {
+		return bucketStart(t, interval)
+	}
+
+	result := qf.Filter(Filter{Column: timeCol, Comparator: filter.IsNotNull})
+	result = result.Apply(Instruction{Fn: bucket, DstCol: timeCol, SrcCol1: timeCol})
+	result = result.GroupBy(groupby.Columns(timeCol)).Aggregate(aggs...)
+	result = result.Sort(Order{Column: timeCol})
+	if result.Err != nil {
+		return qf.withErr(errors.Propagate("Resample", result.Err))
+	}
+
+	return result
+}
+
+// FillGaps adds rows for all buckets missing between the first and the last bucket
+// of timeCol. It is intended to be used on the result of Resample.
+//
+// timeCol - Name of the time column holding the start of each bucket.
+//
+// interval - The length of each bucket, see Resample.
+//
+// All other columns are null in the added rows. The result is sorted by time.
+//
+// Time complexity O(m * (n + b)) where m = number of columns, n = number of rows,
+// b = number of buckets.
+func (qf QFrame) FillGaps(timeCol string, interval time.Duration) QFrame {
+	if qf.Err != nil {
+		return qf
+	}
+
+	col, err := qf.timeColumn("FillGaps", timeCol, interval)
+	if err != nil {
+		return qf.withErr(err)
+	}
+
+	view := col.View(qf.index)
+	present := make(map[int64]struct{}, view.Len())
+	var first, last time.Time
+	for i := 0; i < view.Len(); i++ {
+		if view.IsNull(i) {
+			continue
+		}
+
+		t := bucketStart(view.ItemAt(i), interval)
+		if len(present) == 0 || t.Before(first) {
+			first = t
+		}
+		if len(present) == 0 || t.After(last) {
+			last = t
+		}
+		present[t.UnixNano()] = struct{}{}
+	}
+
+	missing := make([]time.Time, 0)
+	for t := first; len(present) > 0 && !t.After(last); t = fromWall(wallNanos(t)+int64(interval), t.Location()) {
+		if _, ok := present[t.UnixNano()]; !ok {
+			missing = append(missing, t)
+		}
+	}
+
+	gaps := New(map[string]interface{}{timeCol: tcolumn.New(missing).In(col.Location())})
+	result := ConcatFillMissing(qf, gaps).Sort(Order{Column: timeCol})
+	if result.Err != nil {
+		return qf.withErr(errors.Propagate("FillGaps", result.Err))
+	}
+
+	return result
+}
+
+func (qf QFrame) timeColumn(op, colName string, interval time.Duration) (tcolumn.Column, error) {
+	if interval <= 0 {
+		return tcolumn.Column{}, errors.New(op, "interval must be positive: %s", interval)
+	}
+
+	namedColumn, ok := qf.columnsByName[colName]
+	if !ok {
+		return tcolumn.Column{}, errors.New(op, unknownCol(colName))
+	}
+
+	col, ok := namedColumn.Column.(tcolumn.Column)
+	if !ok {
+		return tcolumn.Column{}, errors.New(op, "column must be a time column, was: %s", namedColumn.DataType())
+	}
+
+	return col, nil
+}
+
+// bucketStart truncates t to a multiple of interval, counted on the wall clock of
+// the location of t.
+func bucketStart(t time.Time, interval time.Duration) time.Time {
+	wall := wallNanos(t)
+	start := wall - wall%int64(interval)
+	if wall%int64(interval) < 0 {
+		start -= int64(interval)
+	}
+
+	return fromWall(start, t.Location())
+}
+
+// wallNanos returns the wall clock of t as nanoseconds since the epoch.
+func wallNanos(t time.Time) int64 {
+	_, offset := t.Zone()
+	return t.UnixNano() + int64(offset)*int64(time.Second)
+}
+
+// fromWall returns the time in loc with the wall clock given as nanoseconds since the epoch.
+func fromWall(wall int64, loc *time.Location) time.Time {
+	w := time.Unix(0, wall).UTC()
+	return time.Date(w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(), w.Second(), w.Nanosecond(), loc)
+}
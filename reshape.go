@@ -0,0 +1,219 @@
+package qframe
+
+import (
+	"github.com/tobgu/qframe/errors"
+	"github.com/tobgu/qframe/filter"
+	"github.com/tobgu/qframe/internal/column"
+	"github.com/tobgu/qframe/internal/grouper"
+	"github.com/tobgu/qframe/internal/index"
+	"github.com/tobgu/qframe/internal/scolumn"
+	qfsort "github.com/tobgu/qframe/internal/sort"
+	qfstrings "github.com/tobgu/qframe/internal/strings"
+	"github.com/tobgu/qframe/types"
+)
+
+// Pivot reshapes the QFrame from long to wide format.
+//
+// indexCol - Name of the column whose distinct values make up the rows of the result. The result is sorted by this column.
+//
+// columnsCol - Name of the string or enum column whose distinct values make up the new columns of the result.
+// The new columns are ordered according to these values. Rows where this column is null are ignored.
+//
+// valuesCol - Name of the column holding the values to populate the new columns with.
+//
+// agg - Aggregation function used to combine values for rows with the same indexCol and columnsCol values.
+// Any function accepted by Grouper.Aggregate can be used.
+//
+// Combinations of indexCol and columnsCol values not present in the QFrame produce null.
+//
+// Time complexity O(m * n) where m = number of new columns, n = number of rows.
+func (qf QFrame) Pivot(indexCol, columnsCol, valuesCol string, agg types.SliceFuncOrBuiltInId) QFrame {
+	if qf.Err != nil {
+		return qf
+	}
+
+	if err := qf.checkColumns("Pivot", []string{indexCol, columnsCol, valuesCol}); err != nil {
+		return qf.withErr(err)
+	}
+
+	colType := qf.columnsByName[columnsCol].DataType()
+	if colType != types.String && colType != types.Enum {
+		return qf.withErr(errors.New("Pivot", "columns must be a string or enum column, was: %s", colType))
+	}
+
+	qf = qf.Filter(Filter{Column: columnsCol, Comparator: filter.IsNotNull})
+	if qf.Err != nil {
+		return qf
+	}
+
+	if qf.Len() == 0 {
+		return qf.Select(indexCol)
+	}
+
+	rowCol, colCol, valCol := qf.columnsByName[indexCol], qf.columnsByName[columnsCol], qf.columnsByName[valuesCol]
+	dataLen := rowCol.Len()
+
+	// Number the distinct values of the index and columns columns according to their sort order
+	rowIx, rowOf := pivotKeys(qf.index, rowCol, dataLen)
+	colIx, colOf := pivotKeys(qf.index, colCol, dataLen)
+
+	groups, _ := grouper.GroupBy(qf.index, []column.Comparable{rowCol.Comparable(false, true), colCol.Comparable(false, true)})
+	aggData, err := valCol.Aggregate(groups, agg)
+	if err != nil {
+		return qf.withErr(errors.Propagate("Pivot", err))
+	}
+
+	aggCol, err := sliceToColumn(aggData)
+	if err != nil {
+		return qf.withErr(errors.Propagate("Pivot", err))
+	}
+
+	// ixs[i][j] holds the position of the aggregate for row j in new column i
+	ixs := make([]index.Int, len(colIx))
+	for i := range ixs {
+		ixs[i] = make(index.Int, len(rowIx))
+		for j := range ixs[i] {
+			ixs[i][j] = noMatch
+		}
+	}
+
+	for g, ix := range groups {
+		ixs[colOf[ix[0]]][rowOf[ix[0]]] = uint32(g)
+	}
+
+	newColumns := make([]namedColumn, 0, len(colIx)+1)
+	newColumnsByName := make(map[string]namedColumn, len(colIx)+1)
+	rowCol.Column = rowCol.Subset(rowIx)
+	rowCol.pos = 0
+	newColumns = append(newColumns, rowCol)
+	newColumnsByName[indexCol] = rowCol
+
+	for i, pos := range colIx {
+		name := colCol.StringAt(pos, "")
+		if err := qfstrings.CheckName(name); err != nil {
+			return qf.withErr(errors.Propagate("Pivot", err))
+		}
+
+		if _, ok := newColumnsByName[name]; ok {
+			return qf.withErr(errors.New("Pivot", "duplicate column name in result: %s", name))
+		}
+
+		col, err := nullableSubset(aggCol, ixs[i])
+		if err != nil {
+			return qf.withErr(errors.Propagate("Pivot", err))
+		}
+
+		newColumns = append(newColumns, namedColumn{Column: col, name: name, pos: len(newColumns)})
+		newColumnsByName[name] = newColumns[len(newColumns)-1]
+	}
+
+	return QFrame{columns: newColumns, columnsByName: newColumnsByName, index: index.NewAscending(uint32(len(rowIx)))}
+}
+
+// pivotKeys returns the position of the first row of each distinct value in col, in sort order,
+// together with a mapping from position in col to the number of the distinct value at that position.
+func pivotKeys(ix index.Int, col namedColumn, dataLen int) (index.Int, []uint32) {
+	groups, _ := grouper.GroupBy(ix, []column.Comparable{col.Comparable(false, true)})
+	firstIx := make(index.Int, len(groups))
+	for i, g := range groups {
+		firstIx[i] = g[0]
+	}
+
+	qfsort.New(firstIx, []column.Comparable{col.Comparable(false, false)}).Sort()
+	keyOf := make([]uint32, dataLen)
+	for i, pos := range firstIx {
+		keyOf[pos] = uint32(i)
+	}
+
+	for _, g := range groups {
+		for _, pos := range g[1:] {
+			keyOf[pos] = keyOf[g[0]]
+		}
+	}
+
+	return firstIx, keyOf
+}
+
+// Melt reshapes the QFrame from wide to long format.
+//
+// idCols - Names of the columns that identify each row. These are repeated for each value column.
+//
+// valueCols - Names of the columns to unpivot. All columns not in idCols are used if no columns are given.
+// All value columns must have the same type. Enum columns with different values are allowed.
+//
+// The result contains the idCols followed by a string column named "variable" holding the name of the
+// value column and a column named "value" holding the value. The rows are ordered by value column first
+// and then by the order of the QFrame.
+//
+// Time complexity O(m * n) where m = number of columns, n = number of rows.
+func (qf QFrame) Melt(idCols, valueCols []string) QFrame {
+	if qf.Err != nil {
+		return qf
+	}
+
+	if err := qf.checkColumns("Melt", idCols); err != nil {
+		return qf.withErr(err)
+	}
+
+	if err := qf.checkColumns("Melt", valueCols); err != nil {
+		return qf.withErr(err)
+	}
+
+	if len(valueCols) == 0 {
+		idSet := qfstrings.NewStringSet(idCols)
+		for _, c := range qf.columns {
+			if !idSet.Contains(c.name) {
+				valueCols = append(valueCols, c.name)
+			}
+		}
+	}
+
+	if len(valueCols) == 0 {
+		return qf.withErr(errors.New("Melt", "no value columns"))
+	}
+
+	repeatedIx := make(index.Int, 0, len(valueCols)*qf.Len())
+	frames := make([]QFrame, len(valueCols))
+	variables := make([]*string, 0, len(valueCols)*qf.Len())
+	for i, name := range valueCols {
+		repeatedIx = append(repeatedIx, qf.index...)
+		frames[i] = qf.Select(name).Rename(map[string]string{name: "value"})
+		name := name
+		for range qf.index {
+			variables = append(variables, &name)
+		}
+	}
+
+	newColumns := make([]namedColumn, 0, len(idCols)+2)
+	newColumnsByName := make(map[string]namedColumn, len(idCols)+2)
+	addColumn := func(name string, col column.Column) error {
+		if _, ok := newColumnsByName[name]; ok {
+			return errors.New("Melt", "duplicate column name in result: %s", name)
+		}
+
+		newColumns = append(newColumns, namedColumn{Column: col, name: name, pos: len(newColumns)})
+		newColumnsByName[name] = newColumns[len(newColumns)-1]
+		return nil
+	}
+
+	for _, name := range idCols {
+		if err := addColumn(name, qf.columnsByName[name].Subset(repeatedIx)); err != nil {
+			return qf.withErr(err)
+		}
+	}
+
+	if err := addColumn("variable", scolumn.New(variables)); err != nil {
+		return qf.withErr(err)
+	}
+
+	valueCol, err := concatColumn("value", frames)
+	if err != nil {
+		return qf.withErr(errors.Propagate("Melt", err))
+	}
+
+	if err := addColumn("value", valueCol); err != nil {
+		return qf.withErr(err)
+	}
+
+	return QFrame{columns: newColumns, columnsByName: newColumnsByName, index: index.NewAscending(uint32(len(repeatedIx)))}
+}
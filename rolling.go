@@ -0,0 +1,170 @@
+package qframe
+
+import (
+	"math"
+
+	"github.com/tobgu/qframe/config/rolling"
+	"github.com/tobgu/qframe/errors"
+	"github.com/tobgu/qframe/internal/fcolumn"
+	"github.com/tobgu/qframe/internal/icolumn"
+	"github.com/tobgu/qframe/types"
+)
+
+// Rolling applies fn to a window of rows moving over srcCol and stores the result in dstCol.
+// The rows are visited in the order of the QFrame. The window for a row ends at that row,
+// see rolling.Center for centered windows.
+//
+// dstCol - Name of the column to store the result in. The result is always a float column.
+//
+// srcCol - Name of the int or float column to read values from.
+//
+// window - The number of rows in each window.
+//
+// fn - Either a func([]float64) float64, or the name of one of the built in functions "sum", "mean"
+// or "count". Null values are never passed to fn and are not counted by "count".
+// Windows with fewer non null values than specified by rolling.MinPeriods produce null.
+//
+// Time complexity O(n) for the built in functions, O(n * w) for custom functions where
+// n = number of rows, w = window size.
+func (qf QFrame) Rolling(dstCol, srcCol string, window int, fn types.SliceFuncOrBuiltInId, configFns ...rolling.ConfigFunc) QFrame {
+	if qf.Err != nil {
+		return qf
+	}
+
+	config := rolling.NewConfig(configFns)
+	if window < 1 {
+		return qf.withErr(errors.New("Rolling", "window must be positive: %d", window))
+	}
+
+	minPeriods := config.MinPeriods
+	if minPeriods == 0 {
+		minPeriods = window
+	}
+
+	if minPeriods < 0 || minPeriods > window {
+		return qf.withErr(errors.New("Rolling", "min periods must be between 1 and window size %d: %d", window, minPeriods))
+	}
+
+	namedColumn, ok := qf.columnsByName[srcCol]
+	if !ok {
+		return qf.withErr(errors.New("Rolling", unknownCol(srcCol)))
+	}
+
+	values := make([]float64, qf.Len())
+	switch c := namedColumn.Column.(type) {
+	case icolumn.Column:
+		view := c.View(qf.index)
+		for i := range values {
+			if view.IsNull(i) {
+				values[i] = math.NaN()
+			} else {
+				values[i] = float64(view.ItemAt(i))
+			}
+		}
+	case fcolumn.Column:
+		view := c.View(qf.index)
+		for i := range values {
+			values[i] = view.ItemAt(i)
+		}
+	default:
+		return qf.withErr(errors.New("Rolling", "unsupported column type: %s", namedColumn.DataType()))
+	}
+
+	start := -(window - 1)
+	if config.Center {
+		start = -(window / 2)
+	}
+
+	var result []float64
+	switch t := fn.(type) {
+	case string:
+		if t != "sum" && t != "mean" && t != "count" {
+			return qf.withErr(errors.New("Rolling", "unknown rolling function: %s", t))
+		}
+		result = rollingSum(values, start, window, minPeriods, t)
+	case func([]float64) float64:
+		result = rollingApply(values, start, window, minPeriods, t)
+	default:
+		return qf.withErr(errors.New("Rolling", "invalid rolling function type: %v", t))
+	}
+
+	data := make([]float64, namedColumn.Len())
+	for i, pos := range qf.index {
+		data[pos] = result[i]
+	}
+
+	return qf.setColumn(dstCol, fcolumn.New(data))
+}
+
+// windowBounds returns the first and last position, inclusive, of the window for row i.
+func windowBounds(i, start, window, length int) (int, int) {
+	lo, hi := i+start, i+start+window-1
+	if lo < 0 {
+		lo = 0
+	}
+
+	if hi > length-1 {
+		hi = length - 1
+	}
+
+	return lo, hi
+}
+
+// rollingSum calculates "sum", "mean" or "count" incrementally by adding values entering
+// the window and subtracting values leaving it.
+func rollingSum(values []float64, start, window, minPeriods int, fn string) []float64 {
+	result := make([]float64, len(values))
+	sum, count := 0.0, 0
+	addPos, removePos := 0, 0
+	for i := range values {
+		lo, hi := windowBounds(i, start, window, len(values))
+		for ; addPos <= hi; addPos++ {
+			if v := values[addPos]; !math.IsNaN(v) {
+				sum += v
+				count++
+			}
+		}
+
+		for ; removePos < lo; removePos++ {
+			if v := values[removePos]; !math.IsNaN(v) {
+				sum -= v
+				count--
+			}
+		}
+
+		switch {
+		case count < minPeriods:
+			result[i] = math.NaN()
+		case fn == "sum":
+			result[i] = sum
+		case fn == "mean":
+			result[i] = sum / float64(count)
+		default:
+			result[i] = float64(count)
+		}
+	}
+
+	return result
+}
+
+func rollingApply(values []float64, start, window, minPeriods int, fn func([]float64) float64) []float64 {
+	result := make([]float64, len(values))
+	buf := make([]float64, 0, window)
+	for i := range values {
+		lo, hi := windowBounds(i, start, window, len(values))
+		buf = buf[:0]
+		for _, v := range values[lo : hi+1] {
+			if !math.IsNaN(v) {
+				buf = append(buf, v)
+			}
+		}
+
+		if len(buf) < minPeriods {
+			result[i] = math.NaN()
+		} else {
+			result[i] = fn(buf)
+		}
+	}
+
+	return result
+}
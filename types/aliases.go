@@ -13,19 +13,25 @@ DataSlice can be a slice of any of the supported data types.
 
 The following types are currently supported:
 	[]bool
+	[]*bool
 	[]float64
 	[]int
+	[]*int
 	[]string
 	[]*string
+	[]time.Time
+	[]*time.Time
 */
 type DataSlice = interface{}
 
 /*
-SliceFuncOrBuiltInId can be a function taking a slice of type T and returning a value of type T.
+SliceFuncOrBuiltInId can be a function taking a slice of type T and returning a value of type U.
+U can be any of int, float64, bool or *string.
 
 For example:
 	func(x []float64) float64
 	func(x []int) int
+	func(x []int) float64
 	func(x []*string) *string
 	func(x []bool) bool
 
@@ -33,6 +39,7 @@ Or it can be a string identifying a built in function.
 
 For example:
     "sum"
+    "mean"
 
 IMPORTANT: Reference arguments (eg. slices) must never be assumed to be valid after that the passed function returns.
 Under the hood reuse and other performance enhancements may trigger unexpected behaviour if this is ever done.
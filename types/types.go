@@ -8,7 +8,8 @@ const (
 	// This is mainly used to indicate that the type of a column should be auto detected.
 	None DataType = ""
 
-	// Int translates into the Go int type. Missing values cannot be represented explicitly.
+	// Int translates into the Go int type. Missing values are tracked separately from the values,
+	// when creating a QFrame from a []*int nil represents a missing value.
 	Int = "int"
 
 	// String translates into the Go *string type. nil represents a missing value.
@@ -19,12 +20,20 @@ const (
 	// Float translates into the Go float64 type. NaN represents a missing value.
 	Float = "float"
 
-	// Bool translates into the Go bool type. Missing values cannot be represented explicitly.
+	// Bool translates into the Go bool type. Missing values are tracked separately from the values,
+	// when creating a QFrame from a []*bool nil represents a missing value.
 	Bool = "bool"
 
 	// Enum translates into the Go *string type. nil represents a missing value.
-	// An enum column can, at most, have 254 distinct values.
+	// Values are stored as uint8, uint16 or uint32 codes depending on the number of distinct
+	// values in the column, the codes are widened automatically as values are added.
 	Enum = "enum"
+
+	// Time translates into the Go time.Time type. Values are stored as nanoseconds since
+	// the Unix epoch together with a location (time zone) shared by all values in the column.
+	// This limits the values to the years 1678 - 2262. Missing values are tracked separately
+	// from the values, when creating a QFrame from a []*time.Time nil represents a missing value.
+	Time = "time"
 )
 
 // FunctionType represents the different types of input that functions operating on columns can take.
@@ -36,6 +45,7 @@ const (
 	FunctionTypeFloat
 	FunctionTypeBool
 	FunctionTypeString
+	FunctionTypeTime
 )
 
 func (t FunctionType) String() string {
@@ -48,6 +58,8 @@ func (t FunctionType) String() string {
 		return "String function"
 	case FunctionTypeFloat:
 		return "Float function"
+	case FunctionTypeTime:
+		return "Time function"
 	default:
 		return "Unknown function"
 	}
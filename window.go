@@ -0,0 +1,259 @@
+package qframe
+
+import (
+	"math"
+
+	"github.com/tobgu/qframe/errors"
+	"github.com/tobgu/qframe/internal/bitmap"
+	"github.com/tobgu/qframe/internal/column"
+	"github.com/tobgu/qframe/internal/fcolumn"
+	"github.com/tobgu/qframe/internal/grouper"
+	"github.com/tobgu/qframe/internal/icolumn"
+	"github.com/tobgu/qframe/internal/index"
+	qfsort "github.com/tobgu/qframe/internal/sort"
+	qfstrings "github.com/tobgu/qframe/internal/strings"
+)
+
+// WindowFunc represents a function to apply over the rows of a partition in a window operation.
+type WindowFunc struct {
+	// Fn is the name of the window function to apply. The following functions are available:
+	//
+	// cumsum - Cumulative sum of Column. Int and float columns only. Null values are skipped.
+	//
+	// cummax - Cumulative max of Column. Int and float columns only. Null values are skipped.
+	//
+	// lag - Value of Column Offset rows before the current row. Null for rows without such a row.
+	//
+	// lead - Value of Column Offset rows after the current row. Null for rows without such a row.
+	//
+	// row_number - Sequential number of the row within the partition, starting at 1.
+	//
+	// rank - Rank of the row within the partition, with gaps for rows that are equal with respect to the order.
+	//
+	// dense_rank - Rank of the row within the partition, without gaps for rows that are equal with respect to the order.
+	//
+	// percent_rank - Relative rank of the row within the partition, (rank - 1) / (number of rows - 1).
+	Fn string
+
+	// Column is the name of the column to apply the function to.
+	// Not used by the ranking functions row_number, rank, dense_rank and percent_rank.
+	Column string
+
+	// As is the name of the column in which the result is stored. This field is mandatory.
+	As string
+
+	// Offset is the number of rows to look back or ahead for lag and lead. Default is 1.
+	Offset int
+}
+
+func (w WindowFunc) offset() int {
+	if w.Offset == 0 {
+		return 1
+	}
+	return w.Offset
+}
+
+// Window applies fns to the rows of each partition of the QFrame, ordered according to orderBy.
+// The result of each function is stored in a new column. The order of the rows in the QFrame is
+// not changed.
+//
+// partitionBy - Names of the columns to partition the rows by. Rows with equal values in all
+// these columns, including null, belong to the same partition. If no columns are given all
+// rows belong to the same partition.
+//
+// orderBy - The order of the rows within each partition. If no order is given the order of
+// the rows in the QFrame is used. The relative order of rows that are equal with respect to
+// orderBy is undefined.
+//
+// Time complexity O(m * n * log(n)) where m = number of columns to order by, n = number of rows.
+func (qf QFrame) Window(partitionBy []string, orderBy []Order, fns ...WindowFunc) QFrame {
+	if qf.Err != nil {
+		return qf
+	}
+
+	if err := qf.checkColumns("Window", partitionBy); err != nil {
+		return qf.withErr(err)
+	}
+
+	orderComparables := make([]column.Comparable, 0, len(orderBy))
+	tieComparables := make([]column.Comparable, 0, len(orderBy))
+	for _, o := range orderBy {
+		s, ok := qf.columnsByName[o.Column]
+		if !ok {
+			return qf.withErr(errors.New("Window", unknownCol(o.Column)))
+		}
+
+		orderComparables = append(orderComparables, s.Comparable(o.Reverse, false))
+		tieComparables = append(tieComparables, s.Comparable(o.Reverse, true))
+	}
+
+	for _, fn := range fns {
+		if err := qfstrings.CheckName(fn.As); err != nil {
+			return qf.withErr(errors.Propagate("Window", err))
+		}
+	}
+
+	partitions := []index.Int{qf.index.Copy()}
+	if len(partitionBy) > 0 && qf.Len() > 0 {
+		partitions, _ = grouper.GroupBy(qf.index, qf.comparables(partitionBy, qf.orders(partitionBy), true))
+	}
+
+	if len(orderComparables) > 0 {
+		for _, p := range partitions {
+			qfsort.New(p, orderComparables).Sort()
+		}
+	}
+
+	dataLen := 0
+	if len(qf.columns) > 0 {
+		dataLen = qf.columns[0].Len()
+	}
+
+	result := qf
+	for _, fn := range fns {
+		col, err := qf.windowColumn(fn, partitions, tieComparables, dataLen)
+		if err != nil {
+			return qf.withErr(errors.Propagate("Window", err))
+		}
+
+		result = result.setColumn(fn.As, col)
+	}
+
+	return result
+}
+
+func (qf QFrame) windowColumn(fn WindowFunc, partitions []index.Int, tieComparables []column.Comparable, dataLen int) (column.Column, error) {
+	switch fn.Fn {
+	case "row_number", "rank", "dense_rank":
+		data := make([]int, dataLen)
+		for _, p := range partitions {
+			rankPartition(fn.Fn, p, tieComparables, func(i int, rank int) { data[p[i]] = rank })
+		}
+		return icolumn.New(data), nil
+	case "percent_rank":
+		data := make([]float64, dataLen)
+		for _, p := range partitions {
+			rankPartition("rank", p, tieComparables, func(i int, rank int) {
+				if len(p) > 1 {
+					data[p[i]] = float64(rank-1) / float64(len(p)-1)
+				}
+			})
+		}
+		return fcolumn.New(data), nil
+	}
+
+	namedColumn, ok := qf.columnsByName[fn.Column]
+	if !ok {
+		return nil, errors.New("windowColumn", unknownCol(fn.Column))
+	}
+
+	switch fn.Fn {
+	case "cumsum", "cummax":
+		return cumulative(fn.Fn, namedColumn.Column, partitions, dataLen)
+	case "lag", "lead":
+		if fn.Offset < 0 {
+			return nil, errors.New("windowColumn", "offset must not be negative: %d", fn.Offset)
+		}
+
+		offset := fn.offset()
+		if fn.Fn == "lead" {
+			offset = -offset
+		}
+
+		ix := make(index.Int, dataLen)
+		for _, p := range partitions {
+			for i, pos := range p {
+				if j := i - offset; j >= 0 && j < len(p) {
+					ix[pos] = p[j]
+				} else {
+					ix[pos] = noMatch
+				}
+			}
+		}
+		return nullableSubset(namedColumn.Column, ix)
+	default:
+		return nil, errors.New("windowColumn", "unknown window function: %s", fn.Fn)
+	}
+}
+
+// rankPartition calls setRank for each position i in the sorted partition p with the rank of that row.
+func rankPartition(fn string, p index.Int, tieComparables []column.Comparable, setRank func(i int, rank int)) {
+	rank, denseRank := 0, 0
+	for i := range p {
+		if i == 0 || !equalRows(p[i-1], p[i], tieComparables) {
+			rank = i + 1
+			denseRank++
+		}
+
+		switch fn {
+		case "row_number":
+			setRank(i, i+1)
+		case "rank":
+			setRank(i, rank)
+		case "dense_rank":
+			setRank(i, denseRank)
+		}
+	}
+}
+
+func equalRows(i, j uint32, comparables []column.Comparable) bool {
+	for _, c := range comparables {
+		if c.Compare(i, j) != column.Equal {
+			return false
+		}
+	}
+	return true
+}
+
+func cumulative(fn string, col column.Column, partitions []index.Int, dataLen int) (column.Column, error) {
+	switch c := col.(type) {
+	case icolumn.Column:
+		data, valid := make([]int, dataLen), bitmap.New(dataLen, true)
+		for _, p := range partitions {
+			view := c.View(p)
+			acc, accNull := 0, true
+			for i, pos := range p {
+				if view.IsNull(i) {
+					valid.SetNull(pos)
+					continue
+				}
+
+				v := view.ItemAt(i)
+				if accNull {
+					acc, accNull = v, false
+				} else if fn == "cumsum" {
+					acc += v
+				} else if v > acc {
+					acc = v
+				}
+				data[pos] = acc
+			}
+		}
+		return icolumn.NewNullable(data, valid), nil
+	case fcolumn.Column:
+		data := make([]float64, dataLen)
+		for _, p := range partitions {
+			view := c.View(p)
+			acc := math.NaN()
+			for i, pos := range p {
+				v := view.ItemAt(i)
+				if math.IsNaN(v) {
+					data[pos] = v
+					continue
+				}
+
+				if math.IsNaN(acc) {
+					acc = v
+				} else if fn == "cumsum" {
+					acc += v
+				} else if v > acc {
+					acc = v
+				}
+				data[pos] = acc
+			}
+		}
+		return fcolumn.New(data), nil
+	default:
+		return nil, errors.New("cumulative", "%s not supported for column type: %s", fn, col.DataType())
+	}
+}